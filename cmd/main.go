@@ -8,6 +8,15 @@ import (
 )
 
 func main() {
+	// 带参数时作为控制命令执行，向正在运行的实例发送请求
+	if len(os.Args) > 1 {
+		if err := app.RunCommand(os.Args[1:]); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 调用应用程序的启动函数
 	if err := app.RunApplication(); err != nil {
 		// 如果启动或运行过程中发生错误，输出错误信息
@@ -6,25 +6,52 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"syscall"
 
+	"github.com/lyj404/win-path-convert/internal/autostart"
 	"github.com/lyj404/win-path-convert/internal/clipboard"
+	"github.com/lyj404/win-path-convert/internal/clock"
 	"github.com/lyj404/win-path-convert/internal/config"
+	"github.com/lyj404/win-path-convert/internal/history"
+	"github.com/lyj404/win-path-convert/internal/hotkey"
 	"github.com/lyj404/win-path-convert/internal/interfaces"
+	"github.com/lyj404/win-path-convert/internal/ipc"
 	"github.com/lyj404/win-path-convert/internal/logger"
+	"github.com/lyj404/win-path-convert/internal/notify"
 	"github.com/lyj404/win-path-convert/internal/pathconv"
 	"github.com/lyj404/win-path-convert/internal/singleton"
+	"github.com/lyj404/win-path-convert/internal/suspend"
 )
 
 // PathConvertApp 聚合应用依赖与运行状态
 type PathConvertApp struct {
-	cfg    *config.Config               // 应用配置对象，包含用户设置的各种参数
-	log    *logger.Logger               // 日志记录器，用于输出应用运行信息
-	cb     interfaces.IClipboardManager // 剪贴板管理器，负责监听和操作剪贴板
-	pc     interfaces.IPathConverter    // 路径转换器，负责将Windows路径转换为Unix风格路径
-	ctx    context.Context              // 上下文对象，用于协程间的通知和取消
-	cancel context.CancelFunc           // 取消函数，用于通知所有协程停止运行
-	sigCh  chan os.Signal               // 信号通道，用于接收操作系统信号（如Ctrl+C）
+	cfg            *config.Config               // 应用配置对象，包含用户设置的各种参数
+	cfgPath        string                       // 配置文件路径，用于重新加载配置
+	log            *logger.Logger               // 日志记录器，用于输出应用运行信息
+	cb             interfaces.IClipboardManager // 剪贴板管理器，负责监听和操作剪贴板
+	pc             interfaces.IPathConverter    // 路径转换器，负责将Windows路径转换为Unix风格路径
+	ctx            context.Context              // 上下文对象，用于协程间的通知和取消
+	cancel         context.CancelFunc           // 取消函数，用于通知所有协程停止运行
+	sigCh          chan os.Signal               // 信号通道，用于接收操作系统信号（如Ctrl+C）
+	control        *ipc.Server                  // 控制通道服务端，接收其他进程发来的命令
+	mode           string                       // 当前剪贴板监听方式（监听模式或轮询模式）
+	clock          clock.Clock                  // 时间源，驱动定时暂停的自动恢复
+	suspend        *suspend.Controller          // 自动转换的暂停与定时暂停状态
+	hwnd           uintptr                      // 监听模式下的隐藏窗口句柄，轮询模式下为0
+	hotkeys        []hotkey.Binding             // 已注册的全局热键，仅在消息循环线程中访问
+	history        *history.Store               // 最近的转换记录
+	rotation       *history.Rotation            // 输出格式轮换状态
+	trayWnd        atomic.Uintptr               // 托盘图标所属窗口句柄，未显示图标时为0
+	notifier       *notify.Dispatcher           // 转换通知分发器，负责合并短时间内的多次通知
+	taskbarCreated uint32                       // TaskbarCreated消息编号，仅在消息循环线程中访问
+	targets        *pathconv.TargetMatcher      // 目标程序到输出格式的匹配规则，为nil时总是使用默认格式
+	render         delayedRender                // 延迟渲染的待渲染内容
+	pinnedDir      string                       // pin命令固定的项目目录，非空时代替配置的base_dir
+	registrar      autostart.Registrar          // 开机自启动注册器，用于状态查询
+
+	mu sync.Mutex // 串行化剪贴板处理与配置更新，消息循环和控制通道可能并发访问
 }
 
 // NewPathConvertApp 创建应用实例
@@ -38,21 +65,28 @@ type PathConvertApp struct {
 func NewPathConvertApp(cfg *config.Config, log *logger.Logger) *PathConvertApp {
 	// 创建上下文和对应的取消函数，用于优雅地关闭应用程序
 	ctx, cancel := context.WithCancel(context.Background())
+	clk := clock.System()
 	return &PathConvertApp{
-		cfg:    cfg,
-		log:    log,
-		cb:     clipboard.NewClipboardManager(), // 初始化剪贴板管理器
-		ctx:    ctx,
-		cancel: cancel,
-		sigCh:  make(chan os.Signal, 1), // 创建信号通道，缓冲大小为1，防止信号丢失
+		cfg:       cfg,
+		cfgPath:   config.DefaultConfigPath(),
+		log:       log,
+		cb:        clipboard.NewClipboardManager(), // 初始化剪贴板管理器
+		ctx:       ctx,
+		cancel:    cancel,
+		sigCh:     make(chan os.Signal, 1), // 创建信号通道，缓冲大小为1，防止信号丢失
+		clock:     clk,
+		suspend:   suspend.NewController(clk),
+		history:   history.NewStore(cfg.HistorySize),
+		registrar: newRegistrar(),
 	}
 }
 
 // Initialize 初始化组件
 // 该函数负责初始化应用程序运行所需的各种组件
 // 执行内容:
-//  1. 初始化路径转换器，加载排除模式配置
+//  1. 初始化路径转换器，加载排除模式和输出格式配置
 //  2. 设置信号监听，捕获SIGINT和SIGTERM信号
+//  3. 启动控制通道，接收其他进程发来的命令
 //
 // 返回值:
 //   - error: 初始化过程中可能发生的错误
@@ -60,8 +94,37 @@ func (a *PathConvertApp) Initialize() error {
 	a.log.Info("初始化Windows路径转换工具...")
 	// 创建路径转换器实例，传入排除模式和日志记录器
 	a.pc = pathconv.NewPathConverter(a.cfg.ExcludePatterns, a.log)
+	// 应用输出格式等转换选项
+	a.applyConverterOptions()
+	// 创建转换通知分发器：优先显示托盘气泡通知，托盘图标不可用时写入日志
+	a.notifier = notify.NewDispatcher(
+		notify.Fallback(&balloonNotifier{app: a}, notify.NewLogNotifier(a.log)),
+		a.notifyFormatter(),
+		a.clock,
+		notifyWindow,
+	)
+	a.notifier.SetOnError(func(err error) {
+		a.log.Debug("发送转换通知失败: %v", err)
+	})
+	// 自动转换关闭时以暂停状态启动，之后可通过控制命令恢复
+	if !a.cfg.AutoConvert {
+		a.suspend.Pause()
+	}
+	// 定时暂停到期时记录日志并刷新托盘提示，便于用户确认自动转换已恢复
+	// 回调可能在持有a.mu的剪贴板处理过程中触发，托盘刷新需要在新协程中进行
+	a.suspend.SetOnChange(func(s suspend.State) {
+		a.log.Info("暂停已结束，自动转换已恢复")
+		go a.updateTray()
+	})
 	// 注册信号监听，捕获SIGINT(Ctrl+C)和SIGTERM信号
 	signal.Notify(a.sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	// 启动控制通道，失败时仅记录警告，不影响主要功能
+	a.control = ipc.NewServer(ipc.EndpointFor(a.cfg.PipeName), ipc.HandlerFunc(a.handleControlRequest), a.log)
+	if err := a.control.Start(a.ctx); err != nil {
+		a.log.Warn("无法启动控制通道: %v", err)
+		a.control = nil
+	}
 	return nil
 }
 
@@ -69,14 +132,24 @@ func (a *PathConvertApp) Initialize() error {
 // 该函数负责在应用程序退出前释放所有资源
 // 执行内容:
 //  1. 取消上下文，通知所有协程停止运行
-//  2. 释放单例模式资源
-//  3. 关闭日志记录器
+//  2. 关闭控制通道
+//  3. 发送尚未发送的合并通知
+//  4. 释放单例模式资源
+//  5. 关闭日志记录器
 func (a *PathConvertApp) Cleanup() {
 	a.log.Info("正在清理资源...")
 	// 调用取消函数，通知所有监听ctx.Done()的协程退出
 	if a.cancel != nil {
 		a.cancel()
 	}
+	// 关闭控制通道，等待进行中的命令处理完毕
+	if a.control != nil {
+		a.control.Close()
+	}
+	// 停止通知合并窗口，避免退出后仍有定时器触发
+	if a.notifier != nil {
+		a.notifier.Close()
+	}
 	// 释放单例锁，允许下一个程序实例启动
 	singleton.ReleaseSingleton()
 	// 关闭日志记录器，确保日志信息被写入文件
@@ -122,18 +195,43 @@ func (a *PathConvertApp) Run() error {
 // 返回值:
 //   - error: 运行过程中可能发生的错误
 func RunApplication() error {
+	return runApplication(runOptions{})
+}
+
+// runOptions 应用程序的运行方式
+type runOptions struct {
+	background bool                    // 是否为没有控制台的后台进程，后台进程的日志总是写入文件
+	ctx        context.Context         // 外部上下文，取消时应用程序退出；为nil时只响应信号和quit命令
+	started    func(a *PathConvertApp) // 应用程序初始化完成后的回调，可以为nil
+}
+
+// runApplication 启动并运行应用程序
+// 参数:
+//   - opts: 运行方式
+//
+// 返回值:
+//   - error: 运行过程中可能发生的错误
+func runApplication(opts runOptions) error {
 	// 平台检查，确保程序只在Windows系统上运行
 	if runtime.GOOS != "windows" {
 		return fmt.Errorf("此程序只能在Windows系统上运行")
 	}
 
-	// 加载默认配置
-	cfg := config.DefaultConfig()
+	// 加载配置文件，文件不存在时使用默认配置
+	cfgPath := config.DefaultConfigPath()
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		return err
+	}
+	if opts.background {
+		discardConsoleOutput()
+		cfg.LogFile = backgroundLogFile(cfg)
+	}
 	// 设置单例模式的互斥锁名称（防止多个实例同时运行）
 	singleton.SetMutexName(cfg.MutexName)
 	// 尝试初始化单例（获取全局锁）
 	if !singleton.InitSingleton() {
-		return fmt.Errorf("程序已在运行中")
+		return fmt.Errorf("程序已在运行中，可使用 status、pause、resume、snooze、reload、quit、convert-now 子命令控制")
 	}
 	// 确保退出时释放单例锁
 	defer singleton.ReleaseSingleton()
@@ -144,9 +242,17 @@ func RunApplication() error {
 	defer config.CloseLogger()
 	// 使用全局日志实例
 	appLogger := config.GlobalLogger
+	// 配置了日志文件时同时写入文件，托盘菜单可直接打开该文件
+	if cfg.LogFile != "" {
+		if err := config.SetLogFile(cfg.LogFile); err != nil {
+			appLogger.Warn("无法打开日志文件: %v", err)
+			cfg.LogFile = ""
+		}
+	}
 
 	// 创建应用程序实例
 	app := NewPathConvertApp(cfg, appLogger)
+	app.cfgPath = cfgPath
 	// 初始化应用程序组件
 	if err := app.Initialize(); err != nil {
 		appLogger.Error("应用程序初始化失败: %v", err)
@@ -154,13 +260,24 @@ func RunApplication() error {
 	}
 	// 确保退出时清理资源
 	defer app.Cleanup()
+	// 外部上下文取消时（例如服务被停止）通知应用程序退出
+	if opts.ctx != nil {
+		stop := context.AfterFunc(opts.ctx, app.cancel)
+		defer stop()
+	}
+	if opts.started != nil {
+		opts.started(app)
+	}
 
 	// 输出应用程序启动信息
 	appLogger.Info("Windows路径自动转换工具已启动")
 	appLogger.Info("复制包含反斜杠的路径时，将自动转换为正斜杠格式")
+	appLogger.Info("配置文件: %s", cfgPath)
 	appLogger.Info("日志级别: %s", cfg.LogLevel)
+	appLogger.Info("输出格式: %s", cfg.Dialect)
 	appLogger.Info("自动转换: %t", cfg.AutoConvert)
 	appLogger.Info("显示通知: %t", cfg.ShowNotifications)
+	appLogger.Info("显示托盘图标: %t", cfg.ShowTrayIcon)
 	appLogger.Info("按Ctrl+C或Ctrl+Break退出程序")
 
 	// 运行应用程序主循环
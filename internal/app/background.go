@@ -0,0 +1,132 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/lyj404/win-path-convert/internal/autostart"
+	"github.com/lyj404/win-path-convert/internal/config"
+	"github.com/lyj404/win-path-convert/internal/ipc"
+	"github.com/lyj404/win-path-convert/internal/winapi"
+)
+
+// 本地子命令，不需要正在运行的实例
+const (
+	cmdInstall     = "install"      // 注册开机自启动
+	cmdUninstall   = "uninstall"    // 取消开机自启动
+	flagBackground = "--background" // 脱离控制台在后台运行
+	flagDetached   = "--detached"   // 内部参数，直接以后台模式运行，由--background和开机自启动使用
+)
+
+// defaultLogFileName 后台运行且未配置日志文件时使用的日志文件名，位于配置目录下
+const defaultLogFileName = "win-path-convert.log"
+
+// newRegistrar 创建开机自启动注册器
+func newRegistrar() autostart.Registrar {
+	return autostart.NewRunKey(autostart.DefaultName)
+}
+
+// installAutostart 注册开机自启动，登录时以后台模式启动程序
+// 同目录下有图形子系统的版本（见autostart.GUIVariant）时注册该版本，登录时不会出现控制台窗口
+// 参数:
+//   - r: 自启动注册器
+//
+// 返回值:
+//   - error: 获取程序路径或写入注册表失败时返回错误
+func installAutostart(r autostart.Registrar) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("无法获取程序路径: %v", err)
+	}
+	command, gui, err := autostart.Enable(r, exe, flagDetached)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已注册开机自启动: %s\n", command)
+	if !gui {
+		fmt.Printf("提示: 未找到 %s，登录时会短暂出现控制台窗口；可使用 go build -ldflags -H=windowsgui 编译该文件后重新执行 install\n",
+			filepath.Base(autostart.GUIVariant(exe)))
+	}
+	return nil
+}
+
+// uninstallAutostart 取消开机自启动
+// 参数:
+//   - r: 自启动注册器
+//
+// 返回值:
+//   - error: 删除注册表项失败时返回错误
+func uninstallAutostart(r autostart.Registrar) error {
+	if err := r.Uninstall(); err != nil {
+		return err
+	}
+	fmt.Println("已取消开机自启动")
+	return nil
+}
+
+// startBackground 以脱离控制台的新进程启动程序，当前进程随即退出
+// 关闭启动它的终端不会影响后台进程，之后可通过托盘图标或控制命令操作
+// 返回值:
+//   - error: 程序已在运行或无法创建进程时返回错误
+func startBackground() error {
+	cfg, err := config.LoadConfig(config.DefaultConfigPath())
+	if err != nil {
+		return err
+	}
+	// 单例检查在子进程中进行，失败时无处输出错误，这里提前确认没有正在运行的实例
+	_, err = ipc.Send(ipc.EndpointFor(cfg.PipeName), ipc.NewRequest(ipc.CmdStatus), ipc.DefaultTimeout)
+	switch {
+	case err == nil:
+		return fmt.Errorf("程序已在运行中")
+	case !errors.Is(err, ipc.ErrNotRunning):
+		return fmt.Errorf("无法确认程序是否已在运行: %v", err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("无法获取程序路径: %v", err)
+	}
+	cmd := exec.Command(exe, flagDetached)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP,
+		HideWindow:    true,
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("无法启动后台进程: %v", err)
+	}
+	fmt.Printf("已在后台启动 (PID %d)，日志文件: %s\n", cmd.Process.Pid, backgroundLogFile(cfg))
+	return cmd.Process.Release()
+}
+
+// runDetached 后台进程的入口
+// 以控制台版本直接启动时（如开机自启动未找到图形子系统的版本），立即释放系统为其创建的控制台，窗口随之关闭
+// 返回值:
+//   - error: 运行过程中发生的错误
+func runDetached() error {
+	winapi.ProcFreeConsole.Call()
+	return runApplication(runOptions{background: true})
+}
+
+// discardConsoleOutput 丢弃标准输出和标准错误
+// 后台进程和服务没有控制台，写标准输出会失败，并导致同时写入的日志文件也收不到内容
+func discardConsoleOutput() {
+	if devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0); err == nil {
+		os.Stdout = devNull
+		os.Stderr = devNull
+	}
+}
+
+// backgroundLogFile 返回后台运行时使用的日志文件
+// 优先使用配置的log_file，未配置时使用配置目录下的默认文件
+func backgroundLogFile(cfg *config.Config) string {
+	if cfg.LogFile != "" {
+		return cfg.LogFile
+	}
+	return filepath.Join(filepath.Dir(config.DefaultConfigPath()), defaultLogFileName)
+}
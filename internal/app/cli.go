@@ -0,0 +1,141 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/lyj404/win-path-convert/internal/autostart"
+	"github.com/lyj404/win-path-convert/internal/config"
+	"github.com/lyj404/win-path-convert/internal/ipc"
+)
+
+// controlCommands 通过控制通道发送给正在运行实例的子命令
+var controlCommands = map[string]string{
+	ipc.CmdStatus:     "查看正在运行的实例状态",
+	ipc.CmdPause:      "暂停自动转换",
+	ipc.CmdResume:     "恢复自动转换",
+	ipc.CmdSnooze:     "暂停自动转换一段时间，如 snooze 15、snooze 1h、snooze 3 copies",
+	ipc.CmdReload:     "重新加载配置文件",
+	ipc.CmdQuit:       "退出正在运行的实例",
+	ipc.CmdConvertNow: "立即转换当前剪贴板内容",
+	ipc.CmdPin:        "固定项目目录，相对路径按其解析，如 pin、pin D:\\src\\app（默认为当前目录）",
+	ipc.CmdUnpin:      "取消固定的项目目录，恢复使用配置的 base_dir",
+}
+
+// localCommands 在当前进程中直接执行、不需要正在运行实例的子命令
+var localCommands = map[string]string{
+	cmdInstall:     "注册开机自启动，登录时以后台模式启动",
+	cmdUninstall:   "取消开机自启动",
+	flagBackground: "脱离控制台在后台运行，日志写入文件",
+	cmdService:     "管理Windows服务: service install|remove|start|stop（需要管理员权限）",
+}
+
+// RunCommand 执行命令行子命令
+// 本地子命令直接执行，其余子命令通过控制通道发送给正在运行的实例，并输出执行结果
+// 参数:
+//   - args: 命令行参数（不含程序名），第一个元素为子命令名称
+//
+// 返回值:
+//   - error: 子命令无效、无法连接或执行失败时返回错误
+func RunCommand(args []string) error {
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage()
+		return nil
+	}
+	switch name {
+	case cmdInstall:
+		return installAutostart(newRegistrar())
+	case cmdUninstall:
+		return uninstallAutostart(newRegistrar())
+	case flagBackground:
+		return startBackground()
+	case flagDetached:
+		return runDetached()
+	case cmdService:
+		return runServiceCommand(args[1:])
+	}
+	if _, ok := controlCommands[name]; !ok {
+		printUsage()
+		return fmt.Errorf("未知命令: %s", name)
+	}
+
+	cfg, err := config.LoadConfig(config.DefaultConfigPath())
+	if err != nil {
+		return err
+	}
+
+	// pin的目录按发送命令的进程的当前目录解析，不带参数时固定当前目录
+	if name == ipc.CmdPin {
+		dir := "."
+		if len(args) > 1 {
+			dir = args[1]
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("无法解析目录 %s: %v", dir, err)
+		}
+		args = []string{name, abs}
+	}
+
+	resp, err := ipc.Send(ipc.EndpointFor(cfg.PipeName), ipc.NewRequest(name, args[1:]...), ipc.DefaultTimeout)
+	if err != nil {
+		if errors.Is(err, ipc.ErrNotRunning) {
+			// 没有正在运行的实例时，status仍可报告开机自启动的状态
+			if name == ipc.CmdStatus {
+				printResponse(ipc.Response{
+					Message: "程序未运行",
+					Data:    map[string]string{"autostart": autostart.Describe(newRegistrar())},
+				})
+				return nil
+			}
+			return fmt.Errorf("%v，请先启动程序", err)
+		}
+		return err
+	}
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+
+	printResponse(resp)
+	return nil
+}
+
+// printResponse 输出控制命令的执行结果
+// 附加数据按键名排序输出，保证多次执行结果顺序一致
+func printResponse(resp ipc.Response) {
+	if resp.Message != "" {
+		fmt.Println(resp.Message)
+	}
+	keys := make([]string, 0, len(resp.Data))
+	for k := range resp.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("  %-10s %s\n", k+":", resp.Data[k])
+	}
+}
+
+// printUsage 输出子命令帮助信息
+func printUsage() {
+	fmt.Println("用法: win-path-convert [命令]")
+	fmt.Println("不带命令时启动路径转换程序，可用命令:")
+	printCommands(localCommands)
+	fmt.Println("控制正在运行的实例:")
+	printCommands(controlCommands)
+}
+
+// printCommands 按名称顺序输出命令及其说明
+func printCommands(commands map[string]string) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  %-14s %s\n", name, commands[name])
+	}
+}
@@ -2,20 +2,26 @@ package app
 
 import (
 	"github.com/lyj404/win-path-convert/internal/clipboard"
+	"github.com/lyj404/win-path-convert/internal/history"
+	"github.com/lyj404/win-path-convert/internal/notify"
 )
 
 // processClipboardChange 处理剪贴板变化
 // 这是剪贴板处理的核心函数，负责检查、转换并更新剪贴板内容
 // 执行流程:
-//  1. 检查自动转换是否启用
-//  2. 获取当前剪贴板内容
+//  1. 获取当前剪贴板内容
+//  2. 检查自动转换是否暂停
 //  3. 检查是否需要转换
 //  4. 执行转换并更新剪贴板
 func (a *PathConvertApp) processClipboardChange() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
 	a.log.Debug("检测到剪贴板变化")
-	// 检查用户是否禁用了自动转换功能
-	if !a.cfg.AutoConvert {
-		a.log.Debug("自动转换已禁用，忽略变化")
+
+	// 延迟渲染登记的内容由本程序占有，读取会触发渲染，必须在读取之前跳过
+	if a.cb.IsOwner(a.hwnd) {
+		a.log.Debug("剪贴板由本程序延迟渲染，跳过处理")
 		return
 	}
 
@@ -35,6 +41,14 @@ func (a *PathConvertApp) processClipboardChange() {
 		return
 	}
 
+	// 检查自动转换是否被禁用或暂停
+	// 放在哈希检查之后，保证程序自身写入剪贴板不会消耗"跳过N次复制"的次数
+	if !a.suspend.Allow() {
+		a.log.Debug("自动转换已暂停，忽略变化")
+		a.cb.SetLastContentHash(currentHash)
+		return
+	}
+
 	// 检查内容是否需要转换（路径转换器会判断内容是否包含Windows路径）
 	if !a.pc.ShouldConvert(rawText) {
 		a.log.Debug("不需要转换的内容: %s", a.log.ShortenText(rawText))
@@ -43,31 +57,112 @@ func (a *PathConvertApp) processClipboardChange() {
 		return
 	}
 
+	// 启用延迟渲染时，等目标程序读取剪贴板时再决定输出格式
+	if a.deferConversion(rawText) {
+		a.cb.SetLastContentHash(currentHash)
+		return
+	}
+
 	// 执行路径转换
 	converted := a.pc.Convert(rawText)
 	// 检查转换是否改变了内容（防止设置相同内容导致循环触发）
 	if converted != rawText {
-		// 将转换后的内容设置回剪贴板
-		if err := a.cb.SetText(converted); err != nil {
-			a.log.Error("无法设置剪贴板内容: %v", err)
-			return
-		}
-
-		// 根据用户配置决定是否显示转换通知
-		if a.cfg.ShowNotifications {
-			a.log.Info("已转换路径:")
-			a.log.Info("  原路径: %s", rawText)
-			a.log.Info("  转换后: %s", converted)
-		} else {
-			a.log.Debug("已转换路径，但不显示通知")
-		}
-
-		// 更新最后处理的哈希值（复用已计算的转换后内容的哈希，避免重复计算）
-		convertedHash := clipboard.QuickHash(converted)
-		a.cb.SetLastContentHash(convertedHash)
+		a.replaceClipboard(rawText, converted)
 		return
 	}
 
 	// 内容不需要转换，但更新哈希值以避免下次重复检查
 	a.cb.SetLastContentHash(currentHash)
 }
+
+// convertNow 立即转换当前剪贴板内容
+// 与processClipboardChange不同，该函数忽略暂停状态和内容哈希检查，
+// 供控制命令等用户主动触发的场景使用，调用方不得持有a.mu
+// 返回值:
+//   - string: 转换后的剪贴板内容，未转换时为原内容
+//   - bool: 剪贴板内容是否被修改
+//   - error: 读取或写入剪贴板时发生的错误
+func (a *PathConvertApp) convertNow() (string, bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rawText, err := a.cb.GetText()
+	if err != nil {
+		return "", false, err
+	}
+
+	if !a.pc.ShouldConvert(rawText) {
+		return rawText, false, nil
+	}
+
+	converted := a.pc.Convert(rawText)
+	if converted == rawText {
+		return rawText, false, nil
+	}
+	if err := a.replaceClipboard(rawText, converted); err != nil {
+		return rawText, false, err
+	}
+	return converted, true, nil
+}
+
+// reverseNow 将剪贴板中的目标路径（如映射后的Linux路径、WSL路径）转换回Windows路径
+// 写入剪贴板后同步更新内容哈希，不会再次触发自动转换；反向转换不加入转换记录
+func (a *PathConvertApp) reverseNow() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rawText, err := a.cb.GetText()
+	if err != nil {
+		a.log.Warn("无法获取剪贴板内容: %v", err)
+		return
+	}
+	converted := a.pc.ConvertReverse(rawText)
+	if converted == rawText {
+		a.log.Info("剪贴板中没有可反向转换的路径")
+		return
+	}
+	if err := a.cb.SetText(converted); err != nil {
+		a.log.Error("无法设置剪贴板内容: %v", err)
+		return
+	}
+	a.cb.SetLastContentHash(clipboard.QuickHash(converted))
+	a.log.Info("已反向转换: %s", a.log.ShortenText(converted))
+}
+
+// replaceClipboard 将转换结果写回剪贴板并记录
+// 写入成功后更新内容哈希，防止写入操作本身再次触发转换，并把本次转换加入转换记录
+// 参数:
+//   - rawText: 转换前的内容
+//   - converted: 转换后的内容
+//
+// 返回值:
+//   - error: 写入剪贴板时发生的错误
+func (a *PathConvertApp) replaceClipboard(rawText, converted string) error {
+	// 将转换后的内容设置回剪贴板
+	if err := a.cb.SetText(converted); err != nil {
+		a.log.Error("无法设置剪贴板内容: %v", err)
+		return err
+	}
+
+	// 根据用户配置决定是否显示转换通知
+	a.log.Debug("已转换路径: %s -> %s", rawText, converted)
+	if a.cfg.ShowNotifications {
+		a.notifier.Post(notify.Event{
+			Original:  rawText,
+			Converted: converted,
+			Dialect:   string(a.pc.Dialect()),
+		})
+	}
+
+	// 更新最后处理的哈希值，写入剪贴板会再次触发变化事件，届时据此跳过
+	a.cb.SetLastContentHash(clipboard.QuickHash(converted))
+
+	// 记录原始内容，供格式轮换等功能使用
+	a.history.Add(history.Entry{
+		Original:  rawText,
+		Converted: converted,
+		Dialect:   string(a.pc.Dialect()),
+		Time:      a.clock.Now(),
+	})
+	return nil
+}
@@ -0,0 +1,225 @@
+package app
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/autostart"
+	"github.com/lyj404/win-path-convert/internal/config"
+	"github.com/lyj404/win-path-convert/internal/ipc"
+	"github.com/lyj404/win-path-convert/internal/logger"
+	"github.com/lyj404/win-path-convert/internal/pathconv"
+	"github.com/lyj404/win-path-convert/internal/suspend"
+	"github.com/lyj404/win-path-convert/internal/winapi"
+)
+
+// 剪贴板监听方式，用于状态查询
+const (
+	modeListener = "listener" // 剪贴板监听API模式
+	modePolling  = "polling"  // 轮询模式
+)
+
+// handleControlRequest 处理控制通道收到的命令
+// 该函数在控制通道的协程中执行，访问共享状态时需要注意并发安全
+// 参数:
+//   - req: 控制请求
+//
+// 返回值:
+//   - ipc.Response: 命令执行结果
+func (a *PathConvertApp) handleControlRequest(req ipc.Request) ipc.Response {
+	switch req.Command {
+	case ipc.CmdStatus:
+		return ipc.OKResponse("程序正在运行", a.statusData())
+
+	case ipc.CmdPause:
+		a.pause()
+		return ipc.OKResponse("自动转换已暂停", nil)
+
+	case ipc.CmdResume:
+		a.resume()
+		return ipc.OKResponse("自动转换已恢复", nil)
+
+	case ipc.CmdSnooze:
+		d, n, err := suspend.ParseSnoozeArgs(req.Args)
+		if err != nil {
+			return ipc.ErrorResponse("%v", err)
+		}
+		if err := a.snooze(d, n); err != nil {
+			return ipc.ErrorResponse("%v", err)
+		}
+		return ipc.OKResponse("自动转换"+a.suspend.State().String(), nil)
+
+	case ipc.CmdReload:
+		if err := a.reloadConfig(); err != nil {
+			a.log.Error("重新加载配置失败: %v", err)
+			return ipc.ErrorResponse("重新加载配置失败: %v", err)
+		}
+		return ipc.OKResponse("配置已重新加载: "+a.cfgPath, nil)
+
+	case ipc.CmdQuit:
+		a.log.Info("收到退出命令")
+		a.cancel()
+		return ipc.OKResponse("程序正在退出", nil)
+
+	case ipc.CmdConvertNow:
+		text, changed, err := a.convertNow()
+		if err != nil {
+			return ipc.ErrorResponse("转换失败: %v", err)
+		}
+		if !changed {
+			return ipc.OKResponse("剪贴板内容无需转换", nil)
+		}
+		return ipc.OKResponse("已转换: "+text, nil)
+
+	case ipc.CmdPin:
+		if len(req.Args) != 1 {
+			return ipc.ErrorResponse("用法: pin [目录]")
+		}
+		if err := a.pin(req.Args[0]); err != nil {
+			return ipc.ErrorResponse("%v", err)
+		}
+		return ipc.OKResponse("已固定项目目录: "+req.Args[0], nil)
+
+	case ipc.CmdUnpin:
+		a.pin("")
+		return ipc.OKResponse("已取消固定的项目目录", nil)
+	}
+	return ipc.ErrorResponse("未知命令: %s", req.Command)
+}
+
+// statusData 收集运行状态信息
+// 返回值:
+//   - map[string]string: 状态键值对
+func (a *PathConvertApp) statusData() map[string]string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return map[string]string{
+		"pid":       strconv.Itoa(os.Getpid()),
+		"state":     a.suspend.State().String(),
+		"mode":      a.mode,
+		"config":    a.cfgPath,
+		"log_level": a.cfg.LogLevel,
+		"log_file":  a.cfg.LogFile,
+		"autostart": autostart.Describe(a.registrar),
+		"base_dir":  a.baseDir(),
+	}
+}
+
+// baseDir 返回当前解析相对路径的基准目录，用于状态查询
+// 调用方需持有a.mu
+func (a *PathConvertApp) baseDir() string {
+	if a.pinnedDir != "" {
+		return a.pinnedDir + "（已固定）"
+	}
+	return a.cfg.BaseDir
+}
+
+// pin 固定项目目录作为相对路径的基准目录，重新加载配置后仍然有效
+// 参数:
+//   - dir: 项目目录，为空时取消固定，恢复使用配置的base_dir
+//
+// 返回值:
+//   - error: 目录不是绝对路径时返回错误
+func (a *PathConvertApp) pin(dir string) error {
+	if err := pathconv.ValidateBaseDir(dir); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.pinnedDir = dir
+	a.applyBaseDir()
+	if dir == "" {
+		a.log.Info("已取消固定的项目目录")
+	} else {
+		a.log.Info("已固定项目目录: %s", dir)
+	}
+	return nil
+}
+
+// pause 暂停自动转换，直到手动恢复
+func (a *PathConvertApp) pause() {
+	a.suspend.Pause()
+	a.log.Info("自动转换已暂停")
+	a.updateTray()
+}
+
+// resume 恢复自动转换，同时取消定时暂停
+func (a *PathConvertApp) resume() {
+	a.suspend.Resume()
+	a.log.Info("自动转换已恢复")
+	a.updateTray()
+}
+
+// snooze 按时长或复制次数暂停自动转换
+// 参数:
+//   - d: 暂停时长，为0时按次数暂停
+//   - copies: 跳过的复制次数
+//
+// 返回值:
+//   - error: 参数无效时返回错误
+func (a *PathConvertApp) snooze(d time.Duration, copies int) error {
+	var err error
+	if d > 0 {
+		err = a.suspend.SnoozeFor(d)
+	} else {
+		err = a.suspend.SnoozeCopies(copies)
+	}
+	if err != nil {
+		return err
+	}
+	a.log.Info("自动转换%s", a.suspend.State())
+	a.updateTray()
+	return nil
+}
+
+// setMode 记录当前剪贴板监听方式
+// 参数:
+//   - mode: modeListener 或 modePolling
+func (a *PathConvertApp) setMode(mode string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.mode = mode
+}
+
+// setWindow 记录监听模式下的隐藏窗口句柄
+// 参数:
+//   - hwnd: 窗口句柄，窗口销毁后传入0
+func (a *PathConvertApp) setWindow(hwnd uintptr) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.hwnd = hwnd
+}
+
+// reloadConfig 从配置文件重新加载配置
+// 排除模式、输出格式、日志级别和热键立即生效；轮询间隔、互斥量名称等启动参数需要重启程序才能生效
+// 返回值:
+//   - error: 读取或解析配置文件时发生的错误
+func (a *PathConvertApp) reloadConfig() error {
+	cfg, err := config.LoadConfig(a.cfgPath)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// 保留启动时确定的参数，避免运行中的资源与配置不一致
+	cfg.PollInterval = a.cfg.PollInterval
+	cfg.MutexName = a.cfg.MutexName
+	cfg.PipeName = a.cfg.PipeName
+	cfg.LogFile = a.cfg.LogFile
+
+	a.cfg = cfg
+	a.pc.UpdateExcludePatterns(cfg.ExcludePatterns)
+	a.applyConverterOptions()
+	a.notifier.SetFormatter(a.notifyFormatter())
+	a.log.SetLevel(logger.ParseLevel(cfg.LogLevel))
+	// 热键必须在消息循环线程中重新注册，这里只投递通知消息
+	if a.hwnd != 0 {
+		winapi.ProcPostMessageW.Call(a.hwnd, WMReloadHotkeys, 0, 0)
+	}
+	a.log.Info("配置已重新加载: %s", a.cfgPath)
+	return nil
+}
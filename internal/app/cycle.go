@@ -0,0 +1,58 @@
+package app
+
+import (
+	"github.com/lyj404/win-path-convert/internal/clipboard"
+	"github.com/lyj404/win-path-convert/internal/history"
+	"github.com/lyj404/win-path-convert/internal/pathconv"
+)
+
+// cycleDialect 将剪贴板内容切换为下一个输出格式
+// 轮换基于转换记录中最近一次复制的原始内容，因此可以在各格式之间反复切换而不损失信息
+// 剪贴板内容不是最近一次转换的结果时（例如暂停期间复制的路径），把当前内容作为新的原始内容
+// 写入剪贴板后同步更新内容哈希，不会再次触发自动转换
+func (a *PathConvertApp) cycleDialect() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	current, err := a.cb.GetText()
+	if err != nil {
+		a.log.Warn("无法获取剪贴板内容: %v", err)
+		return
+	}
+
+	entry, ok := a.history.Latest()
+	if !ok || current != entry.Converted {
+		if !a.pc.ShouldConvert(current) {
+			a.log.Info("剪贴板中没有可切换格式的路径")
+			return
+		}
+		entry = history.Entry{
+			Original:  current,
+			Converted: current,
+			Dialect:   string(pathconv.DialectOriginal),
+			Time:      a.clock.Now(),
+		}
+		a.history.Add(entry)
+	}
+
+	// 原始内容或当前格式与轮换状态不一致时，从记录中的格式重新开始轮换
+	if a.rotation.Original() != entry.Original || a.rotation.Current() != entry.Dialect {
+		a.rotation.Reset(entry.Original, entry.Dialect)
+	}
+
+	dialect, text, ok := a.rotation.Next(current, func(d string) string {
+		return a.pc.ConvertTo(entry.Original, pathconv.Dialect(d))
+	})
+	if !ok {
+		a.log.Info("没有其他可切换的输出格式")
+		return
+	}
+
+	if err := a.cb.SetText(text); err != nil {
+		a.log.Error("无法设置剪贴板内容: %v", err)
+		return
+	}
+	a.cb.SetLastContentHash(clipboard.QuickHash(text))
+	a.history.UpdateLatest(text, dialect)
+	a.log.Info("已切换为 %s 格式: %s", dialect, text)
+}
@@ -0,0 +1,179 @@
+package app
+
+import (
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/lyj404/win-path-convert/internal/history"
+	"github.com/lyj404/win-path-convert/internal/notify"
+	"github.com/lyj404/win-path-convert/internal/pathconv"
+	"github.com/lyj404/win-path-convert/internal/winapi"
+)
+
+// pendingRender 等待目标程序读取的延迟渲染内容
+// 登记时预先按所有候选格式完成转换，渲染时只需选择结果，不再访问路径转换器和配置
+type pendingRender struct {
+	original string                      // 转换前的内容
+	outputs  map[pathconv.Dialect]string // 各候选输出格式的转换结果
+	fallback pathconv.Dialect            // 没有规则匹配时使用的输出格式
+	targets  *pathconv.TargetMatcher     // 登记时的目标程序匹配规则
+	notify   bool                        // 渲染后是否显示转换通知
+}
+
+// delayedRender 延迟渲染的状态
+// 窗口过程在其他程序读取剪贴板时同步渲染，此时本程序的其他协程可能正持有a.mu等待剪贴板，
+// 因此渲染只使用自己的互斥锁，避免死锁
+type delayedRender struct {
+	mu      sync.Mutex     // 保护pending
+	pending *pendingRender // 尚未渲染的内容，没有时为nil
+}
+
+// set 登记新的待渲染内容，替换尚未渲染的旧内容
+func (r *delayedRender) set(p *pendingRender) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = p
+}
+
+// take 取出待渲染的内容，每次登记的内容只渲染一次
+func (r *delayedRender) take() *pendingRender {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.pending
+	r.pending = nil
+	return p
+}
+
+// deferConversion 以延迟渲染的方式写入转换结果，由第一个读取剪贴板的程序决定输出格式
+// 只在剪贴板监听模式下可用；调用方需持有a.mu
+// 参数:
+//   - rawText: 转换前的剪贴板内容
+//
+// 返回值:
+//   - bool: 是否已登记延迟渲染，返回false时调用方应立即转换
+func (a *PathConvertApp) deferConversion(rawText string) bool {
+	if !a.cfg.DelayedRendering || a.hwnd == 0 {
+		return false
+	}
+
+	p := &pendingRender{
+		original: rawText,
+		outputs:  make(map[pathconv.Dialect]string),
+		fallback: a.pc.Dialect(),
+		targets:  a.targets,
+		notify:   a.cfg.ShowNotifications,
+	}
+	changed := false
+	for _, d := range append(a.targets.Dialects(), p.fallback) {
+		p.outputs[d] = a.pc.ConvertTo(rawText, d)
+		changed = changed || p.outputs[d] != rawText
+	}
+	if !changed {
+		return false
+	}
+
+	a.render.set(p)
+	if err := a.cb.SetDelayedText(a.hwnd); err != nil {
+		a.render.take()
+		a.log.Warn("无法登记延迟渲染，立即转换: %v", err)
+		return false
+	}
+	a.log.Debug("已登记延迟渲染: %s", a.log.ShortenText(rawText))
+	return true
+}
+
+// renderClipboard 处理WM_RENDERFORMAT消息，按前台程序选择输出格式并提供剪贴板文本
+// 在窗口过程中调用，不得获取a.mu
+func (a *PathConvertApp) renderClipboard() {
+	p := a.render.take()
+	if p == nil {
+		return
+	}
+
+	target := foregroundTarget()
+	d, ok := p.targets.Match(target)
+	if !ok {
+		d = p.fallback
+	}
+	text := p.outputs[d]
+	if err := a.cb.RenderText(text); err != nil {
+		a.log.Error("无法渲染剪贴板内容: %v", err)
+		return
+	}
+	a.log.Debug("为 %s（%s）渲染 %s 格式", target.Process, target.Title, d)
+	a.recordRender(p, d, text)
+}
+
+// renderAllClipboard 处理WM_RENDERALLFORMATS消息，窗口销毁前按默认格式提供剪贴板文本
+// 在窗口过程中调用，不得获取a.mu
+// 参数:
+//   - hwnd: 剪贴板的所有者窗口句柄
+func (a *PathConvertApp) renderAllClipboard(hwnd uintptr) {
+	p := a.render.take()
+	if p == nil {
+		return
+	}
+	text := p.outputs[p.fallback]
+	if err := a.cb.RenderAllText(hwnd, text); err != nil {
+		a.log.Error("无法渲染剪贴板内容: %v", err)
+		return
+	}
+	a.recordRender(p, p.fallback, text)
+}
+
+// recordRender 为渲染结果显示通知并加入转换记录
+// 参数:
+//   - p: 已渲染的内容
+//   - d: 使用的输出格式
+//   - text: 渲染的文本
+func (a *PathConvertApp) recordRender(p *pendingRender, d pathconv.Dialect, text string) {
+	if text == p.original {
+		return
+	}
+	if p.notify {
+		a.notifier.Post(notify.Event{
+			Original:  p.original,
+			Converted: text,
+			Dialect:   string(d),
+		})
+	}
+	a.history.Add(history.Entry{
+		Original:  p.original,
+		Converted: text,
+		Dialect:   string(d),
+		Time:      a.clock.Now(),
+	})
+}
+
+// foregroundTarget 获取前台窗口所属的程序，即正在粘贴的目标程序
+// 窗口标题使用InternalGetWindowText读取，不向目标窗口发送消息，
+// 因为目标程序此时正阻塞在GetClipboardData中等待本程序渲染
+// 返回值:
+//   - pathconv.Target: 目标程序信息，无法获取的字段为空
+func foregroundTarget() pathconv.Target {
+	var t pathconv.Target
+	hwnd, _, _ := winapi.ProcGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return t
+	}
+
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	n, _, _ := winapi.ProcInternalGetWindowText.Call(hwnd, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	t.Title = windows.UTF16ToString(buf[:n])
+
+	var pid uint32
+	winapi.ProcGetWindowThreadProcessId.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+	h, _, _ := winapi.ProcOpenProcess.Call(winapi.ProcessQueryLimitedInformation, 0, uintptr(pid))
+	if h == 0 {
+		return t
+	}
+	defer winapi.ProcCloseHandle.Call(h)
+
+	size := uint32(len(buf))
+	if ret, _, _ := winapi.ProcQueryFullProcessImageNameW.Call(h, 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size))); ret != 0 {
+		t.Process = windows.UTF16ToString(buf[:size])
+	}
+	return t
+}
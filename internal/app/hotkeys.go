@@ -0,0 +1,174 @@
+package app
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/lyj404/win-path-convert/internal/hotkey"
+	"github.com/lyj404/win-path-convert/internal/winapi"
+)
+
+// 热键动作名称，对应配置文件hotkeys中的键
+const (
+	actionConvert = "convert" // 转换当前剪贴板内容，可选自动粘贴
+	actionToggle  = "toggle"  // 暂停或恢复自动转换
+	actionCycle   = "cycle"   // 在配置的输出格式之间轮换剪贴板内容
+	actionReverse = "reverse" // 将剪贴板中的目标路径转换回Windows路径
+)
+
+// hotkeyActions 支持的热键动作及其说明
+var hotkeyActions = map[string]string{
+	actionConvert: "转换当前剪贴板内容",
+	actionToggle:  "暂停/恢复自动转换",
+	actionCycle:   "切换剪贴板路径的输出格式",
+	actionReverse: "将剪贴板路径转换回Windows格式",
+}
+
+// registerHotkeys 在隐藏窗口上注册配置中的全局热键
+// 热键与注册线程绑定，必须在运行消息循环的线程中调用（runWithClipboardListener已固定该线程）
+// 无效或注册失败的热键（如已被其他程序占用）只记录警告，不影响其他热键
+// 参数:
+//   - hwnd: 接收WM_HOTKEY消息的窗口句柄
+func (a *PathConvertApp) registerHotkeys(hwnd uintptr) {
+	a.mu.Lock()
+	spec := a.cfg.Hotkeys
+	a.mu.Unlock()
+
+	bindings, errs := hotkey.ParseBindings(spec)
+	for _, err := range errs {
+		a.log.Warn("热键配置无效: %v", err)
+	}
+
+	for _, b := range bindings {
+		if _, ok := hotkeyActions[b.Action]; !ok {
+			a.log.Warn("未知的热键动作: %s", b.Action)
+			continue
+		}
+		ret, _, err := winapi.ProcRegisterHotKey.Call(
+			hwnd,
+			uintptr(b.ID),
+			uintptr(b.Hotkey.Modifiers|hotkey.ModNoRepeat),
+			uintptr(b.Hotkey.Key),
+		)
+		if ret == 0 {
+			a.log.Warn("无法注册热键 %s（%s），可能已被其他程序占用: %v", b.Hotkey, b.Action, err)
+			continue
+		}
+		a.hotkeys = append(a.hotkeys, b)
+		a.log.Info("已注册热键 %s: %s", b.Hotkey, hotkeyActions[b.Action])
+	}
+}
+
+// unregisterHotkeys 注销所有已注册的热键
+// 参数:
+//   - hwnd: 注册热键时使用的窗口句柄
+func (a *PathConvertApp) unregisterHotkeys(hwnd uintptr) {
+	for _, b := range a.hotkeys {
+		winapi.ProcUnregisterHotKey.Call(hwnd, uintptr(b.ID))
+	}
+	a.hotkeys = nil
+}
+
+// handleHotkey 处理WM_HOTKEY消息
+// 参数:
+//   - id: 被按下热键的标识符
+func (a *PathConvertApp) handleHotkey(id int) {
+	for _, b := range a.hotkeys {
+		if b.ID != id {
+			continue
+		}
+		a.log.Debug("热键 %s 被按下: %s", b.Hotkey, b.Action)
+
+		switch b.Action {
+		case actionConvert:
+			a.convertByHotkey(b.Hotkey.Modifiers)
+		case actionToggle:
+			if a.suspend.State().Suspended() {
+				a.resume()
+			} else {
+				a.pause()
+			}
+		case actionCycle:
+			a.cycleDialect()
+		case actionReverse:
+			a.reverseNow()
+		}
+		return
+	}
+}
+
+// convertByHotkey 转换当前剪贴板内容，并根据配置自动粘贴
+// 参数:
+//   - held: 触发热键的修饰键，粘贴前需要先释放这些按键
+func (a *PathConvertApp) convertByHotkey(held hotkey.Modifiers) {
+	text, changed, err := a.convertNow()
+	if err != nil {
+		a.log.Warn("热键转换失败: %v", err)
+		return
+	}
+	if changed {
+		a.log.Debug("热键转换完成: %s", a.log.ShortenText(text))
+	}
+
+	a.mu.Lock()
+	paste := a.cfg.PasteAfterConvert
+	a.mu.Unlock()
+	if paste {
+		sendPaste(held)
+	}
+}
+
+// sendPaste 模拟Ctrl+V粘贴
+// 用户此时可能仍按着热键的修饰键，先按下Ctrl再释放其他修饰键，
+// 既避免目标窗口收到Ctrl+Alt+V之类的组合，也防止单独释放Alt激活菜单栏
+// 参数:
+//   - held: 需要释放的修饰键
+func sendPaste(held hotkey.Modifiers) {
+	inputs := []Input{keyInput(winapi.VKControl, false)}
+	if held&hotkey.ModAlt != 0 {
+		inputs = append(inputs, keyInput(winapi.VKMenu, true))
+	}
+	if held&hotkey.ModShift != 0 {
+		inputs = append(inputs, keyInput(winapi.VKShift, true))
+	}
+	if held&hotkey.ModWin != 0 {
+		inputs = append(inputs, keyInput(winapi.VKLWin, true))
+	}
+	inputs = append(inputs,
+		keyInput(winapi.VKV, false),
+		keyInput(winapi.VKV, true),
+		keyInput(winapi.VKControl, true),
+	)
+
+	// 稍作等待，让剪贴板写入在目标程序中可见
+	time.Sleep(30 * time.Millisecond)
+	winapi.ProcSendInput.Call(
+		uintptr(len(inputs)),
+		uintptr(unsafe.Pointer(&inputs[0])),
+		unsafe.Sizeof(inputs[0]),
+	)
+}
+
+// keyInput 构造一个键盘输入事件
+// 参数:
+//   - vk: 虚拟键码
+//   - up: true表示按键释放，false表示按键按下
+//
+// 返回值:
+//   - Input: 输入事件
+func keyInput(vk uint16, up bool) Input {
+	in := Input{Type: winapi.InputKeyboard}
+	in.Ki.Vk = vk
+	if up {
+		in.Ki.Flags = winapi.KeyEventFKeyUp
+	}
+	return in
+}
+
+// reloadHotkeys 重新注册热键，配置重新加载后由消息循环调用
+// 参数:
+//   - hwnd: 接收WM_HOTKEY消息的窗口句柄
+func (a *PathConvertApp) reloadHotkeys(hwnd uintptr) {
+	a.unregisterHotkeys(hwnd)
+	a.registerHotkeys(hwnd)
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"fmt"
+	"runtime"
 	"syscall"
 	"time"
 	"unsafe"
@@ -20,6 +21,11 @@ import (
 //   - error: 初始化或运行过程中可能发生的错误
 func (a *PathConvertApp) runWithClipboardListener() error {
 	a.log.Info("使用剪贴板监听模式")
+	a.setMode(modeListener)
+	// 隐藏窗口、剪贴板监听、全局热键和托盘图标都属于创建它们的线程，消息循环必须始终在该线程上运行；
+	// 以服务方式运行时应用程序在新的协程中启动，协程可能在系统线程之间迁移
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
 	// 获取当前线程ID，用于后面向特定线程发送退出消息
 	tid := getCurrentThreadID()
 
@@ -69,6 +75,25 @@ func (a *PathConvertApp) runWithClipboardListener() error {
 	// 确保退出时取消注册剪贴板监听
 	defer winapi.ProcRemoveClipboardFormatListener.Call(hwnd)
 
+	// 记录窗口句柄，供其他协程向消息循环投递消息
+	a.setWindow(hwnd)
+	defer a.setWindow(0)
+
+	// 在隐藏窗口上注册全局热键，热键按下时会收到WM_HOTKEY消息
+	a.registerHotkeys(hwnd)
+	// 确保退出时注销热键，释放被占用的组合键
+	defer a.unregisterHotkeys(hwnd)
+
+	// 添加托盘图标；资源管理器重启时会广播TaskbarCreated消息，届时需要重新添加
+	if a.cfg.ShowTrayIcon {
+		taskbarCreated, _ := syscall.UTF16PtrFromString("TaskbarCreated")
+		msg, _, _ := winapi.ProcRegisterWindowMessageW.Call(uintptr(unsafe.Pointer(taskbarCreated)))
+		a.taskbarCreated = uint32(msg)
+		a.addTrayIcon(hwnd)
+		// 确保退出时移除托盘图标，避免通知区域残留失效的图标
+		defer a.removeTrayIcon(hwnd)
+	}
+
 	// 启动一个goroutine监听退出信号，以便优雅地退出消息循环
 	// 当收到信号或上下文被取消时，向消息循环发送退出消息
 	go func(tid uint32) {
@@ -103,10 +128,16 @@ func (a *PathConvertApp) runWithClipboardListener() error {
 			break
 		}
 
-		// 检查是否是剪贴板更新消息
-		if m.Message == WMClipboardUpdate {
-			// 调用剪贴板变化处理函数
+		switch m.Message {
+		case WMClipboardUpdate:
+			// 剪贴板更新消息，调用剪贴板变化处理函数
 			a.processClipboardChange()
+		case WMHotkey:
+			// 全局热键被按下，wParam为注册时的热键标识符
+			a.handleHotkey(int(m.WParam))
+		case WMReloadHotkeys:
+			// 配置已重新加载，按新配置重新注册热键
+			a.reloadHotkeys(hwnd)
 		}
 
 		// 将虚拟键消息转换为字符消息（如键盘输入）
@@ -134,6 +165,32 @@ func (a *PathConvertApp) windowProc(hwnd uintptr, message uint32, wparam, lparam
 		// 收到窗口销毁消息，向消息循环发送退出消息
 		winapi.ProcPostQuitMessage.Call(0)
 		return 0
+	case WMRenderFormat:
+		// 其他程序读取延迟渲染的剪贴板文本，wParam为请求的格式
+		if wparam == winapi.CFUnicodeText {
+			a.renderClipboard()
+		}
+		return 0
+	case WMRenderAllFormats:
+		// 窗口即将销毁，渲染尚未读取的内容，避免剪贴板中的文本丢失
+		a.renderAllClipboard(hwnd)
+		return 0
+	case WMDestroyClipboard:
+		// 剪贴板已被清空，放弃尚未渲染的内容
+		a.render.take()
+		return 0
+	case WMTrayCallback:
+		// 托盘图标的鼠标事件，lParam为具体的鼠标消息
+		switch uint32(lparam) {
+		case winapi.WMLButtonUp, winapi.WMRButtonUp:
+			a.showTrayMenu(hwnd)
+		}
+		return 0
+	}
+	if a.taskbarCreated != 0 && message == a.taskbarCreated {
+		// 资源管理器已重启，原有托盘图标已丢失
+		a.addTrayIcon(hwnd)
+		return 0
 	}
 	// 对于未处理的消息，调用默认窗口过程函数
 	ret, _, _ := winapi.ProcDefWindowProcW.Call(hwnd, uintptr(message), wparam, lparam)
@@ -151,6 +208,10 @@ func (a *PathConvertApp) windowProc(hwnd uintptr, message uint32, wparam, lparam
 //   - error: 运行过程中可能发生的错误
 func (a *PathConvertApp) runWithPolling() error {
 	a.log.Info("使用轮询模式，间隔: %v", a.cfg.PollInterval)
+	a.setMode(modePolling)
+	if len(a.cfg.Hotkeys) > 0 {
+		a.log.Warn("轮询模式下没有消息循环，全局热键不可用")
+	}
 	// 创建定时器，按照配置的时间间隔触发
 	ticker := time.NewTicker(a.cfg.PollInterval)
 	// 确保退出时停止定时器，防止资源泄漏
@@ -0,0 +1,272 @@
+package app
+
+import (
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/history"
+	"github.com/lyj404/win-path-convert/internal/notify"
+	"github.com/lyj404/win-path-convert/internal/pathconv"
+)
+
+// notifyWindow 转换通知的合并窗口，窗口期内的多次转换合并为一条通知
+const notifyWindow = 2 * time.Second
+
+// applyConverterOptions 将配置中的转换选项应用到路径转换器
+// 无效的选项只记录警告并使用默认值，保证配置文件中的笔误不会导致程序无法启动
+// 调用方需持有a.mu或处于初始化阶段，本文件中的其他辅助函数同样如此
+func (a *PathConvertApp) applyConverterOptions() {
+	dialect, err := pathconv.ParseDialect(a.cfg.Dialect)
+	if err != nil {
+		a.log.Warn("%v，使用默认格式 %s", err, pathconv.DialectForward)
+		dialect = pathconv.DialectForward
+	}
+	a.pc.SetDialect(dialect)
+
+	a.applyUNCOptions()
+	a.applyPathMappings()
+	a.applyEnvOptions()
+	a.applyQuoting()
+	a.applyNormalization()
+	a.applyCaseOptions()
+	a.applyPrefixPolicies()
+	a.applyClassPolicies()
+	a.applyThreshold()
+	a.applyExistenceCheck()
+	a.applyBaseDir()
+	a.pc.SetListOptions(a.cfg.SplitLists, a.cfg.DropNonPaths)
+	a.applyMarkdown()
+	a.pc.SetStructured(a.cfg.Structured)
+	a.applyTargetDialects()
+
+	// 轮换列表中的无效格式被跳过
+	var cycle []string
+	for _, name := range a.cfg.CycleDialects {
+		d, err := pathconv.ParseDialect(name)
+		if err != nil {
+			a.log.Warn("轮换格式配置无效: %v", err)
+			continue
+		}
+		cycle = append(cycle, string(d))
+	}
+	a.rotation = history.NewRotation(cycle)
+}
+
+// notifyFormatter 按配置的模板创建通知正文格式化器
+// 模板无效时记录警告并使用默认模板
+func (a *PathConvertApp) notifyFormatter() *notify.Formatter {
+	f, err := notify.NewFormatter(a.cfg.NotificationTemplate)
+	if err != nil {
+		a.log.Warn("%v，使用默认模板", err)
+		f, _ = notify.NewFormatter(notify.DefaultTemplate)
+	}
+	return f
+}
+
+// applyUNCOptions 设置映射驱动器与UNC路径的转换
+// 配置中的固定映射优先，其余驱动器通过系统查询，查询与存在性检查共用超时和缓存时间
+func (a *PathConvertApp) applyUNCOptions() {
+	mode, err := pathconv.ParseUNCMode(a.cfg.UNCMode)
+	if err != nil {
+		a.log.Warn("%v，不做UNC转换", err)
+		mode = pathconv.UNCModeNone
+	}
+	static, err := pathconv.NewStaticResolver(a.cfg.UNCDrives)
+	if err != nil {
+		a.log.Warn("驱动器映射配置无效: %v", err)
+		static = nil
+	}
+
+	timeout, ttl := a.fsLookupOptions()
+	var r pathconv.Resolver = pathconv.NewSystemResolver(timeout, ttl, a.clock)
+	if static != nil {
+		r = pathconv.ChainResolvers(static, r)
+	}
+	a.pc.SetUNCResolution(mode, r)
+}
+
+// applyPathMappings 设置路径前缀映射
+// 映射规则无效时记录警告并禁用映射
+func (a *PathConvertApp) applyPathMappings() {
+	rules := make([]pathconv.Mapping, 0, len(a.cfg.PathMappings))
+	for _, m := range a.cfg.PathMappings {
+		rules = append(rules, pathconv.Mapping{Windows: m.Windows, Target: m.Target})
+	}
+	mapper, err := pathconv.NewMapper(rules)
+	if err != nil {
+		a.log.Warn("路径映射配置无效: %v", err)
+		mapper = nil
+	}
+	a.pc.SetMappings(mapper)
+}
+
+// applyEnvOptions 设置环境变量的处理与路径收缩
+func (a *PathConvertApp) applyEnvOptions() {
+	mode, err := pathconv.ParseEnvMode(a.cfg.EnvMode)
+	if err != nil {
+		a.log.Warn("%v，保持环境变量不变", err)
+		mode = pathconv.EnvKeep
+	}
+	contract, err := pathconv.ParseContractMode(a.cfg.ContractPaths)
+	if err != nil {
+		a.log.Warn("%v，不收缩路径", err)
+		contract = pathconv.ContractNone
+	}
+	a.pc.SetEnvOptions(mode, contract, nil)
+}
+
+// applyQuoting 设置转换结果的加引号策略
+func (a *PathConvertApp) applyQuoting() {
+	policy, err := pathconv.ParseQuotePolicy(a.cfg.QuotePolicy)
+	if err != nil {
+		a.log.Warn("%v，保留原有引号", err)
+		policy = pathconv.QuoteKeep
+	}
+	sh, err := pathconv.ParseShell(a.cfg.Shell)
+	if err != nil {
+		a.log.Warn("%v，使用posix", err)
+		sh = pathconv.ShellPOSIX
+	}
+	a.pc.SetQuoting(policy, sh)
+}
+
+// applyNormalization 设置路径的词法规范化
+func (a *PathConvertApp) applyNormalization() {
+	trailing, err := pathconv.ParseTrailingPolicy(a.cfg.TrailingSeparator)
+	if err != nil {
+		a.log.Warn("%v，保留结尾分隔符", err)
+		trailing = pathconv.TrailingKeep
+	}
+	a.pc.SetNormalization(a.cfg.Normalize, trailing)
+}
+
+// applyCaseOptions 设置盘符大小写与路径大小写的还原
+func (a *PathConvertApp) applyCaseOptions() {
+	driveCase, err := pathconv.ParseDriveCase(a.cfg.DriveCase)
+	if err != nil {
+		a.log.Warn("%v，保持盘符的大小写", err)
+		driveCase = pathconv.DriveCaseKeep
+	}
+	var resolver pathconv.CaseResolver
+	if a.cfg.ResolveCase {
+		timeout, ttl := a.fsLookupOptions()
+		resolver = pathconv.NewFSCaseResolver(pathconv.DirFS, timeout, ttl, a.clock)
+	}
+	a.pc.SetCaseOptions(driveCase, resolver)
+}
+
+// applyExistenceCheck 设置转换前的存在性检查
+// 配置无效时记录警告并使用默认值
+func (a *PathConvertApp) applyExistenceCheck() {
+	mode, err := pathconv.ParseExistMode(a.cfg.ExistenceCheck)
+	if err != nil {
+		a.log.Warn("%v，不检查路径是否存在", err)
+		mode = pathconv.ExistOff
+	}
+	var checker pathconv.ExistenceChecker
+	if mode != pathconv.ExistOff {
+		timeout, ttl := a.fsLookupOptions()
+		checker = pathconv.NewFSExistenceChecker(pathconv.DirFS, timeout, ttl, a.clock)
+	}
+	a.pc.SetExistenceCheck(mode, checker, a.cfg.AllowedRoots)
+}
+
+// fsLookupOptions 返回存在性检查与大小写还原共用的文件系统查询超时和缓存时间
+// 返回值:
+//   - time.Duration: 单次查询的超时时间
+//   - time.Duration: 查询结果的缓存时间
+func (a *PathConvertApp) fsLookupOptions() (time.Duration, time.Duration) {
+	timeout := a.parseDuration("fs_timeout", a.cfg.FSTimeout, pathconv.DefaultFSTimeout)
+	ttl := a.parseDuration("fs_cache_ttl", a.cfg.FSCacheTTL, pathconv.DefaultFSCacheTTL)
+	return timeout, ttl
+}
+
+// applyBaseDir 设置解析相对路径的基准目录，pin命令固定的项目目录优先于配置
+// 配置无效时记录警告并不解析相对路径
+func (a *PathConvertApp) applyBaseDir() {
+	dir := a.cfg.BaseDir
+	if a.pinnedDir != "" {
+		dir = a.pinnedDir
+	}
+	if err := pathconv.ValidateBaseDir(dir); err != nil {
+		a.log.Warn("%v，不解析相对路径", err)
+		dir = ""
+	}
+	a.pc.SetBaseDir(dir)
+}
+
+// parseDuration 解析配置中的时长，为空或无效时记录警告并返回默认值
+// 参数:
+//   - key: 配置项名称，用于日志
+//   - value: 配置的时长，如 "200ms"
+//   - def: 默认值
+//
+// 返回值:
+//   - time.Duration: 解析结果
+func (a *PathConvertApp) parseDuration(key, value string, def time.Duration) time.Duration {
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		a.log.Warn("%s 配置无效: %q，使用默认值 %v", key, value, def)
+		return def
+	}
+	return d
+}
+
+// applyPrefixPolicies 设置各输出格式对命名空间前缀的处理方式
+// 配置无效时记录警告并使用默认处理方式
+func (a *PathConvertApp) applyPrefixPolicies() {
+	policies, err := pathconv.ParsePrefixPolicies(a.cfg.NamespacePrefixes)
+	if err != nil {
+		a.log.Warn("命名空间前缀配置无效: %v", err)
+		policies = nil
+	}
+	a.pc.SetPrefixPolicies(policies)
+}
+
+// applyClassPolicies 设置各类文本的转换策略
+// 配置无效时记录警告并使用默认策略
+func (a *PathConvertApp) applyClassPolicies() {
+	policies, err := pathconv.ParseClassPolicies(a.cfg.ClassPolicies)
+	if err != nil {
+		a.log.Warn("文本类别策略配置无效: %v", err)
+		policies = nil
+	}
+	a.pc.SetClassPolicies(policies)
+}
+
+// applyThreshold 设置路径识别得分的阈值
+func (a *PathConvertApp) applyThreshold() {
+	threshold := a.cfg.DetectionThreshold
+	if err := pathconv.ValidateThreshold(threshold); err != nil {
+		a.log.Warn("%v，使用默认阈值 %v", err, pathconv.DefaultThreshold)
+		threshold = pathconv.DefaultThreshold
+	}
+	a.pc.SetThreshold(threshold)
+}
+
+// applyMarkdown 设置Markdown感知转换
+func (a *PathConvertApp) applyMarkdown() {
+	mode, err := pathconv.ParseMarkdownMode(a.cfg.Markdown)
+	if err != nil {
+		a.log.Warn("%v，不按Markdown处理", err)
+		mode = pathconv.MarkdownOff
+	}
+	a.pc.SetMarkdown(mode, a.cfg.MarkdownFenceLanguages)
+}
+
+// applyTargetDialects 设置目标程序到输出格式的匹配规则
+// 规则无效时记录警告并总是使用默认格式
+func (a *PathConvertApp) applyTargetDialects() {
+	rules := make([]pathconv.TargetRule, 0, len(a.cfg.TargetDialects))
+	for _, r := range a.cfg.TargetDialects {
+		rules = append(rules, pathconv.TargetRule{Process: r.Process, Title: r.Title, Dialect: pathconv.Dialect(r.Dialect)})
+	}
+	targets, err := pathconv.NewTargetMatcher(rules)
+	if err != nil {
+		a.log.Warn("目标程序规则配置无效: %v", err)
+		targets = nil
+	}
+	a.targets = targets
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/lyj404/win-path-convert/internal/service"
+)
+
+// cmdService 服务管理子命令
+const cmdService = "service"
+
+// serviceDisplayName 服务显示名称
+const serviceDisplayName = "Windows路径自动转换工具"
+
+// serviceRunArg 服务启动参数，SCM以 "service run" 启动程序
+const serviceRunArg = "run"
+
+// serviceRunner 以服务方式运行应用程序，实现service.Runner
+type serviceRunner struct {
+	mu  sync.Mutex
+	app *PathConvertApp // 正在运行的应用程序，初始化完成前为nil
+}
+
+// Run 运行应用程序直到ctx被取消
+func (r *serviceRunner) Run(ctx context.Context) error {
+	return runApplication(runOptions{
+		background: true,
+		ctx:        ctx,
+		started: func(a *PathConvertApp) {
+			r.mu.Lock()
+			r.app = a
+			r.mu.Unlock()
+		},
+	})
+}
+
+// Pause 暂停自动转换
+func (r *serviceRunner) Pause() {
+	if a := r.current(); a != nil {
+		a.pause()
+	}
+}
+
+// Resume 恢复自动转换
+func (r *serviceRunner) Resume() {
+	if a := r.current(); a != nil {
+		a.resume()
+	}
+}
+
+// current 返回正在运行的应用程序
+func (r *serviceRunner) current() *PathConvertApp {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.app
+}
+
+// runServiceCommand 执行服务管理子命令
+// 参数:
+//   - args: service之后的参数，第一个元素为install、remove、start、stop或run
+//
+// 返回值:
+//   - error: 参数无效或操作失败时返回错误
+func runServiceCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: service <install|remove|start|stop>")
+	}
+
+	name := service.DefaultName
+	switch args[0] {
+	case "install":
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("无法获取程序路径: %v", err)
+		}
+		if err := service.Install(name, serviceDisplayName, exe, cmdService, serviceRunArg); err != nil {
+			return err
+		}
+		fmt.Printf("已注册服务 %s，注销并重新登录后在当前用户的会话中启动\n", name)
+	case "remove":
+		if err := service.Remove(name); err != nil {
+			return err
+		}
+		fmt.Printf("已删除服务 %s\n", name)
+	case "start":
+		if err := service.Start(name); err != nil {
+			return err
+		}
+		fmt.Printf("已启动服务 %s\n", name)
+	case "stop":
+		if err := service.Stop(name); err != nil {
+			return err
+		}
+		fmt.Printf("已停止服务 %s\n", name)
+	case serviceRunArg:
+		// 仅供SCM调用；在终端中直接执行时按普通方式运行，便于调试
+		if !service.IsService() {
+			return RunApplication()
+		}
+		return service.Run(name, &serviceRunner{})
+	default:
+		return fmt.Errorf("未知的服务命令: %s", args[0])
+	}
+	return nil
+}
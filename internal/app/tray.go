@@ -0,0 +1,311 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/lyj404/win-path-convert/internal/clipboard"
+	"github.com/lyj404/win-path-convert/internal/config"
+	"github.com/lyj404/win-path-convert/internal/notify"
+	"github.com/lyj404/win-path-convert/internal/pathconv"
+	"github.com/lyj404/win-path-convert/internal/tray"
+	"github.com/lyj404/win-path-convert/internal/winapi"
+)
+
+// trayIconID 托盘图标标识符，程序只有一个图标
+const trayIconID = 1
+
+// swShowNormal ShellExecute的窗口显示方式：正常显示
+const swShowNormal = 1
+
+// addTrayIcon 在通知区域添加托盘图标
+// 资源管理器重启后托盘图标会丢失，收到TaskbarCreated消息时再次调用即可恢复
+// 参数:
+//   - hwnd: 接收图标回调消息的窗口句柄
+func (a *PathConvertApp) addTrayIcon(hwnd uintptr) {
+	icon, _, _ := winapi.ProcLoadIconW.Call(0, winapi.IDIApp)
+	nid := a.newNotifyIconData(hwnd)
+	nid.UFlags = winapi.NIFMessage | winapi.NIFIcon | winapi.NIFTip
+	nid.UCallbackMessage = WMTrayCallback
+	nid.HIcon = icon
+	copyUTF16(nid.SzTip[:], tray.Tooltip(a.trayState()))
+
+	if ret, _, err := winapi.ProcShellNotifyIconW.Call(winapi.NIMAdd, uintptr(unsafe.Pointer(&nid))); ret == 0 {
+		a.log.Warn("无法添加托盘图标: %v", err)
+		return
+	}
+	a.trayWnd.Store(hwnd)
+}
+
+// removeTrayIcon 移除托盘图标
+// 参数:
+//   - hwnd: 添加图标时使用的窗口句柄
+func (a *PathConvertApp) removeTrayIcon(hwnd uintptr) {
+	if a.trayWnd.Swap(0) == 0 {
+		return
+	}
+
+	nid := a.newNotifyIconData(hwnd)
+	winapi.ProcShellNotifyIconW.Call(winapi.NIMDelete, uintptr(unsafe.Pointer(&nid)))
+}
+
+// updateTray 刷新托盘图标的提示文本
+// 可以在任意协程中调用，调用方不得持有a.mu
+func (a *PathConvertApp) updateTray() {
+	hwnd := a.trayWnd.Load()
+	if hwnd == 0 {
+		return
+	}
+
+	nid := a.newNotifyIconData(hwnd)
+	nid.UFlags = winapi.NIFTip
+	copyUTF16(nid.SzTip[:], tray.Tooltip(a.trayState()))
+	winapi.ProcShellNotifyIconW.Call(winapi.NIMModify, uintptr(unsafe.Pointer(&nid)))
+}
+
+// newNotifyIconData 创建标识本程序托盘图标的结构体
+func (a *PathConvertApp) newNotifyIconData(hwnd uintptr) NotifyIconData {
+	return NotifyIconData{
+		CbSize: uint32(unsafe.Sizeof(NotifyIconData{})),
+		HWnd:   hwnd,
+		UID:    trayIconID,
+	}
+}
+
+// trayState 收集构建托盘菜单所需的状态，调用方不得持有a.mu
+func (a *PathConvertApp) trayState() tray.State {
+	state := a.suspend.State()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	dialects := make([]string, 0, len(pathconv.Dialects()))
+	for _, d := range pathconv.Dialects() {
+		dialects = append(dialects, string(d))
+	}
+
+	var recent []tray.Recent
+	for _, e := range a.history.Recent(tray.MaxRecent) {
+		recent = append(recent, tray.Recent{Original: e.Original, Converted: e.Converted})
+	}
+
+	return tray.State{
+		Status:       state.String(),
+		Paused:       state.Suspended(),
+		Dialects:     dialects,
+		Dialect:      string(a.pc.Dialect()),
+		Recent:       recent,
+		LogAvailable: a.cfg.LogFile != "",
+	}
+}
+
+// showTrayMenu 在鼠标位置弹出托盘菜单并执行用户选择的动作
+// 参数:
+//   - hwnd: 菜单所属窗口句柄
+func (a *PathConvertApp) showTrayMenu(hwnd uintptr) {
+	menu := tray.Build(a.trayState())
+	hMenu := buildPopupMenu(menu.Items)
+	if hMenu == 0 {
+		a.log.Warn("无法创建托盘菜单")
+		return
+	}
+	defer winapi.ProcDestroyMenu.Call(hMenu)
+
+	var pt Point
+	winapi.ProcGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+	// 菜单所属窗口必须是前台窗口，否则点击菜单外部时菜单不会关闭
+	winapi.ProcSetForegroundWindow.Call(hwnd)
+	id, _, _ := winapi.ProcTrackPopupMenu.Call(
+		hMenu,
+		winapi.TPMReturnCmd|winapi.TPMRightBtn|winapi.TPMNoNotify,
+		uintptr(pt.X), uintptr(pt.Y),
+		0, hwnd, 0,
+	)
+	// 按照TrackPopupMenu文档的建议投递空消息，确保菜单能正常再次弹出
+	winapi.ProcPostMessageW.Call(hwnd, winapi.WMNull, 0, 0)
+
+	if action, ok := menu.Lookup(uint32(id)); ok {
+		a.performTrayAction(action)
+	}
+}
+
+// buildPopupMenu 将菜单模型渲染为Win32弹出菜单
+// 参数:
+//   - items: 菜单项
+//
+// 返回值:
+//   - uintptr: 菜单句柄，失败时为0
+func buildPopupMenu(items []tray.Item) uintptr {
+	hMenu, _, _ := winapi.ProcCreatePopupMenu.Call()
+	if hMenu == 0 {
+		return 0
+	}
+
+	for _, item := range items {
+		if item.Separator {
+			winapi.ProcAppendMenuW.Call(hMenu, winapi.MFSeparator, 0, 0)
+			continue
+		}
+
+		flags := uintptr(winapi.MFString)
+		if item.Disabled {
+			flags |= winapi.MFGrayed
+		}
+		if item.Checked {
+			flags |= winapi.MFChecked
+		}
+		// 菜单文本中的&表示快捷键前缀，路径中的&需要写成&&才能原样显示
+		label, _ := syscall.UTF16PtrFromString(strings.ReplaceAll(item.Label, "&", "&&"))
+
+		if len(item.Children) > 0 {
+			sub := buildPopupMenu(item.Children)
+			winapi.ProcAppendMenuW.Call(hMenu, flags|winapi.MFPopup, sub, uintptr(unsafe.Pointer(label)))
+			continue
+		}
+		winapi.ProcAppendMenuW.Call(hMenu, flags, uintptr(item.ID), uintptr(unsafe.Pointer(label)))
+	}
+	return hMenu
+}
+
+// performTrayAction 执行托盘菜单动作
+// 参数:
+//   - action: 用户选择的动作
+func (a *PathConvertApp) performTrayAction(action tray.Action) {
+	switch action.Kind {
+	case tray.ActionToggle:
+		if a.suspend.State().Suspended() {
+			a.resume()
+		} else {
+			a.pause()
+		}
+	case tray.ActionSetDialect:
+		if err := a.setDialect(action.Dialect); err != nil {
+			a.log.Warn("%v", err)
+		}
+	case tray.ActionRecopy:
+		a.recopy(action.Text)
+	case tray.ActionOpenConfig:
+		a.openConfigFile()
+	case tray.ActionOpenLog:
+		a.mu.Lock()
+		logFile := a.cfg.LogFile
+		a.mu.Unlock()
+		a.openFile(logFile)
+	case tray.ActionQuit:
+		a.log.Info("通过托盘菜单退出")
+		a.cancel()
+	}
+}
+
+// setDialect 切换默认输出格式，仅在本次运行期间有效
+// 参数:
+//   - name: 输出格式名称
+//
+// 返回值:
+//   - error: 名称无效时返回错误
+func (a *PathConvertApp) setDialect(name string) error {
+	d, err := pathconv.ParseDialect(name)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.cfg.Dialect = string(d)
+	a.pc.SetDialect(d)
+	a.mu.Unlock()
+
+	a.log.Info("输出格式已切换为 %s", d)
+	a.updateTray()
+	return nil
+}
+
+// recopy 将历史转换结果重新复制到剪贴板
+// 同步更新内容哈希，避免再次触发自动转换
+// 参数:
+//   - text: 要复制的内容
+func (a *PathConvertApp) recopy(text string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.cb.SetText(text); err != nil {
+		a.log.Error("无法设置剪贴板内容: %v", err)
+		return
+	}
+	a.cb.SetLastContentHash(clipboard.QuickHash(text))
+	a.log.Info("已重新复制: %s", a.log.ShortenText(text))
+}
+
+// openConfigFile 用关联程序打开配置文件
+// 配置文件不存在时先以当前配置生成一份，方便用户在此基础上修改
+func (a *PathConvertApp) openConfigFile() {
+	a.mu.Lock()
+	path, cfg := a.cfgPath, *a.cfg
+	a.mu.Unlock()
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := config.SaveConfig(&cfg, path); err != nil {
+			a.log.Error("无法生成配置文件: %v", err)
+			return
+		}
+		a.log.Info("已生成配置文件: %s", path)
+	}
+	a.openFile(path)
+}
+
+// openFile 用系统关联的程序打开文件
+// 参数:
+//   - path: 文件路径
+func (a *PathConvertApp) openFile(path string) {
+	if path == "" {
+		return
+	}
+	verb, _ := syscall.UTF16PtrFromString("open")
+	file, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		a.log.Error("无效的文件路径: %v", err)
+		return
+	}
+	if err := windows.ShellExecute(0, verb, file, nil, nil, swShowNormal); err != nil {
+		a.log.Error("无法打开文件 %s: %v", path, err)
+	}
+}
+
+// copyUTF16 将字符串以UTF-16编码复制到定长缓冲区，超出部分被截断，结尾始终保留空字符
+// 参数:
+//   - dst: 目标缓冲区
+//   - s: 源字符串
+func copyUTF16(dst []uint16, s string) {
+	src := utf16.Encode([]rune(s))
+	n := copy(dst[:len(dst)-1], src)
+	dst[n] = 0
+}
+
+// balloonNotifier 以托盘气泡的形式显示通知
+// 托盘图标未显示时返回错误，由调用方改用其他通知方式
+type balloonNotifier struct {
+	app *PathConvertApp
+}
+
+// Notify 在托盘图标上弹出气泡通知
+func (b *balloonNotifier) Notify(n notify.Notification) error {
+	hwnd := b.app.trayWnd.Load()
+	if hwnd == 0 {
+		return fmt.Errorf("托盘图标不可用")
+	}
+
+	nid := b.app.newNotifyIconData(hwnd)
+	nid.UFlags = winapi.NIFInfo
+	nid.DwInfoFlags = winapi.NIIFInfo | winapi.NIIFNoSound
+	copyUTF16(nid.SzInfoTitle[:], n.Title)
+	copyUTF16(nid.SzInfo[:], n.Body)
+	if ret, _, err := winapi.ProcShellNotifyIconW.Call(winapi.NIMModify, uintptr(unsafe.Pointer(&nid))); ret == 0 {
+		return fmt.Errorf("无法显示气泡通知: %v", err)
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package app
 
-import "github.com/lyj404/win-path-convert/internal/winapi"
+import (
+	"golang.org/x/sys/windows"
+
+	"github.com/lyj404/win-path-convert/internal/winapi"
+)
 
 // Windows消息系统相关常量
 // 这些常量定义了应用程序需要处理的Windows消息类型
@@ -8,9 +12,15 @@ import "github.com/lyj404/win-path-convert/internal/winapi"
 // WMClipboardUpdate 表示剪贴板内容已更新的消息
 // 当用户复制内容到剪贴板时，系统会向注册的窗口发送此消息
 const (
-	WMClipboardUpdate = winapi.WMClipboardUpdate // 剪贴板更新消息 (0x031D)
-	WMDestroy         = winapi.WMDestroy         // 窗口销毁消息 (0x0002)
-	WMQuit            = winapi.WMQuit            // 退出消息，用于结束消息循环 (0x0012)
+	WMClipboardUpdate  = winapi.WMClipboardUpdate  // 剪贴板更新消息 (0x031D)
+	WMDestroy          = winapi.WMDestroy          // 窗口销毁消息 (0x0002)
+	WMQuit             = winapi.WMQuit             // 退出消息，用于结束消息循环 (0x0012)
+	WMHotkey           = winapi.WMHotkey           // 全局热键消息 (0x0312)
+	WMRenderFormat     = winapi.WMRenderFormat     // 请求延迟渲染的剪贴板数据 (0x0305)
+	WMRenderAllFormats = winapi.WMRenderAllFormats // 所有者窗口销毁前渲染所有延迟的格式 (0x0306)
+	WMDestroyClipboard = winapi.WMDestroyClipboard // 剪贴板被清空 (0x0307)
+	WMReloadHotkeys    = winapi.WMApp + 1          // 自定义消息，通知消息循环重新注册热键
+	WMTrayCallback     = winapi.WMApp + 2          // 自定义消息，托盘图标的鼠标事件回调
 )
 
 // WndClassEx 窗口类结构体
@@ -45,3 +55,49 @@ type Msg struct {
 		Y int32 // 屏幕Y坐标
 	}
 }
+
+// KeybdInput 键盘输入结构体
+// 这是Windows KEYBDINPUT结构的镜像，描述一次模拟的按键事件
+type KeybdInput struct {
+	Vk        uint16  // 虚拟键码
+	Scan      uint16  // 硬件扫描码，使用虚拟键码时为0
+	Flags     uint32  // 按键标志，如KEYEVENTF_KEYUP
+	Time      uint32  // 事件时间戳，0表示由系统提供
+	ExtraInfo uintptr // 附加信息
+}
+
+// Input 输入事件结构体
+// 这是Windows INPUT结构的镜像，SendInput要求传入该结构体数组
+// 原结构中的联合体以MOUSEINPUT为最大成员，这里在键盘输入后补齐剩余字节
+type Input struct {
+	Type uint32     // 输入类型，键盘输入为INPUT_KEYBOARD
+	Ki   KeybdInput // 键盘输入数据
+	_    [8]byte    // 补齐到MOUSEINPUT的大小
+}
+
+// Point 屏幕坐标结构体
+// 这是Windows POINT结构的镜像
+type Point struct {
+	X int32 // 屏幕X坐标
+	Y int32 // 屏幕Y坐标
+}
+
+// NotifyIconData 通知区域图标结构体
+// 这是Windows NOTIFYICONDATAW结构的镜像，用于添加、修改和删除托盘图标
+type NotifyIconData struct {
+	CbSize           uint32       // 结构体大小
+	HWnd             uintptr      // 接收图标回调消息的窗口句柄
+	UID              uint32       // 图标标识符，同一窗口的多个图标以此区分
+	UFlags           uint32       // 指示哪些字段有效的标志
+	UCallbackMessage uint32       // 鼠标事件回调消息
+	HIcon            uintptr      // 图标句柄
+	SzTip            [128]uint16  // 鼠标悬停提示文本
+	DwState          uint32       // 图标状态
+	DwStateMask      uint32       // 图标状态掩码
+	SzInfo           [256]uint16  // 气泡通知正文
+	UVersion         uint32       // 联合体uTimeout/uVersion
+	SzInfoTitle      [64]uint16   // 气泡通知标题
+	DwInfoFlags      uint32       // 气泡通知图标等标志
+	GuidItem         windows.GUID // 图标GUID
+	HBalloonIcon     uintptr      // 气泡通知自定义图标
+}
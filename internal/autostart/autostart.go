@@ -0,0 +1,213 @@
+package autostart
+
+import (
+	"debug/pe"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultName 自启动项的默认名称
+const DefaultName = "win-path-convert"
+
+// Status 自启动项的注册状态
+type Status struct {
+	Installed bool   // 是否已注册
+	Command   string // 已注册的启动命令，未注册时为空
+}
+
+// String 返回面向用户的状态描述
+func (s Status) String() string {
+	if !s.Installed {
+		return "未启用"
+	}
+	return fmt.Sprintf("已启用（%s）", s.Command)
+}
+
+// Registrar 开机自启动注册器
+// Windows下由当前用户的Run注册表项实现，测试时使用Fake
+type Registrar interface {
+	// Install 注册登录时执行的命令，已存在时覆盖
+	Install(command string) error
+	// Uninstall 删除自启动项，未注册时不返回错误
+	Uninstall() error
+	// Status 查询自启动项的注册状态
+	Status() (Status, error)
+}
+
+// CommandLine 按Windows命令行规则拼接程序路径与参数
+// 含空格、制表符或引号的部分加上引号，引号及其前面的反斜杠按CommandLineToArgvW的规则转义
+// 参数:
+//   - exe: 程序路径
+//   - args: 命令行参数
+//
+// 返回值:
+//   - string: 可写入注册表或快捷方式的命令行
+func CommandLine(exe string, args ...string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, quoteArg(exe))
+	for _, arg := range args {
+		parts = append(parts, quoteArg(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// GUIVariant 返回与控制台版本位于同一目录、不带控制台的程序变体的路径
+// 变体以 -ldflags -H=windowsgui 编译，文件名在扩展名前加 w，如 win-path-convertw.exe
+// 参数:
+//   - exe: 控制台版本的程序路径
+//
+// 返回值:
+//   - string: 变体的程序路径
+func GUIVariant(exe string) string {
+	ext := filepath.Ext(exe)
+	return strings.TrimSuffix(exe, ext) + "w" + ext
+}
+
+// IsGUIExecutable 报告程序是否为Windows图形子系统的可执行文件
+// 图形子系统的程序启动时系统不会为其创建控制台窗口
+// 参数:
+//   - path: 程序路径
+//
+// 返回值:
+//   - bool: 是否为图形子系统
+//   - error: 文件不存在或不是PE文件时返回错误
+func IsGUIExecutable(path string) (bool, error) {
+	f, err := pe.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("无法读取程序 %s: %v", path, err)
+	}
+	defer f.Close()
+	switch h := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		return h.Subsystem == pe.IMAGE_SUBSYSTEM_WINDOWS_GUI, nil
+	case *pe.OptionalHeader64:
+		return h.Subsystem == pe.IMAGE_SUBSYSTEM_WINDOWS_GUI, nil
+	}
+	return false, fmt.Errorf("程序 %s 缺少可选头", path)
+}
+
+// LogonExecutable 选择登录时启动的程序，优先使用不会打开控制台窗口的图形子系统版本
+// 参数:
+//   - exe: 当前程序路径
+//
+// 返回值:
+//   - string: 登录时启动的程序路径
+//   - bool: 该程序是否为图形子系统；为false时登录时会短暂出现控制台窗口
+func LogonExecutable(exe string) (string, bool) {
+	for _, candidate := range []string{exe, GUIVariant(exe)} {
+		if gui, err := IsGUIExecutable(candidate); err == nil && gui {
+			return candidate, true
+		}
+	}
+	return exe, false
+}
+
+// Enable 注册登录时启动程序的命令，优先使用图形子系统的版本（见LogonExecutable）
+// 参数:
+//   - r: 自启动注册器
+//   - exe: 当前程序路径
+//   - args: 启动参数
+//
+// 返回值:
+//   - string: 注册的命令
+//   - bool: 注册的程序是否为图形子系统
+//   - error: 注册失败时返回错误
+func Enable(r Registrar, exe string, args ...string) (string, bool, error) {
+	launcher, gui := LogonExecutable(exe)
+	command := CommandLine(launcher, args...)
+	if err := r.Install(command); err != nil {
+		return "", false, err
+	}
+	return command, gui, nil
+}
+
+// Describe 返回自启动状态的描述，查询失败时说明原因
+// 参数:
+//   - r: 自启动注册器
+//
+// 返回值:
+//   - string: 面向用户的状态描述
+func Describe(r Registrar) string {
+	s, err := r.Status()
+	if err != nil {
+		return fmt.Sprintf("未知（%v）", err)
+	}
+	return s.String()
+}
+
+// quoteArg 按CommandLineToArgvW的规则为单个参数加引号
+func quoteArg(s string) string {
+	if s == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(s, " \t\"") {
+		return s
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			// 引号前的反斜杠需要加倍，引号本身再转义一次
+			b.WriteString(strings.Repeat(`\`, backslashes*2+1))
+		default:
+			b.WriteString(strings.Repeat(`\`, backslashes))
+		}
+		backslashes = 0
+		b.WriteByte(c)
+	}
+	// 结尾的反斜杠紧挨着闭合引号，同样需要加倍
+	b.WriteString(strings.Repeat(`\`, backslashes*2))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// Fake 保存在内存中的自启动注册器，供测试使用
+type Fake struct {
+	mu      sync.Mutex
+	command string // 已注册的命令
+	Err     error  // 不为nil时所有操作都返回该错误
+}
+
+// Install 记录注册的命令
+func (f *Fake) Install(command string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	if command == "" {
+		return fmt.Errorf("启动命令不能为空")
+	}
+	f.command = command
+	return nil
+}
+
+// Uninstall 清除注册的命令
+func (f *Fake) Uninstall() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.command = ""
+	return nil
+}
+
+// Status 返回注册状态
+func (f *Fake) Status() (Status, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return Status{}, f.Err
+	}
+	return Status{Installed: f.command != "", Command: f.command}, nil
+}
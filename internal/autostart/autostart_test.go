@@ -0,0 +1,171 @@
+package autostart
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommandLine(t *testing.T) {
+	tests := []struct {
+		exe  string
+		args []string
+		want string
+	}{
+		{`C:\tools\wpc.exe`, nil, `C:\tools\wpc.exe`},
+		{`C:\Program Files\wpc\wpc.exe`, []string{"--background"}, `"C:\Program Files\wpc\wpc.exe" --background`},
+		{`C:\tools\wpc.exe`, []string{""}, `C:\tools\wpc.exe ""`},
+		{`C:\tools\wpc.exe`, []string{`say "hi"`}, `C:\tools\wpc.exe "say \"hi\""`},
+		{`C:\my dir\`, nil, `"C:\my dir\\"`},
+		{`C:\tools\wpc.exe`, []string{`a\"b c`}, `C:\tools\wpc.exe "a\\\"b c"`},
+	}
+	for _, tt := range tests {
+		if got := CommandLine(tt.exe, tt.args...); got != tt.want {
+			t.Errorf("CommandLine(%q, %q) = %s, want %s", tt.exe, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestFake_InstallStatusUninstall(t *testing.T) {
+	var r Registrar = &Fake{}
+
+	s, err := r.Status()
+	if err != nil || s.Installed {
+		t.Fatalf("initial Status = %+v, %v", s, err)
+	}
+
+	if err := r.Install(`"C:\a b\wpc.exe" --background`); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	s, _ = r.Status()
+	if !s.Installed || s.Command != `"C:\a b\wpc.exe" --background` {
+		t.Errorf("Status after Install = %+v", s)
+	}
+
+	if err := r.Uninstall(); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+	if s, _ = r.Status(); s.Installed {
+		t.Errorf("Status after Uninstall = %+v", s)
+	}
+	// 重复取消不应报错
+	if err := r.Uninstall(); err != nil {
+		t.Errorf("second Uninstall failed: %v", err)
+	}
+}
+
+func TestFake_Errors(t *testing.T) {
+	f := &Fake{}
+	if err := f.Install(""); err == nil {
+		t.Error("expected an error for an empty command")
+	}
+
+	f.Err = errors.New("access denied")
+	if err := f.Install("x"); err == nil {
+		t.Error("expected Install to fail")
+	}
+	if _, err := f.Status(); err == nil {
+		t.Error("expected Status to fail")
+	}
+}
+
+func TestStatus_String(t *testing.T) {
+	if got := (Status{}).String(); got != "未启用" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (Status{Installed: true, Command: "wpc.exe"}).String(); got != "已启用（wpc.exe）" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+// writePE 写入只有文件头和可选头的PE文件，用于测试子系统的识别
+func writePE(t *testing.T, path string, subsystem uint16) {
+	t.Helper()
+	var b bytes.Buffer
+	dos := make([]byte, 0x40)
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3c:], 0x40)
+	b.Write(dos)
+	b.WriteString("PE\x00\x00")
+	opt := pe.OptionalHeader64{Magic: 0x20b, Subsystem: subsystem, NumberOfRvaAndSizes: 16}
+	binary.Write(&b, binary.LittleEndian, pe.FileHeader{
+		Machine:              pe.IMAGE_FILE_MACHINE_AMD64,
+		SizeOfOptionalHeader: uint16(binary.Size(opt)),
+	})
+	binary.Write(&b, binary.LittleEndian, opt)
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGUIVariant(t *testing.T) {
+	if got := GUIVariant(`C:\tools\wpc.exe`); got != `C:\tools\wpcw.exe` {
+		t.Errorf("GUIVariant = %s", got)
+	}
+}
+
+func TestLogonExecutable(t *testing.T) {
+	dir := t.TempDir()
+	console := filepath.Join(dir, "wpc.exe")
+	writePE(t, console, pe.IMAGE_SUBSYSTEM_WINDOWS_CUI)
+
+	if gui, err := IsGUIExecutable(console); err != nil || gui {
+		t.Errorf("IsGUIExecutable(console) = (%v, %v), want (false, nil)", gui, err)
+	}
+	if _, err := IsGUIExecutable(filepath.Join(dir, "missing.exe")); err == nil {
+		t.Error("IsGUIExecutable should fail for a missing file")
+	}
+	if exe, gui := LogonExecutable(console); exe != console || gui {
+		t.Errorf("without a GUI variant LogonExecutable = (%s, %v), want (%s, false)", exe, gui, console)
+	}
+
+	// 同目录下存在图形子系统的变体时优先使用
+	writePE(t, GUIVariant(console), pe.IMAGE_SUBSYSTEM_WINDOWS_GUI)
+	if exe, gui := LogonExecutable(console); exe != GUIVariant(console) || !gui {
+		t.Errorf("LogonExecutable = (%s, %v), want (%s, true)", exe, gui, GUIVariant(console))
+	}
+	// 当前程序本身是图形子系统时直接使用
+	if exe, gui := LogonExecutable(GUIVariant(console)); exe != GUIVariant(console) || !gui {
+		t.Errorf("LogonExecutable(gui) = (%s, %v), want (%s, true)", exe, gui, GUIVariant(console))
+	}
+}
+
+func TestEnable(t *testing.T) {
+	dir := t.TempDir()
+	console := filepath.Join(dir, "wpc.exe")
+	writePE(t, console, pe.IMAGE_SUBSYSTEM_WINDOWS_CUI)
+	r := &Fake{}
+
+	command, gui, err := Enable(r, console, "--detached")
+	if err != nil || gui || command != CommandLine(console, "--detached") {
+		t.Errorf("Enable without a GUI variant = (%s, %v, %v)", command, gui, err)
+	}
+	if got := Describe(r); got != "已启用（"+command+"）" {
+		t.Errorf("Describe after Enable = %q", got)
+	}
+
+	writePE(t, GUIVariant(console), pe.IMAGE_SUBSYSTEM_WINDOWS_GUI)
+	command, gui, err = Enable(r, console, "--detached")
+	if err != nil || !gui || command != CommandLine(GUIVariant(console), "--detached") {
+		t.Errorf("Enable with a GUI variant = (%s, %v, %v)", command, gui, err)
+	}
+
+	if err := r.Uninstall(); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+	if got := Describe(r); got != "未启用" {
+		t.Errorf("Describe after Uninstall = %q", got)
+	}
+
+	r.Err = errors.New("access denied")
+	if _, _, err := Enable(r, console, "--detached"); err == nil {
+		t.Error("Enable should report registrar errors")
+	}
+	if got := Describe(r); got != "未知（access denied）" {
+		t.Errorf("Describe with an error = %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+package autostart
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// runKeyPath 当前用户登录时自动执行的程序所在的注册表项
+const runKeyPath = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+// RunKey 基于HKCU Run注册表项的自启动注册器
+// 只影响当前用户，不需要管理员权限
+type RunKey struct {
+	name string // 注册表值名称
+}
+
+// NewRunKey 创建基于Run注册表项的自启动注册器
+// 参数:
+//   - name: 注册表值名称，通常为DefaultName
+//
+// 返回值:
+//   - *RunKey: 注册器实例
+func NewRunKey(name string) *RunKey {
+	return &RunKey{name: name}
+}
+
+// Install 将启动命令写入Run注册表项
+func (r *RunKey) Install(command string) error {
+	if command == "" {
+		return fmt.Errorf("启动命令不能为空")
+	}
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("无法打开注册表项 %s: %v", runKeyPath, err)
+	}
+	defer key.Close()
+
+	if err := key.SetStringValue(r.name, command); err != nil {
+		return fmt.Errorf("无法写入自启动项: %v", err)
+	}
+	return nil
+}
+
+// Uninstall 从Run注册表项删除启动命令
+func (r *RunKey) Uninstall() error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+	if err != nil {
+		if errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+			return nil
+		}
+		return fmt.Errorf("无法打开注册表项 %s: %v", runKeyPath, err)
+	}
+	defer key.Close()
+
+	if err := key.DeleteValue(r.name); err != nil && !errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+		return fmt.Errorf("无法删除自启动项: %v", err)
+	}
+	return nil
+}
+
+// Status 读取Run注册表项中的启动命令
+func (r *RunKey) Status() (Status, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		if errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+			return Status{}, nil
+		}
+		return Status{}, fmt.Errorf("无法打开注册表项 %s: %v", runKeyPath, err)
+	}
+	defer key.Close()
+
+	command, _, err := key.GetStringValue(r.name)
+	if err != nil {
+		if errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+			return Status{}, nil
+		}
+		return Status{}, fmt.Errorf("无法读取自启动项: %v", err)
+	}
+	return Status{Installed: true, Command: command}, nil
+}
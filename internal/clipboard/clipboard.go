@@ -4,6 +4,7 @@ import (
 	"encoding/hex" // 用于将哈希值转换为十六进制字符串
 	"fmt"          // 格式化输出
 	"hash/fnv"     // FNV哈希算法实现
+	"runtime"      // 固定线程，保证读取的是本线程的错误码
 	"syscall"      // 系统调用接口
 	"time"         // 时间操作，用于退避重试间隔
 	"unsafe"       // 不安全指针操作，用于Windows API调用
@@ -35,30 +36,9 @@ func NewClipboardManager() *ClipboardManager {
 //   - string: 剪贴板中的文本内容
 //   - error: 获取过程中可能发生的错误
 func (cm *ClipboardManager) GetText() (string, error) {
-	var lastErr error // 记录最后一次错误，用于返回
-	var clipboardOpened bool
-
-	// 使用退避策略重试，第一次立即尝试，然后等待15ms和30ms再尝试
-	// 这种策略可以减少因剪贴板被其他进程临时占用而导致的失败
-	for _, delay := range []time.Duration{0, 15 * time.Millisecond, 30 * time.Millisecond} {
-		if delay > 0 {
-			time.Sleep(delay)
-		}
-
-		// 尝试打开剪贴板，0表示当前进程
-		// 如果返回值非0表示成功，0表示失败
-		if ret, _, err := winapi.ProcOpenClipboard.Call(0); ret == 0 {
-			lastErr = err
-			continue // 打开失败，尝试下一次重试
-		}
-		// 剪贴板打开成功，标记状态并退出循环
-		clipboardOpened = true
-		break
-	}
-
-	if !clipboardOpened {
-		// 所有尝试均失败，返回最后一次错误
-		return "", fmt.Errorf("无法打开剪贴板: %v", lastErr)
+	// 使用退避策略打开剪贴板，剪贴板可能被其他进程临时占用
+	if err := openClipboard(0); err != nil {
+		return "", err
 	}
 
 	// 确保函数退出时关闭剪贴板，避免资源锁定
@@ -115,36 +95,121 @@ func (cm *ClipboardManager) GetText() (string, error) {
 // 返回值:
 //   - error: 设置过程中可能发生的错误
 func (cm *ClipboardManager) SetText(text string) error {
-	var lastErr error // 记录最后一次错误，用于返回
-	var clipboardOpened bool
+	// 使用与GetText相同的退避策略打开剪贴板
+	if err := openClipboard(0); err != nil {
+		return err
+	}
+	// 确保函数退出时关闭剪贴板
+	defer winapi.ProcCloseClipboard.Call()
 
-	// 使用退避策略重试，与GetText相同的策略
-	for _, delay := range []time.Duration{0, 15 * time.Millisecond, 30 * time.Millisecond} {
-		if delay > 0 {
-			time.Sleep(delay)
-		}
+	// 清空剪贴板，准备设置新内容
+	winapi.ProcEmptyClipboard.Call()
 
-		// 尝试打开剪贴板
-		if ret, _, err := winapi.ProcOpenClipboard.Call(0); ret == 0 {
-			lastErr = err
-			continue // 打开失败，尝试下一次重试
-		}
-		// 剪贴板打开成功，标记状态并退出循环
-		clipboardOpened = true
-		break
+	return setTextData(text)
+}
+
+// SetDelayedText 以延迟渲染的方式占有剪贴板文本
+// 剪贴板中的Unicode文本只登记格式而不提供数据，其他程序第一次读取时，
+// 系统向hwnd发送WM_RENDERFORMAT消息，由窗口过程调用RenderText提供数据
+// 参数:
+//   - hwnd: 负责渲染数据的窗口句柄，成为剪贴板的所有者
+//
+// 返回值:
+//   - error: 打开或设置剪贴板时发生的错误
+func (cm *ClipboardManager) SetDelayedText(hwnd uintptr) error {
+	if err := openClipboard(hwnd); err != nil {
+		return err
 	}
+	defer winapi.ProcCloseClipboard.Call()
+
+	// 清空剪贴板，同时使hwnd成为所有者
+	winapi.ProcEmptyClipboard.Call()
 
-	if !clipboardOpened {
-		// 所有尝试均失败，返回最后一次错误
-		return fmt.Errorf("无法打开剪贴板: %v", lastErr)
+	// 数据句柄为NULL表示延迟渲染，此时成功也返回NULL，只能通过错误码判断是否失败。
+	// 错误码按线程保存，先清零并固定线程，避免读到之前调用留下的错误码
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	winapi.ProcSetLastError.Call(0)
+	if ret, _, err := winapi.ProcSetClipboardData.Call(winapi.CFUnicodeText, 0); ret == 0 && err != windows.ERROR_SUCCESS {
+		return fmt.Errorf("无法登记延迟渲染的剪贴板格式: %v", err)
 	}
+	return nil
+}
 
-	// 确保函数退出时关闭剪贴板
+// RenderText 在处理WM_RENDERFORMAT消息时提供剪贴板文本
+// 请求数据的程序已经打开了剪贴板，这里不能再打开或清空剪贴板
+// 参数:
+//   - text: 要提供的文本内容
+//
+// 返回值:
+//   - error: 设置数据时发生的错误
+func (cm *ClipboardManager) RenderText(text string) error {
+	return setTextData(text)
+}
+
+// RenderAllText 在处理WM_RENDERALLFORMATS消息时提供剪贴板文本
+// 所有者窗口销毁前需要自行打开剪贴板并提供数据，否则剪贴板中的文本将丢失
+// 参数:
+//   - hwnd: 剪贴板的所有者窗口句柄
+//   - text: 要提供的文本内容
+//
+// 返回值:
+//   - error: 打开或设置剪贴板时发生的错误
+func (cm *ClipboardManager) RenderAllText(hwnd uintptr, text string) error {
+	if err := openClipboard(hwnd); err != nil {
+		return err
+	}
 	defer winapi.ProcCloseClipboard.Call()
 
-	// 清空剪贴板，准备设置新内容
-	winapi.ProcEmptyClipboard.Call()
+	// 打开剪贴板之前其他程序可能已经清空了剪贴板，此时不需要渲染
+	if !cm.IsOwner(hwnd) {
+		return nil
+	}
+	return setTextData(text)
+}
+
+// IsOwner 报告hwnd是否为剪贴板的所有者
+// 参数:
+//   - hwnd: 窗口句柄
+//
+// 返回值:
+//   - bool: hwnd非0且为剪贴板的所有者时返回true
+func (cm *ClipboardManager) IsOwner(hwnd uintptr) bool {
+	owner, _, _ := winapi.ProcGetClipboardOwner.Call()
+	return hwnd != 0 && owner == hwnd
+}
 
+// openClipboard 打开剪贴板，包含简单退避重试
+// 第一次立即尝试，然后等待15ms和30ms再尝试，减少因剪贴板被其他进程临时占用而导致的失败
+// 参数:
+//   - hwnd: 与剪贴板关联的窗口句柄，0表示当前任务
+//
+// 返回值:
+//   - error: 所有尝试均失败时返回最后一次错误
+func openClipboard(hwnd uintptr) error {
+	var lastErr error // 记录最后一次错误，用于返回
+	for _, delay := range []time.Duration{0, 15 * time.Millisecond, 30 * time.Millisecond} {
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		// 尝试打开剪贴板，返回值非0表示成功
+		ret, _, err := winapi.ProcOpenClipboard.Call(hwnd)
+		if ret != 0 {
+			return nil
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("无法打开剪贴板: %v", lastErr)
+}
+
+// setTextData 将文本复制到新分配的内存块，并作为Unicode文本交给剪贴板
+// 调用方需已打开剪贴板，或正在处理WM_RENDERFORMAT消息
+// 参数:
+//   - text: 要设置的文本内容
+//
+// 返回值:
+//   - error: 转换编码、分配内存或设置数据时发生的错误
+func setTextData(text string) error {
 	// 将Go字符串转换为UTF-16编码的字节切片
 	utf16Text, err := windows.UTF16FromString(text)
 	if err != nil {
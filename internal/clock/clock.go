@@ -0,0 +1,127 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock 时间源接口
+// 需要定时行为的组件通过该接口获取时间和创建定时器，测试时可替换为Fake以精确控制时间流逝
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+
+	// AfterFunc 在指定时长后于独立协程中调用f
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer 由AfterFunc创建的定时器
+type Timer interface {
+	// Stop 停止定时器，如果定时器在触发前被停止则返回true
+	Stop() bool
+}
+
+// systemClock 基于系统时间的实现
+type systemClock struct{}
+
+// System 返回使用系统时间的Clock
+func System() Clock {
+	return systemClock{}
+}
+
+// Now 返回系统当前时间
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc 使用time.AfterFunc创建定时器
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// Fake 手动推进的时间源，用于测试
+// 时间只在调用Advance时前进，到期的定时器在Advance中按到期顺序同步执行
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// fakeTimer Fake创建的定时器
+type fakeTimer struct {
+	clock   *Fake
+	when    time.Time
+	f       func()
+	stopped bool
+}
+
+// NewFake 创建从指定时间开始的Fake时间源
+// 参数:
+//   - start: 初始时间
+//
+// 返回值:
+//   - *Fake: 时间源实例
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now 返回Fake当前时间
+func (c *Fake) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// AfterFunc 注册一个在Fake时间到达后执行的定时器
+func (c *Fake) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, when: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance 推进时间并执行所有到期的定时器
+// 参数:
+//   - d: 推进的时长
+func (c *Fake) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var due, pending []*fakeTimer
+	for _, t := range c.timers {
+		if t.stopped {
+			continue
+		}
+		if !t.when.After(c.now) {
+			due = append(due, t)
+		} else {
+			pending = append(pending, t)
+		}
+	}
+	c.timers = pending
+	c.mu.Unlock()
+
+	// 在锁外执行回调，允许回调中再次访问时间源
+	sort.SliceStable(due, func(i, j int) bool { return due[i].when.Before(due[j].when) })
+	for _, t := range due {
+		t.f()
+	}
+}
+
+// Stop 停止Fake定时器
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	if t.stopped {
+		return false
+	}
+	t.stopped = true
+	for _, pending := range t.clock.timers {
+		if pending == t {
+			return true
+		}
+	}
+	// 定时器已经触发
+	return false
+}
@@ -0,0 +1,42 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake_AdvanceFiresDueTimersInOrder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	var fired []string
+	c.AfterFunc(2*time.Minute, func() { fired = append(fired, "b") })
+	c.AfterFunc(time.Minute, func() { fired = append(fired, "a") })
+	c.AfterFunc(time.Hour, func() { fired = append(fired, "c") })
+
+	c.Advance(5 * time.Minute)
+
+	if got := c.Now(); !got.Equal(start.Add(5 * time.Minute)) {
+		t.Errorf("expected time to advance by 5m, got %v", got)
+	}
+	if len(fired) != 2 || fired[0] != "a" || fired[1] != "b" {
+		t.Errorf("expected timers a,b to fire in order, got %v", fired)
+	}
+}
+
+func TestFake_StopPreventsFiring(t *testing.T) {
+	c := NewFake(time.Unix(0, 0))
+	fired := false
+	timer := c.AfterFunc(time.Second, func() { fired = true })
+
+	if !timer.Stop() {
+		t.Error("expected Stop to report the timer was pending")
+	}
+	c.Advance(time.Minute)
+	if fired {
+		t.Error("stopped timer should not fire")
+	}
+	if timer.Stop() {
+		t.Error("expected second Stop to return false")
+	}
+}
@@ -6,32 +6,155 @@ import "time"
 // 这个结构体定义了应用程序运行所需的各种参数，通过修改这些参数
 // 可以调整应用程序的行为，如转换策略、通知方式和日志详细程度等
 type Config struct {
-	PollInterval time.Duration // 轮询间隔（仅在无法使用剪贴板监听API时使用）
+	PollInterval time.Duration `json:"-"` // 轮询间隔（仅在无法使用剪贴板监听API时使用）
 	// 当Windows剪贴板监听API不可用或权限不足时，应用程序会回退到轮询模式
 	// 该值定义了两次检查剪贴板内容之间的时间间隔，值越小响应越快但CPU占用越高
 
-	AutoConvert bool // 是否自动转换路径
+	AutoConvert bool `json:"auto_convert"` // 是否自动转换路径
 	// 控制应用程序是否在检测到剪贴板内容变化时自动执行路径转换
 	// 设为false时，应用程序会监听剪贴板变化但不会执行实际转换
 
-	ShowNotifications bool // 是否显示转换通知
-	// 控制当路径被转换时是否在日志和通知中显示详细信息
+	ShowNotifications bool `json:"show_notifications"` // 是否显示转换通知
+	// 控制当路径被转换时是否弹出托盘气泡通知（没有托盘图标时写入日志）
 	// 设为false时仅记录调试信息，不在用户界面显示转换详情
 
-	ExcludePatterns []string // 排除的模式列表
+	NotificationTemplate string `json:"notification_template"` // 转换通知的正文模板
+	// 使用Go text/template语法，可引用 .Original、.Converted、.Dialect 和 .Count（合并的转换次数）
+
+	ExcludePatterns []string `json:"exclude_patterns"` // 排除的模式列表
 	// 定义不需要进行路径转换的内容模式，支持通配符匹配
 	// 例如："*.exe", "http://*" 等，可以防止特定文件、URL等被错误转换
 
-	LogLevel string // 日志级别: debug, info, warn, error
+	LogLevel string `json:"log_level"` // 日志级别: debug, info, warn, error
 	// 控制日志输出的详细程度，不同级别输出不同数量的信息：
 	// - debug: 最详细，包括所有内部操作和状态
 	// - info: 适中，包括用户关心的操作和状态变化
 	// - warn: 只包含警告和错误信息
 	// - error: 只包含错误信息
 
-	MutexName string // 互斥量名称，用于防止多个实例同时运行
+	MutexName string `json:"mutex_name"` // 互斥量名称，用于防止多个实例同时运行
 	// Windows互斥锁名称，确保同一时间只有一个程序实例在运行
 	// 不同程序应使用不同的互斥量名称，避免相互冲突
+
+	PipeName string `json:"pipe_name"` // 控制通道名称，用于与正在运行的实例通信
+	// Windows下对应命名管道 \\.\pipe\<PipeName>-<用户SID>-<会话ID>，只允许当前用户访问；其他平台对应临时目录下的Unix套接字
+	// 第二次启动程序时通过该通道发送 status、pause 等控制命令
+
+	Hotkeys map[string]string `json:"hotkeys"` // 全局热键绑定，动作名称到热键字符串的映射
+	// 例如 {"convert": "Ctrl+Alt+V", "toggle": "Ctrl+Alt+P"}，值为空表示不绑定
+	// 关闭自动转换并绑定convert热键即为手动模式：只有按下热键时才转换剪贴板
+
+	PasteAfterConvert bool `json:"paste_after_convert"` // 热键转换后是否自动发送粘贴
+	// 设为true时，按下convert热键会在转换完成后模拟Ctrl+V粘贴到当前窗口
+
+	Dialect string `json:"dialect"` // 默认输出格式: forward, wsl, msys, escaped, original, smb, file-uri, json, c, go, python, csharp, string-forward
+	// 自动转换和热键转换使用的路径格式，例如 forward 输出 C:/Users，wsl 输出 /mnt/c/Users
+
+	CycleDialects []string `json:"cycle_dialects"` // cycle热键依次轮换的输出格式
+	// 按下cycle热键时，最近一次复制的原始路径按此顺序在各格式之间切换
+
+	UNCMode string `json:"unc_mode"` // 映射网络驱动器与UNC路径的转换方向: none, to-unc, to-drive
+	// to-unc 将 Z:\builds 转换为 \\fileserver\builds，便于没有映射该驱动器的同事使用
+	// to-drive 则相反，将UNC路径转换为本机映射的驱动器路径
+
+	UNCDrives map[string]string `json:"unc_drives"` // 固定的驱动器映射表，如 {"Z:": "\\\\fileserver\\builds"}
+	// 优先于系统查询结果，可用于描述本机并未映射、但同事常用的驱动器
+
+	PathMappings []PathMapping `json:"path_mappings"` // 路径前缀映射规则
+	// 远程开发时将Windows路径转换为远程主机上的对应路径，如 C:\work → /home/me/work
+	// 按最长前缀匹配，Windows侧不区分大小写；reverse热键按相同规则反向转换
+
+	EnvMode string `json:"env_mode"` // %VAR% 形式环境变量的处理方式: keep, expand, posix, posix-braced
+	// expand 展开为当前环境中的值；posix 转换为 $VAR，posix-braced 转换为 ${VAR}，便于在Unix shell中使用
+
+	ContractPaths string `json:"contract_paths"` // 路径收缩方式: none, env, tilde
+	// env 将 C:\Users\me\.ssh 收缩为 %USERPROFILE%\.ssh（再按env_mode处理），tilde 收缩为 ~\.ssh
+
+	QuotePolicy string `json:"quote_policy"` // 转换结果的加引号策略: none, keep, always, auto
+	// keep 保留原文本的双引号；always 和 auto 去掉原有引号，按shell的规则重新加引号（auto只在需要时加）
+
+	Shell string `json:"shell"` // 加引号时遵循的目标shell: posix(bash), powershell, cmd, fish
+	// 决定引号类型以及 $、反引号、单引号、! 等字符的转义方式
+
+	Normalize bool `json:"normalize"` // 是否在转换前规范化路径
+	// 去掉 . 和重复的分隔符并消解 ..，如 C:\a\b\..\c\\d → C:\a\c\d；只处理文本，不访问文件系统
+
+	TrailingSeparator string `json:"trailing_separator"` // 规范化时结尾分隔符的处理方式: keep, strip, add
+	// keep 保留原路径结尾的分隔符，strip 总是去掉，add 总是添加
+
+	DriveCase string `json:"drive_case"` // 盘符的大小写: keep, lower, upper
+	// 不影响 wsl、msys 格式，其挂载点总是小写
+
+	ResolveCase bool `json:"resolve_case"` // 是否按文件系统中的实际大小写还原路径
+	// 如 c:\users\ME\repo → C:\Users\me\repo；需要逐级列出目录，不存在的部分保持原样
+
+	BaseDir string `json:"base_dir"` // 解析相对路径的基准目录，如 "D:\\src\\app"
+	// 构建输出中的 src\pkg\file.go:12:3 解析为基准目录下的绝对路径后再转换；为空时不解析，pin 命令可以临时覆盖
+
+	ExistenceCheck string `json:"existence_check"` // 转换前检查路径是否存在: off, path, parent
+	// path 要求路径本身存在，parent 允许路径或其所在目录存在；无法确认存在的路径不转换
+
+	AllowedRoots []string `json:"allowed_roots"` // 无需检查存在性即可转换的根目录，如 ["D:\\src", "\\\\nas\\proj"]
+	// 适用于离线的网络共享或尚未创建的目录
+
+	FSTimeout string `json:"fs_timeout"` // 存在性检查和大小写还原中单次文件系统查询的超时时间，如 "200ms"
+	// 超时的路径视为存在且不还原大小写，避免慢速网络共享阻塞剪贴板处理
+
+	FSCacheTTL string `json:"fs_cache_ttl"` // 文件系统查询结果的缓存时间，如 "1m"
+
+	NamespacePrefixes map[string]string `json:"namespace_prefixes"` // 各输出格式对 \\?\、\\.\、\??\ 前缀的处理方式: strip, translate, keep
+	// 如 {"forward": "translate"}；未列出的格式默认去掉前缀，escaped 和反斜杠的字符串字面量默认保留前缀，只有 forward、escaped 和字符串字面量可以使用 translate
+
+	ClassPolicies map[string]string `json:"class_policies"` // 各类含反斜杠文本的转换策略: convert, skip
+	// 类别有 file、registry（注册表键）、account（DOMAIN\user 账户名）、provider（HKLM:\ 等PowerShell路径）和 unknown
+	// 默认只转换 file，如 {"registry": "convert"} 可恢复对注册表键的转换
+
+	DetectionThreshold float64 `json:"detection_threshold"` // 路径识别得分的阈值，0到1之间
+	// 含反斜杠的文本按盘符、段数、扩展名、转义序列和自然语言比例等特征打分，低于阈值时不转换
+	// 调高可减少对正文、LaTeX和正则表达式的误转换，调低到0.4可以转换 docs\readme 这样只有两段的相对路径
+
+	SplitLists bool `json:"split_lists"` // 是否将路径列表拆分后逐个转换
+	// 分号分隔的列表（如PATH的值）和多行文本中的每个路径分别转换；wsl、msys 格式下分号列表改用冒号拼接，得到有效的Unix PATH
+
+	DropNonPaths bool `json:"drop_non_paths"` // 拆分列表时是否去掉不是路径的元素（包括空行）
+	// 默认只有每个元素都是路径的文本才按列表拆分；开启后混有其他内容的文本也会拆分，并只保留其中的路径
+
+	Markdown string `json:"markdown"` // Markdown感知转换: off, auto, on
+	// 启用后正文和行内代码中的路径被转换，``` 代码块（如批处理脚本）保持原样；auto 只在文本含有代码块时启用
+
+	MarkdownFenceLanguages []string `json:"markdown_fence_languages"` // 需要转换其中路径的代码块语言，如 ["bash", "sh"]
+	// 空字符串表示没有声明语言的代码块
+
+	Structured bool `json:"structured"` // 是否按结构转换JSON、YAML和INI/.env片段
+	// 启用后只转换其中像路径的字符串值并按原格式重新编码，键、注释和其他值保持不变；无法解析时按普通文本转换
+
+	DelayedRendering bool `json:"delayed_rendering"` // 是否在粘贴时才按目标程序决定输出格式
+	// 启用后复制路径时只登记剪贴板格式，第一个读取剪贴板的程序决定输出格式，仅在剪贴板监听模式下有效
+
+	TargetDialects []TargetDialect `json:"target_dialects"` // 目标程序到输出格式的匹配规则，按顺序匹配
+	// 如 [{"process": "wsl.exe", "dialect": "wsl"}]；没有规则匹配时使用 dialect
+
+	HistorySize int `json:"history_size"` // 保留的最近转换记录条数
+	// 转换记录用于格式轮换等功能，仅保存在内存中
+
+	ShowTrayIcon bool `json:"show_tray_icon"` // 是否在通知区域显示托盘图标
+	// 托盘菜单提供暂停/恢复、输出格式选择、最近转换记录、打开配置和日志以及退出等操作
+
+	LogFile string `json:"log_file"` // 日志文件路径，为空时只输出到控制台
+	// 设置后日志会同时写入该文件，托盘菜单的"打开日志文件"也使用该路径
+}
+
+// PathMapping 一条路径前缀映射规则
+type PathMapping struct {
+	Windows string `json:"windows"` // Windows侧的路径前缀，如 C:\work 或 \\nas\proj
+	Target  string `json:"target"`  // 目标侧的路径前缀，如 /home/me/work
+}
+
+// TargetDialect 一条目标程序到输出格式的匹配规则
+type TargetDialect struct {
+	Process string `json:"process"` // 进程文件名的通配符模式，如 WindowsTerminal.exe、mintty*
+	Title   string `json:"title"`   // 窗口标题的通配符模式，为空时不检查标题
+	Dialect string `json:"dialect"` // 匹配时使用的输出格式
 }
 
 // DefaultConfig 返回应用程序的默认配置
@@ -51,6 +174,9 @@ func DefaultConfig() *Config {
 		// 帮助用户理解程序的工作状态和转换结果
 		ShowNotifications: true,
 
+		// 默认通知显示转换前后的内容
+		NotificationTemplate: "{{.Original}} → {{.Converted}}",
+
 		// 默认排除所有URL和特殊协议，避免错误转换网络链接和协议内容
 		// 这些模式不会被当作路径处理，防止破坏有用的URL和协议内容
 		ExcludePatterns: []string{
@@ -65,5 +191,89 @@ func DefaultConfig() *Config {
 		// 默认互斥量名称，确保程序的单一实例运行
 		// 如果需要同时运行多个版本或变体，应修改此名称
 		MutexName: "PathConvertToolMutex",
+
+		// 默认控制通道名称，与互斥量名称保持同一前缀便于识别
+		PipeName: "PathConvertToolControl",
+
+		// 默认不绑定任何热键，避免与其他程序的快捷键冲突
+		Hotkeys: map[string]string{},
+
+		// 默认只转换不粘贴，由用户自行决定粘贴位置
+		PasteAfterConvert: false,
+
+		// 默认输出正斜杠格式，与早期版本的行为保持一致
+		Dialect: "forward",
+
+		// 默认在最常用的几种格式之间轮换，最后回到原始内容
+		CycleDialects: []string{"forward", "wsl", "escaped", "original"},
+
+		// 默认不在映射驱动器与UNC路径之间转换
+		UNCMode: "none",
+
+		// 默认没有固定的驱动器映射，只使用系统查询结果
+		UNCDrives: map[string]string{},
+
+		// 默认没有路径映射规则
+		PathMappings: []PathMapping{},
+
+		// 默认保持环境变量不变，也不收缩路径
+		EnvMode:       "keep",
+		ContractPaths: "none",
+
+		// 默认保留原文本的引号，与早期版本的行为保持一致
+		QuotePolicy: "keep",
+		Shell:       "posix",
+
+		// 默认不规范化路径，保留结尾分隔符
+		Normalize:         false,
+		TrailingSeparator: "keep",
+
+		// 默认保持路径的大小写
+		DriveCase:   "keep",
+		ResolveCase: false,
+
+		// 默认不解析相对路径
+		BaseDir: "",
+
+		// 默认不检查路径是否存在
+		ExistenceCheck: "off",
+		AllowedRoots:   []string{},
+
+		// 默认的文件系统查询超时与缓存时间
+		FSTimeout:  "200ms",
+		FSCacheTTL: "1m",
+
+		// 默认使用各输出格式的前缀处理方式
+		NamespacePrefixes: map[string]string{},
+
+		// 默认使用各类别的默认转换策略
+		ClassPolicies: map[string]string{},
+
+		// 默认阈值按标注样本调整，盘符路径和UNC路径总是高于该值
+		DetectionThreshold: 0.5,
+
+		// 默认逐个转换列表中的路径，保留不是路径的元素
+		SplitLists:   true,
+		DropNonPaths: false,
+
+		// 默认不按Markdown处理，启用后所有代码块都保持原样
+		Markdown:               "off",
+		MarkdownFenceLanguages: []string{},
+
+		// 默认按结构转换JSON、YAML和INI片段，避免破坏其中的转义
+		Structured: true,
+
+		// 默认在复制时立即转换，不按目标程序选择输出格式
+		DelayedRendering: false,
+		TargetDialects:   []TargetDialect{},
+
+		// 默认保留最近10条转换记录
+		HistorySize: 10,
+
+		// 默认显示托盘图标，便于在没有控制台的情况下操作程序
+		ShowTrayIcon: true,
+
+		// 默认不写日志文件
+		LogFile: "",
 	}
 }
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// configFileName 默认配置文件名
+const configFileName = "config.json"
+
+// appDirName 用户配置目录下的应用子目录名称
+const appDirName = "win-path-convert"
+
+// fileConfig 配置文件的JSON映射结构
+// 通过嵌入Config的别名类型复用字段定义，仅对需要特殊格式的字段单独声明
+// 例如时间间隔在文件中以 "100ms" 这样的字符串表示
+type fileConfig struct {
+	*configAlias
+	PollInterval string `json:"poll_interval,omitempty"` // 轮询间隔，使用time.ParseDuration格式
+}
+
+// configAlias Config的别名类型，避免JSON编解码时递归调用自定义方法
+type configAlias Config
+
+// DefaultConfigPath 返回默认配置文件路径
+// 配置文件位于用户配置目录（Windows下为 %APPDATA%）下的 win-path-convert 子目录中
+// 返回值:
+//   - string: 配置文件的完整路径，无法获取用户配置目录时返回当前目录下的文件名
+func DefaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return configFileName
+	}
+	return filepath.Join(dir, appDirName, configFileName)
+}
+
+// LoadConfig 从JSON文件加载配置
+// 该函数以默认配置为基础，用文件中出现的字段覆盖默认值
+// 文件不存在时不视为错误，直接返回默认配置
+// 参数:
+//   - path: 配置文件路径
+//
+// 返回值:
+//   - *Config: 合并后的配置对象
+//   - error: 读取或解析文件时发生的错误
+func LoadConfig(path string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// 配置文件不存在，使用默认配置
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("无法读取配置文件: %v", err)
+	}
+
+	fc := fileConfig{configAlias: (*configAlias)(cfg)}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("无法解析配置文件 %s: %v", path, err)
+	}
+
+	// 解析字符串格式的轮询间隔
+	if fc.PollInterval != "" {
+		d, err := time.ParseDuration(fc.PollInterval)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("无效的轮询间隔 %q", fc.PollInterval)
+		}
+		cfg.PollInterval = d
+	}
+
+	return cfg, nil
+}
+
+// SaveConfig 将配置以JSON格式写入文件
+// 该函数会自动创建配置文件所在的目录，常用于生成初始配置文件供用户编辑
+// 参数:
+//   - cfg: 要保存的配置对象
+//   - path: 配置文件路径
+//
+// 返回值:
+//   - error: 写入过程中发生的错误
+func SaveConfig(cfg *Config, path string) error {
+	fc := fileConfig{
+		configAlias:  (*configAlias)(cfg),
+		PollInterval: cfg.PollInterval.String(),
+	}
+	data, err := json.MarshalIndent(fc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("无法序列化配置: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("无法创建配置目录: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("无法写入配置文件: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig_MissingFileReturnsDefaults(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.PollInterval != 100*time.Millisecond || !cfg.AutoConvert {
+		t.Errorf("expected default config, got %+v", cfg)
+	}
+}
+
+func TestLoadConfig_OverridesFields(t *testing.T) {
+	path := writeConfigFile(t, `{
+		"auto_convert": false,
+		"log_level": "debug",
+		"poll_interval": "250ms",
+		"pipe_name": "TestPipe"
+	}`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AutoConvert {
+		t.Error("expected AutoConvert to be overridden to false")
+	}
+	if cfg.LogLevel != "debug" {
+		t.Errorf("expected LogLevel 'debug', got %q", cfg.LogLevel)
+	}
+	if cfg.PollInterval != 250*time.Millisecond {
+		t.Errorf("expected PollInterval 250ms, got %v", cfg.PollInterval)
+	}
+	if cfg.PipeName != "TestPipe" {
+		t.Errorf("expected PipeName 'TestPipe', got %q", cfg.PipeName)
+	}
+	// 未出现在文件中的字段保留默认值
+	if !cfg.ShowNotifications {
+		t.Error("expected ShowNotifications to keep its default")
+	}
+}
+
+func TestLoadConfig_InvalidContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"Malformed JSON", `{"auto_convert": `},
+		{"Bad duration", `{"poll_interval": "soon"}`},
+		{"Negative duration", `{"poll_interval": "-1s"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadConfig(writeConfigFile(t, tt.content)); err == nil {
+				t.Errorf("expected error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestSaveConfig_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.json")
+	cfg := DefaultConfig()
+	cfg.PollInterval = 2 * time.Second
+	cfg.Dialect = "wsl"
+	cfg.Hotkeys["convert"] = "Ctrl+Alt+V"
+
+	if err := SaveConfig(cfg, path); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if loaded.PollInterval != 2*time.Second || loaded.Dialect != "wsl" || loaded.Hotkeys["convert"] != "Ctrl+Alt+V" {
+		t.Errorf("config did not round-trip: %+v", loaded)
+	}
+}
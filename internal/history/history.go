@@ -0,0 +1,99 @@
+package history
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry 一次转换记录
+type Entry struct {
+	Original  string    // 复制时的原始内容
+	Converted string    // 写回剪贴板的内容
+	Dialect   string    // 生成Converted使用的输出格式
+	Time      time.Time // 转换时间
+}
+
+// Store 最近转换记录的内存存储
+// 超出容量时丢弃最早的记录，所有方法都可以在多个协程中并发调用
+type Store struct {
+	mu       sync.Mutex
+	entries  []Entry // 按时间顺序保存，最新的记录在末尾
+	capacity int     // 最多保存的记录数
+}
+
+// NewStore 创建转换记录存储
+// 参数:
+//   - capacity: 最多保存的记录数，小于1时按1处理
+//
+// 返回值:
+//   - *Store: 存储实例
+func NewStore(capacity int) *Store {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Store{capacity: capacity}
+}
+
+// Add 添加一条记录
+// 参数:
+//   - e: 转换记录
+func (s *Store) Add(e Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, e)
+	if over := len(s.entries) - s.capacity; over > 0 {
+		s.entries = append(s.entries[:0:0], s.entries[over:]...)
+	}
+}
+
+// Latest 返回最新的记录
+// 返回值:
+//   - Entry: 最新记录
+//   - bool: 存储为空时返回false
+func (s *Store) Latest() (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return Entry{}, false
+	}
+	return s.entries[len(s.entries)-1], true
+}
+
+// UpdateLatest 更新最新记录的转换结果，原始内容保持不变
+// 用于在不同输出格式之间轮换同一原始内容
+// 参数:
+//   - converted: 新的剪贴板内容
+//   - dialect: 生成该内容使用的输出格式
+//
+// 返回值:
+//   - bool: 存储为空时返回false
+func (s *Store) UpdateLatest(converted, dialect string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return false
+	}
+	latest := &s.entries[len(s.entries)-1]
+	latest.Converted = converted
+	latest.Dialect = dialect
+	return true
+}
+
+// Recent 返回最近的n条记录，最新的在前
+// 参数:
+//   - n: 最多返回的记录数
+//
+// 返回值:
+//   - []Entry: 记录副本
+func (s *Store) Recent(n int) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n > len(s.entries) {
+		n = len(s.entries)
+	}
+	out := make([]Entry, 0, n)
+	for i := len(s.entries) - 1; i >= 0 && len(out) < n; i-- {
+		out = append(out, s.entries[i])
+	}
+	return out
+}
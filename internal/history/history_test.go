@@ -0,0 +1,47 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore_KeepsNewestWithinCapacity(t *testing.T) {
+	s := NewStore(3)
+	if _, ok := s.Latest(); ok {
+		t.Fatal("expected empty store to have no latest entry")
+	}
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		s.Add(Entry{Original: name, Time: time.Now()})
+	}
+
+	recent := s.Recent(10)
+	if len(recent) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(recent))
+	}
+	if recent[0].Original != "d" || recent[2].Original != "b" {
+		t.Errorf("expected newest-first b..d, got %+v", recent)
+	}
+	if latest, _ := s.Latest(); latest.Original != "d" {
+		t.Errorf("expected latest entry d, got %q", latest.Original)
+	}
+	if got := s.Recent(1); len(got) != 1 || got[0].Original != "d" {
+		t.Errorf("expected Recent(1) to return only d, got %+v", got)
+	}
+}
+
+func TestStore_UpdateLatest(t *testing.T) {
+	s := NewStore(5)
+	if s.UpdateLatest("x", "wsl") {
+		t.Fatal("expected UpdateLatest on empty store to fail")
+	}
+
+	s.Add(Entry{Original: `C:\a`, Converted: "C:/a", Dialect: "forward"})
+	if !s.UpdateLatest("/mnt/c/a", "wsl") {
+		t.Fatal("expected UpdateLatest to succeed")
+	}
+	latest, _ := s.Latest()
+	if latest.Original != `C:\a` || latest.Converted != "/mnt/c/a" || latest.Dialect != "wsl" {
+		t.Errorf("unexpected entry after update: %+v", latest)
+	}
+}
@@ -0,0 +1,72 @@
+package history
+
+// Rotation 在配置的输出格式之间轮换同一原始内容
+// 每次调用Next切换到列表中的下一个格式，到达末尾后回到开头；
+// 原始内容变化时需要先调用Reset，从该内容当前使用的格式之后开始轮换
+type Rotation struct {
+	dialects []string // 参与轮换的输出格式
+	original string   // 正在轮换的原始内容
+	pos      int      // 当前格式在dialects中的位置，-1表示不在列表中
+}
+
+// NewRotation 创建轮换状态机
+// 参数:
+//   - dialects: 参与轮换的输出格式，按轮换顺序排列
+//
+// 返回值:
+//   - *Rotation: 状态机实例
+func NewRotation(dialects []string) *Rotation {
+	return &Rotation{dialects: append([]string(nil), dialects...), pos: -1}
+}
+
+// Original 返回正在轮换的原始内容
+func (r *Rotation) Original() string {
+	return r.original
+}
+
+// Current 返回当前所处的输出格式，不在列表中时返回空字符串
+func (r *Rotation) Current() string {
+	if r.pos < 0 || r.pos >= len(r.dialects) {
+		return ""
+	}
+	return r.dialects[r.pos]
+}
+
+// Reset 开始轮换新的原始内容
+// 参数:
+//   - original: 原始内容
+//   - current: 剪贴板中当前内容使用的输出格式
+func (r *Rotation) Reset(original, current string) {
+	r.original = original
+	r.pos = -1
+	for i, d := range r.dialects {
+		if d == current {
+			r.pos = i
+			break
+		}
+	}
+}
+
+// Next 切换到下一个会改变剪贴板内容的格式
+// 渲染结果与当前内容相同的格式会被跳过，例如UNC路径在正斜杠和WSL格式下结果一致
+// 参数:
+//   - current: 剪贴板中的当前内容
+//   - render: 将原始内容渲染为指定格式的函数
+//
+// 返回值:
+//   - string: 选中的输出格式
+//   - string: 该格式下的内容
+//   - bool: 所有格式的结果都与当前内容相同时返回false
+func (r *Rotation) Next(current string, render func(dialect string) string) (string, string, bool) {
+	n := len(r.dialects)
+	for step := 1; step <= n; step++ {
+		i := (r.pos + step) % n
+		text := render(r.dialects[i])
+		if text == current {
+			continue
+		}
+		r.pos = i
+		return r.dialects[i], text, true
+	}
+	return "", "", false
+}
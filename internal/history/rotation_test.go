@@ -0,0 +1,88 @@
+package history
+
+import (
+	"testing"
+)
+
+// renderFor 返回一个按格式查表的渲染函数
+func renderFor(outputs map[string]string) func(string) string {
+	return func(d string) string { return outputs[d] }
+}
+
+func TestRotation_CyclesFromCurrentDialect(t *testing.T) {
+	outputs := map[string]string{
+		"forward":  "C:/a",
+		"wsl":      "/mnt/c/a",
+		"escaped":  `"C:\\a"`,
+		"original": `C:\a`,
+	}
+	r := NewRotation([]string{"forward", "wsl", "escaped", "original"})
+	r.Reset(`C:\a`, "forward")
+
+	current := "C:/a"
+	var got []string
+	for i := 0; i < 5; i++ {
+		d, text, ok := r.Next(current, renderFor(outputs))
+		if !ok {
+			t.Fatalf("step %d: expected a next dialect", i)
+		}
+		if text != outputs[d] {
+			t.Fatalf("step %d: text %q does not match dialect %s", i, text, d)
+		}
+		got = append(got, d)
+		current = text
+	}
+
+	want := []string{"wsl", "escaped", "original", "forward", "wsl"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("rotation order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRotation_SkipsDialectsWithIdenticalOutput(t *testing.T) {
+	// UNC路径在正斜杠和WSL格式下结果相同，轮换时应跳过
+	outputs := map[string]string{
+		"forward":  "//srv/share",
+		"wsl":      "//srv/share",
+		"original": `\\srv\share`,
+	}
+	r := NewRotation([]string{"forward", "wsl", "original"})
+	r.Reset(`\\srv\share`, "forward")
+
+	d, _, ok := r.Next("//srv/share", renderFor(outputs))
+	if !ok || d != "original" {
+		t.Fatalf("expected to skip wsl and land on original, got %q, %v", d, ok)
+	}
+	d, _, ok = r.Next(`\\srv\share`, renderFor(outputs))
+	if !ok || d != "forward" {
+		t.Fatalf("expected to wrap around to forward, got %q, %v", d, ok)
+	}
+}
+
+func TestRotation_UnknownCurrentDialectStartsAtBeginning(t *testing.T) {
+	r := NewRotation([]string{"forward", "wsl"})
+	r.Reset(`C:\a`, "msys")
+	if r.Original() != `C:\a` || r.Current() != "" {
+		t.Fatalf("expected original to be tracked without a current dialect, got %q, %q", r.Original(), r.Current())
+	}
+
+	d, _, ok := r.Next("/c/a", renderFor(map[string]string{"forward": "C:/a", "wsl": "/mnt/c/a"}))
+	if !ok || d != "forward" || r.Current() != "forward" {
+		t.Fatalf("expected rotation to start at forward, got %q, %v", d, ok)
+	}
+}
+
+func TestRotation_NothingToRotate(t *testing.T) {
+	r := NewRotation([]string{"forward", "wsl"})
+	r.Reset("plain", "forward")
+	if _, _, ok := r.Next("plain", func(string) string { return "plain" }); ok {
+		t.Fatal("expected no rotation when every dialect renders the same text")
+	}
+
+	empty := NewRotation(nil)
+	if _, _, ok := empty.Next("x", func(string) string { return "y" }); ok {
+		t.Fatal("expected empty rotation to report no next dialect")
+	}
+}
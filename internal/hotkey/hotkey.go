@@ -0,0 +1,227 @@
+package hotkey
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Modifiers 热键修饰键组合，取值与Windows RegisterHotKey的MOD_*常量一致
+type Modifiers uint32
+
+const (
+	ModAlt      Modifiers = 0x0001 // Alt键
+	ModControl  Modifiers = 0x0002 // Ctrl键
+	ModShift    Modifiers = 0x0004 // Shift键
+	ModWin      Modifiers = 0x0008 // Windows徽标键
+	ModNoRepeat Modifiers = 0x4000 // 按住不放时不重复触发
+)
+
+// modifierNames 修饰键名称（小写）到修饰键的映射
+var modifierNames = map[string]Modifiers{
+	"ctrl":    ModControl,
+	"control": ModControl,
+	"alt":     ModAlt,
+	"shift":   ModShift,
+	"win":     ModWin,
+	"super":   ModWin,
+}
+
+// namedKeys 非字母数字按键名称（小写）到Windows虚拟键码的映射
+var namedKeys = map[string]uint32{
+	"space":       0x20,
+	"enter":       0x0D,
+	"return":      0x0D,
+	"tab":         0x09,
+	"esc":         0x1B,
+	"escape":      0x1B,
+	"backspace":   0x08,
+	"insert":      0x2D,
+	"ins":         0x2D,
+	"delete":      0x2E,
+	"del":         0x2E,
+	"home":        0x24,
+	"end":         0x23,
+	"pageup":      0x21,
+	"pgup":        0x21,
+	"pagedown":    0x22,
+	"pgdn":        0x22,
+	"left":        0x25,
+	"up":          0x26,
+	"right":       0x27,
+	"down":        0x28,
+	"pause":       0x13,
+	"printscreen": 0x2C,
+}
+
+// Hotkey 一个全局热键
+type Hotkey struct {
+	Modifiers Modifiers // 修饰键组合
+	Key       uint32    // Windows虚拟键码
+}
+
+// Parse 解析形如 "Ctrl+Alt+V" 的热键字符串
+// 名称不区分大小写，各部分以+分隔；支持字母、数字、F1-F24及常用功能键
+// 除F1-F24外，热键必须至少包含一个修饰键，避免拦截普通输入
+// 参数:
+//   - s: 热键字符串
+//
+// 返回值:
+//   - Hotkey: 解析得到的热键
+//   - error: 字符串无效时返回错误
+func Parse(s string) (Hotkey, error) {
+	var hk Hotkey
+	parts := strings.Split(s, "+")
+	if strings.TrimSpace(s) == "" {
+		return hk, fmt.Errorf("热键不能为空")
+	}
+
+	for i, part := range parts {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			return hk, fmt.Errorf("无效的热键 %q: 存在空的按键名称", s)
+		}
+
+		// 最后一部分为主键，其余部分必须是修饰键
+		if i < len(parts)-1 {
+			mod, ok := modifierNames[name]
+			if !ok {
+				return hk, fmt.Errorf("无效的热键 %q: 未知的修饰键 %q", s, part)
+			}
+			if hk.Modifiers&mod != 0 {
+				return hk, fmt.Errorf("无效的热键 %q: 重复的修饰键 %q", s, part)
+			}
+			hk.Modifiers |= mod
+			continue
+		}
+
+		if _, isMod := modifierNames[name]; isMod {
+			return hk, fmt.Errorf("无效的热键 %q: 缺少主键", s)
+		}
+		key, ok := keyCode(name)
+		if !ok {
+			return hk, fmt.Errorf("无效的热键 %q: 未知的按键 %q", s, part)
+		}
+		hk.Key = key
+	}
+
+	if hk.Modifiers == 0 && !isFunctionKey(hk.Key) {
+		return hk, fmt.Errorf("无效的热键 %q: 至少需要一个修饰键", s)
+	}
+	return hk, nil
+}
+
+// keyCode 将按键名称转换为虚拟键码
+func keyCode(name string) (uint32, bool) {
+	if len(name) == 1 {
+		c := name[0]
+		switch {
+		case c >= 'a' && c <= 'z':
+			// 字母键的虚拟键码与大写ASCII码相同
+			return uint32(c - 'a' + 'A'), true
+		case c >= '0' && c <= '9':
+			return uint32(c), true
+		}
+	}
+
+	if strings.HasPrefix(name, "f") {
+		if n, err := strconv.Atoi(name[1:]); err == nil && n >= 1 && n <= 24 {
+			// VK_F1 = 0x70
+			return uint32(0x70 + n - 1), true
+		}
+	}
+
+	key, ok := namedKeys[name]
+	return key, ok
+}
+
+// isFunctionKey 报告虚拟键码是否为F1-F24
+func isFunctionKey(key uint32) bool {
+	return key >= 0x70 && key <= 0x87
+}
+
+// keyName 返回虚拟键码的显示名称
+func keyName(key uint32) string {
+	switch {
+	case key >= 'A' && key <= 'Z', key >= '0' && key <= '9':
+		return string(rune(key))
+	case isFunctionKey(key):
+		return "F" + strconv.Itoa(int(key-0x70+1))
+	}
+
+	// 同一键码可能有多个别名，选择字典序最小的名称保证输出稳定
+	best := ""
+	for name, code := range namedKeys {
+		if code == key && (best == "" || name < best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return fmt.Sprintf("0x%02X", key)
+	}
+	return strings.ToUpper(best[:1]) + best[1:]
+}
+
+// String 返回规范格式的热键字符串，修饰键按 Ctrl、Alt、Shift、Win 顺序排列
+func (h Hotkey) String() string {
+	var parts []string
+	if h.Modifiers&ModControl != 0 {
+		parts = append(parts, "Ctrl")
+	}
+	if h.Modifiers&ModAlt != 0 {
+		parts = append(parts, "Alt")
+	}
+	if h.Modifiers&ModShift != 0 {
+		parts = append(parts, "Shift")
+	}
+	if h.Modifiers&ModWin != 0 {
+		parts = append(parts, "Win")
+	}
+	parts = append(parts, keyName(h.Key))
+	return strings.Join(parts, "+")
+}
+
+// Binding 热键与动作的绑定
+type Binding struct {
+	ID     int    // 注册热键时使用的标识符，从1开始
+	Action string // 动作名称，由应用程序解释
+	Hotkey Hotkey // 触发动作的热键
+}
+
+// ParseBindings 解析配置中的动作到热键映射
+// 值为空字符串的动作被忽略；绑定按动作名称排序并依次分配ID，保证多次解析结果一致。
+// 无效的绑定被跳过，不影响其他绑定；多个动作使用同一热键时，按名称排在前面的动作生效
+// 参数:
+//   - m: 动作名称到热键字符串的映射
+//
+// 返回值:
+//   - []Binding: 有效的绑定列表
+//   - []error: 每个被跳过的绑定对应一个错误
+func ParseBindings(m map[string]string) ([]Binding, []error) {
+	actions := make([]string, 0, len(m))
+	for action, spec := range m {
+		if strings.TrimSpace(spec) != "" {
+			actions = append(actions, action)
+		}
+	}
+	sort.Strings(actions)
+
+	bindings := make([]Binding, 0, len(actions))
+	used := make(map[Hotkey]string, len(actions))
+	var errs []error
+	for _, action := range actions {
+		hk, err := Parse(m[action])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("动作 %s: %v", action, err))
+			continue
+		}
+		if other, dup := used[hk]; dup {
+			errs = append(errs, fmt.Errorf("热键 %s 同时绑定了动作 %s 和 %s，忽略 %s", hk, other, action, action))
+			continue
+		}
+		used[hk] = action
+		bindings = append(bindings, Binding{ID: len(bindings) + 1, Action: action, Hotkey: hk})
+	}
+	return bindings, errs
+}
@@ -0,0 +1,118 @@
+package hotkey
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		modifiers Modifiers
+		key       uint32
+	}{
+		{"Ctrl+Alt+V", "Ctrl+Alt+V", ModControl | ModAlt, 'V'},
+		{"Lowercase with spaces", " ctrl + shift + c ", ModControl | ModShift, 'C'},
+		{"Digit", "Alt+1", ModAlt, '1'},
+		{"Function key alone", "F9", 0, 0x78},
+		{"F24", "Win+F24", ModWin, 0x87},
+		{"Named key", "Ctrl+PageDown", ModControl, 0x22},
+		{"Alias", "Control+Super+Esc", ModControl | ModWin, 0x1B},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hk, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
+			}
+			if hk.Modifiers != tt.modifiers || hk.Key != tt.key {
+				t.Errorf("Parse(%q) = %+v, want modifiers %#x key %#x", tt.input, hk, tt.modifiers, tt.key)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"V",
+		"Ctrl+",
+		"Ctrl+Alt",
+		"Hyper+V",
+		"Ctrl+Ctrl+V",
+		"Ctrl+F25",
+		"Ctrl+VV",
+	}
+
+	for _, input := range inputs {
+		if hk, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) = %+v, expected error", input, hk)
+		}
+	}
+}
+
+func TestHotkey_String(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"alt+ctrl+v", "Ctrl+Alt+V"},
+		{"win+shift+f12", "Shift+Win+F12"},
+		{"ctrl+del", "Ctrl+Del"},
+		{"ctrl+return", "Ctrl+Enter"},
+	}
+
+	for _, tt := range tests {
+		hk, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
+		}
+		if got := hk.String(); got != tt.expected {
+			t.Errorf("String() for %q = %q, want %q", tt.input, got, tt.expected)
+		}
+		// 规范格式可以被重新解析为相同的热键
+		if again, err := Parse(hk.String()); err != nil || again != hk {
+			t.Errorf("String() output %q does not round-trip: %+v, %v", hk.String(), again, err)
+		}
+	}
+}
+
+func TestParseBindings(t *testing.T) {
+	bindings, errs := ParseBindings(map[string]string{
+		"toggle":  "Ctrl+Alt+P",
+		"convert": "Ctrl+Alt+V",
+		"cycle":   "",
+	})
+	if errs != nil {
+		t.Fatalf("ParseBindings returned errors: %v", errs)
+	}
+	if len(bindings) != 2 {
+		t.Fatalf("expected 2 bindings, got %d", len(bindings))
+	}
+	if bindings[0].Action != "convert" || bindings[0].ID != 1 || bindings[0].Hotkey.Key != 'V' {
+		t.Errorf("unexpected first binding: %+v", bindings[0])
+	}
+	if bindings[1].Action != "toggle" || bindings[1].ID != 2 {
+		t.Errorf("unexpected second binding: %+v", bindings[1])
+	}
+}
+
+func TestParseBindings_Errors(t *testing.T) {
+	bindings, errs := ParseBindings(map[string]string{"convert": "Ctrl+Alt+V", "toggle": "alt+ctrl+v"})
+	if len(errs) != 1 {
+		t.Errorf("expected one error for duplicate hotkeys, got %v", errs)
+	}
+	if len(bindings) != 1 || bindings[0].Action != "convert" {
+		t.Errorf("the first of the duplicate bindings should be kept, got %+v", bindings)
+	}
+
+	// 一个无效的绑定不影响其他绑定
+	bindings, errs = ParseBindings(map[string]string{"convert": "Nope+V", "reverse": "Ctrl+Alt+R", "toggle": "Ctrl+Alt+P"})
+	if len(errs) != 1 {
+		t.Errorf("expected one error for the invalid hotkey, got %v", errs)
+	}
+	if len(bindings) != 2 || bindings[0].Action != "reverse" || bindings[0].ID != 1 || bindings[1].ID != 2 {
+		t.Errorf("valid bindings should still be parsed, got %+v", bindings)
+	}
+}
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"github.com/lyj404/win-path-convert/internal/logger"
+	"github.com/lyj404/win-path-convert/internal/pathconv"
 )
 
 // IPathConverter 路径转换器接口
@@ -13,6 +14,60 @@ type IPathConverter interface {
 	// Convert 将Windows路径转换为Unix风格路径
 	Convert(text string) string
 
+	// ConvertTo 将Windows路径转换为指定输出格式
+	ConvertTo(text string, d pathconv.Dialect) string
+
+	// SetDialect 设置默认输出格式
+	SetDialect(d pathconv.Dialect)
+
+	// Dialect 返回默认输出格式
+	Dialect() pathconv.Dialect
+
+	// SetUNCResolution 设置映射驱动器与UNC路径的转换方式
+	SetUNCResolution(mode pathconv.UNCMode, r pathconv.Resolver)
+
+	// SetMappings 设置路径前缀映射
+	SetMappings(m *pathconv.Mapper)
+
+	// SetEnvOptions 设置环境变量的展开、语法转换与路径收缩
+	SetEnvOptions(mode pathconv.EnvMode, contract pathconv.ContractMode, env pathconv.Env)
+
+	// SetQuoting 设置转换结果的加引号策略
+	SetQuoting(policy pathconv.QuotePolicy, sh pathconv.Shell)
+
+	// SetNormalization 设置路径的词法规范化
+	SetNormalization(enabled bool, trailing pathconv.TrailingPolicy)
+
+	// SetPrefixPolicies 设置各输出格式对带命名空间前缀的路径的处理方式
+	SetPrefixPolicies(policies map[pathconv.Dialect]pathconv.PrefixPolicy)
+
+	// SetClassPolicies 设置各类文本的转换策略
+	SetClassPolicies(policies map[pathconv.Class]pathconv.ClassPolicy)
+
+	// SetThreshold 设置路径识别得分的阈值
+	SetThreshold(threshold float64)
+
+	// SetListOptions 设置路径列表的转换方式
+	SetListOptions(split, dropNonPaths bool)
+
+	// SetMarkdown 设置Markdown感知转换
+	SetMarkdown(mode pathconv.MarkdownMode, fenceLanguages []string)
+
+	// SetStructured 设置是否按结构转换JSON、YAML和INI片段
+	SetStructured(enabled bool)
+
+	// SetCaseOptions 设置盘符大小写与路径大小写的还原
+	SetCaseOptions(driveCase pathconv.DriveCase, resolver pathconv.CaseResolver)
+
+	// SetExistenceCheck 设置转换前的存在性检查
+	SetExistenceCheck(mode pathconv.ExistMode, checker pathconv.ExistenceChecker, allowedRoots []string)
+
+	// SetBaseDir 设置解析相对路径的基准目录
+	SetBaseDir(dir string)
+
+	// ConvertReverse 将目标路径转换回Windows路径
+	ConvertReverse(text string) string
+
 	// UpdateExcludePatterns 更新排除模式
 	UpdateExcludePatterns(patterns []string)
 }
@@ -34,6 +89,18 @@ type IClipboardManager interface {
 
 	// SetLastContentHash 设置最近一次内容的哈希
 	SetLastContentHash(hash string)
+
+	// SetDelayedText 以延迟渲染的方式占有剪贴板文本
+	SetDelayedText(hwnd uintptr) error
+
+	// RenderText 在处理WM_RENDERFORMAT消息时提供剪贴板文本
+	RenderText(text string) error
+
+	// RenderAllText 在处理WM_RENDERALLFORMATS消息时提供剪贴板文本
+	RenderAllText(hwnd uintptr, text string) error
+
+	// IsOwner 报告窗口是否为剪贴板的所有者
+	IsOwner(hwnd uintptr) bool
 }
 
 // ILogger 日志接口
@@ -48,3 +115,6 @@ type ILogger interface {
 
 // 确保具体的 Logger 实现满足接口
 var _ ILogger = (*logger.Logger)(nil)
+
+// 确保具体的 PathConverter 实现满足接口
+var _ IPathConverter = (*pathconv.PathConverter)(nil)
@@ -0,0 +1,54 @@
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultTimeout 客户端等待响应的默认超时时间
+const DefaultTimeout = 5 * time.Second
+
+// ErrNotRunning 表示控制通道不存在，即没有正在运行的实例
+var ErrNotRunning = errors.New("没有正在运行的实例")
+
+// Send 向正在运行的实例发送一条控制请求并等待响应
+// 参数:
+//   - endpoint: 控制通道地址
+//   - req: 要发送的请求
+//   - timeout: 等待响应的超时时间
+//
+// 返回值:
+//   - Response: 服务端返回的响应
+//   - error: 连接、收发或超时错误；没有实例运行时返回ErrNotRunning
+func Send(endpoint string, req Request, timeout time.Duration) (Response, error) {
+	conn, err := dial(endpoint, timeout)
+	if err != nil {
+		return Response{}, err
+	}
+
+	type result struct {
+		resp Response
+		err  error
+	}
+	done := make(chan result, 1)
+
+	// 部分平台的管道句柄不支持读写超时，因此在协程中完成收发并统一计时
+	go func() {
+		var r result
+		if r.err = writeMessage(conn, req); r.err == nil {
+			r.err = readMessage(conn, &r.resp)
+		}
+		done <- r
+	}()
+
+	select {
+	case r := <-done:
+		conn.Close()
+		return r.resp, r.err
+	case <-time.After(timeout):
+		// 关闭连接以解除协程中的阻塞读写
+		conn.Close()
+		return Response{}, fmt.Errorf("等待响应超时（%v）", timeout)
+	}
+}
@@ -0,0 +1,123 @@
+package ipc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/logger"
+)
+
+func startTestServer(t *testing.T, h Handler) string {
+	t.Helper()
+	endpoint := filepath.Join(t.TempDir(), "control.sock")
+	srv := NewServer(endpoint, h, logger.NewLogger("error"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := srv.Start(ctx); err != nil {
+		cancel()
+		t.Fatalf("failed to start server: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		srv.Close()
+	})
+	return endpoint
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	req := NewRequest(CmdPause, "a", "b")
+	if err := writeMessage(&buf, req); err != nil {
+		t.Fatalf("writeMessage failed: %v", err)
+	}
+
+	var got Request
+	if err := readMessage(&buf, &got); err != nil {
+		t.Fatalf("readMessage failed: %v", err)
+	}
+	if got.Version != ProtocolVersion || got.Command != CmdPause || len(got.Args) != 2 {
+		t.Errorf("unexpected request after round trip: %+v", got)
+	}
+}
+
+func TestSend_DispatchesToHandler(t *testing.T) {
+	endpoint := startTestServer(t, HandlerFunc(func(req Request) Response {
+		if req.Command != CmdStatus {
+			return ErrorResponse("未知命令: %s", req.Command)
+		}
+		return OKResponse("运行中", map[string]string{"paused": "false"})
+	}))
+
+	resp, err := Send(endpoint, NewRequest(CmdStatus), time.Second)
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if !resp.OK || resp.Data["paused"] != "false" || resp.Version != ProtocolVersion {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	resp, err = Send(endpoint, NewRequest("bogus"), time.Second)
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if resp.OK || resp.Error == "" {
+		t.Errorf("expected error response for unknown command, got %+v", resp)
+	}
+}
+
+func TestSend_RejectsUnsupportedVersion(t *testing.T) {
+	called := false
+	endpoint := startTestServer(t, HandlerFunc(func(req Request) Response {
+		called = true
+		return OKResponse("", nil)
+	}))
+
+	for _, version := range []int{0, ProtocolVersion + 1} {
+		resp, err := Send(endpoint, Request{Version: version, Command: CmdStatus}, time.Second)
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		if resp.OK {
+			t.Errorf("expected version %d to be rejected", version)
+		}
+	}
+	if called {
+		t.Error("handler should not be called for unsupported versions")
+	}
+}
+
+func TestSend_NotRunning(t *testing.T) {
+	endpoint := filepath.Join(t.TempDir(), "missing.sock")
+	if _, err := Send(endpoint, NewRequest(CmdStatus), time.Second); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("expected ErrNotRunning, got %v", err)
+	}
+}
+
+func TestServer_StopsOnContextCancel(t *testing.T) {
+	endpoint := filepath.Join(t.TempDir(), "control.sock")
+	srv := NewServer(endpoint, HandlerFunc(func(req Request) Response {
+		return OKResponse("", nil)
+	}), logger.NewLogger("error"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := srv.Start(ctx); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, err := Send(endpoint, NewRequest(CmdStatus), 100*time.Millisecond)
+		if errors.Is(err, ErrNotRunning) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server still reachable after cancel, last error: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
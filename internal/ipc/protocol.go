@@ -0,0 +1,104 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ProtocolVersion 当前控制协议版本
+// 客户端与服务端在每条消息中携带版本号，服务端拒绝处理高于自身版本的请求，
+// 以便新旧版本程序同时存在时给出明确的错误提示
+const ProtocolVersion = 1
+
+// maxMessageSize 单条消息的最大字节数，防止异常客户端占用过多内存
+const maxMessageSize = 64 * 1024
+
+// 控制命令名称
+const (
+	CmdStatus     = "status"      // 查询运行状态
+	CmdPause      = "pause"       // 暂停自动转换
+	CmdResume     = "resume"      // 恢复自动转换
+	CmdSnooze     = "snooze"      // 按时长或复制次数暂停自动转换
+	CmdReload     = "reload"      // 重新加载配置文件
+	CmdQuit       = "quit"        // 退出正在运行的实例
+	CmdConvertNow = "convert-now" // 立即转换当前剪贴板内容
+	CmdPin        = "pin"         // 固定项目目录，作为相对路径的基准目录
+	CmdUnpin      = "unpin"       // 取消固定的项目目录
+)
+
+// Request 控制请求
+// 每个连接只传输一条请求和一条响应，消息以单行JSON编码
+type Request struct {
+	Version int      `json:"version"`        // 协议版本
+	Command string   `json:"command"`        // 命令名称
+	Args    []string `json:"args,omitempty"` // 命令参数
+}
+
+// Response 控制响应
+type Response struct {
+	Version int               `json:"version"`           // 协议版本
+	OK      bool              `json:"ok"`                // 命令是否执行成功
+	Message string            `json:"message,omitempty"` // 面向用户的结果描述
+	Data    map[string]string `json:"data,omitempty"`    // 附加的键值数据，如状态信息
+	Error   string            `json:"error,omitempty"`   // 失败原因
+}
+
+// NewRequest 创建携带当前协议版本的请求
+// 参数:
+//   - command: 命令名称
+//   - args: 命令参数
+//
+// 返回值:
+//   - Request: 请求对象
+func NewRequest(command string, args ...string) Request {
+	return Request{Version: ProtocolVersion, Command: command, Args: args}
+}
+
+// OKResponse 创建表示成功的响应
+// 参数:
+//   - message: 结果描述
+//   - data: 附加数据，可以为nil
+//
+// 返回值:
+//   - Response: 响应对象
+func OKResponse(message string, data map[string]string) Response {
+	return Response{Version: ProtocolVersion, OK: true, Message: message, Data: data}
+}
+
+// ErrorResponse 创建表示失败的响应
+// 参数:
+//   - format: 格式化字符串
+//   - args: 格式化参数
+//
+// 返回值:
+//   - Response: 响应对象
+func ErrorResponse(format string, args ...any) Response {
+	return Response{Version: ProtocolVersion, Error: fmt.Sprintf(format, args...)}
+}
+
+// writeMessage 将消息编码为单行JSON写入连接
+func writeMessage(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("无法编码消息: %v", err)
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("无法发送消息: %v", err)
+	}
+	return nil
+}
+
+// readMessage 从连接读取一行JSON并解码
+func readMessage(r io.Reader, v any) error {
+	reader := bufio.NewReader(io.LimitReader(r, maxMessageSize))
+	line, err := reader.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		return fmt.Errorf("无法读取消息: %v", err)
+	}
+	if err := json.Unmarshal(line, v); err != nil {
+		return fmt.Errorf("无法解码消息: %v", err)
+	}
+	return nil
+}
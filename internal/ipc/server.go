@@ -0,0 +1,163 @@
+package ipc
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/lyj404/win-path-convert/internal/logger"
+)
+
+// Handler 控制命令处理器
+// 由应用程序实现，根据请求执行对应操作并返回响应
+type Handler interface {
+	HandleRequest(req Request) Response
+}
+
+// HandlerFunc 允许普通函数作为Handler使用
+type HandlerFunc func(req Request) Response
+
+// HandleRequest 调用函数本身处理请求
+func (f HandlerFunc) HandleRequest(req Request) Response {
+	return f(req)
+}
+
+// listener 控制通道监听器
+// 不同平台的传输层（Windows命名管道、Unix套接字）都实现该接口
+type listener interface {
+	Accept() (io.ReadWriteCloser, error)
+	Close() error
+}
+
+// Server 控制通道服务端
+// 在正在运行的实例中监听控制通道，将收到的请求交给Handler处理
+type Server struct {
+	endpoint string         // 监听地址
+	handler  Handler        // 命令处理器
+	logger   *logger.Logger // 日志记录器
+
+	mu     sync.Mutex     // 保护ln字段
+	ln     listener       // 底层监听器
+	wg     sync.WaitGroup // 等待所有连接处理完成
+	closed bool           // 服务端是否已关闭
+}
+
+// NewServer 创建控制通道服务端
+// 参数:
+//   - endpoint: 监听地址，通常由EndpointFor生成
+//   - h: 命令处理器
+//   - l: 日志记录器
+//
+// 返回值:
+//   - *Server: 服务端实例，调用Start后开始监听
+func NewServer(endpoint string, h Handler, l *logger.Logger) *Server {
+	return &Server{endpoint: endpoint, handler: h, logger: l}
+}
+
+// Start 开始监听控制通道
+// 监听建立后在后台协程中接受连接，上下文取消时自动关闭
+// 参数:
+//   - ctx: 控制服务端生命周期的上下文
+//
+// 返回值:
+//   - error: 建立监听失败时返回错误
+func (s *Server) Start(ctx context.Context) error {
+	ln, err := listen(s.endpoint)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.ln = ln
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.acceptLoop(ln)
+
+	// 上下文取消时关闭监听器，使acceptLoop退出
+	go func() {
+		<-ctx.Done()
+		s.Close()
+	}()
+	return nil
+}
+
+// acceptLoop 循环接受连接并分发处理
+func (s *Server) acceptLoop(ln listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return
+			}
+			s.logger.Warn("控制通道接受连接失败: %v", err)
+			continue
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+// serveConn 处理单个连接上的一次请求
+func (s *Server) serveConn(conn io.ReadWriteCloser) {
+	defer conn.Close()
+
+	var req Request
+	if err := readMessage(conn, &req); err != nil {
+		s.logger.Debug("控制通道读取请求失败: %v", err)
+		_ = writeMessage(conn, ErrorResponse("%v", err))
+		return
+	}
+
+	s.logger.Debug("收到控制命令: %s %v", req.Command, req.Args)
+	resp := s.dispatch(req)
+	if err := writeMessage(conn, resp); err != nil {
+		s.logger.Debug("控制通道发送响应失败: %v", err)
+	}
+}
+
+// dispatch 校验协议版本后交给Handler处理
+func (s *Server) dispatch(req Request) Response {
+	if req.Version <= 0 || req.Version > ProtocolVersion {
+		return ErrorResponse("不支持的协议版本 %d（当前版本 %d）", req.Version, ProtocolVersion)
+	}
+	if req.Command == "" {
+		return ErrorResponse("缺少命令名称")
+	}
+	resp := s.handler.HandleRequest(req)
+	resp.Version = ProtocolVersion
+	return resp
+}
+
+// isClosed 报告服务端是否已关闭
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// Close 关闭控制通道并等待进行中的请求处理完毕
+// 返回值:
+//   - error: 关闭监听器时发生的错误
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	ln := s.ln
+	s.mu.Unlock()
+
+	var err error
+	if ln != nil {
+		err = ln.Close()
+	}
+	s.wg.Wait()
+	return err
+}
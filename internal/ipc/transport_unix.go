@@ -0,0 +1,64 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// EndpointFor 根据通道名称生成Unix套接字路径
+// 非Windows平台仅用于测试，套接字位于系统临时目录下
+// 参数:
+//   - name: 通道名称
+//
+// 返回值:
+//   - string: 套接字文件路径
+func EndpointFor(name string) string {
+	return filepath.Join(os.TempDir(), name+".sock")
+}
+
+// unixListener 基于Unix套接字的监听器
+type unixListener struct {
+	ln net.Listener
+}
+
+// listen 在指定路径上监听Unix套接字
+func listen(endpoint string) (listener, error) {
+	// 清理上次异常退出遗留的套接字文件
+	if err := os.Remove(endpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &unixListener{ln: ln}, nil
+}
+
+// Accept 接受一个连接
+func (l *unixListener) Accept() (io.ReadWriteCloser, error) {
+	return l.ln.Accept()
+}
+
+// Close 关闭监听器，同时删除套接字文件
+func (l *unixListener) Close() error {
+	return l.ln.Close()
+}
+
+// dial 连接Unix套接字
+func dial(endpoint string, timeout time.Duration) (io.ReadWriteCloser, error) {
+	conn, err := net.DialTimeout("unix", endpoint, timeout)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, ErrNotRunning
+		}
+		return nil, err
+	}
+	return conn, nil
+}
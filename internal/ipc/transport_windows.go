@@ -0,0 +1,218 @@
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// pipeBufferSize 命名管道的输入输出缓冲区大小
+const pipeBufferSize = 4096
+
+// errListenerClosed 监听器已关闭时Accept返回的错误
+var errListenerClosed = errors.New("控制通道已关闭")
+
+// currentUser 当前用户的SID与会话ID，首次使用时查询
+var currentUser = sync.OnceValues(func() (string, uint32) {
+	var sid string
+	if u, err := windows.GetCurrentProcessToken().GetTokenUser(); err == nil {
+		sid = u.User.Sid.String()
+	}
+	var session uint32
+	windows.ProcessIdToSessionId(windows.GetCurrentProcessId(), &session)
+	return sid, session
+})
+
+// EndpointFor 根据通道名称生成命名管道路径
+// 管道名称对整台计算机可见，而单例互斥量只在会话内有效，因此名称中加入用户SID和会话ID，
+// 使每个会话中的实例各自使用独立的管道，控制命令只会发给同一会话中的实例
+// 参数:
+//   - name: 通道名称
+//
+// 返回值:
+//   - string: 形如 \\.\pipe\<name>-<SID>-<会话ID> 的管道路径
+func EndpointFor(name string) string {
+	sid, session := currentUser()
+	if sid == "" {
+		return fmt.Sprintf(`\\.\pipe\%s-%d`, name, session)
+	}
+	return fmt.Sprintf(`\\.\pipe\%s-%s-%d`, name, sid, session)
+}
+
+// pipeSecurity 返回只允许当前用户访问管道的安全属性
+// 默认的安全描述符允许所有人读取管道，这里改为只授予当前用户完全访问权限
+func pipeSecurity() (*windows.SecurityAttributes, error) {
+	sid, _ := currentUser()
+	if sid == "" {
+		return nil, fmt.Errorf("无法获取当前用户的SID")
+	}
+	sd, err := windows.SecurityDescriptorFromString("D:P(A;;GA;;;" + sid + ")")
+	if err != nil {
+		return nil, fmt.Errorf("无法创建管道的安全描述符: %v", err)
+	}
+	sa := &windows.SecurityAttributes{SecurityDescriptor: sd}
+	sa.Length = uint32(unsafe.Sizeof(*sa))
+	return sa, nil
+}
+
+// pipeListener 基于Windows命名管道的监听器
+// 每次Accept使用一个新的管道实例并阻塞等待客户端连接
+type pipeListener struct {
+	path   string         // 管道路径
+	mu     sync.Mutex     // 保护closed与next字段
+	closed bool           // 监听器是否已关闭
+	next   windows.Handle // 预先创建、尚未使用的管道实例
+}
+
+// listen 创建命名管道监听器
+// 第一个管道实例使用FILE_FLAG_FIRST_PIPE_INSTANCE创建，确保同名管道未被其他进程占用
+func listen(endpoint string) (listener, error) {
+	l := &pipeListener{path: endpoint}
+	h, err := l.createInstance(windows.FILE_FLAG_FIRST_PIPE_INSTANCE)
+	if err != nil {
+		return nil, err
+	}
+	l.next = h
+	return l, nil
+}
+
+// createInstance 创建一个管道实例
+func (l *pipeListener) createInstance(extraFlags uint32) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(l.path)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+	sa, err := pipeSecurity()
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+
+	h, err := windows.CreateNamedPipe(
+		name,
+		windows.PIPE_ACCESS_DUPLEX|extraFlags,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+		windows.PIPE_UNLIMITED_INSTANCES,
+		pipeBufferSize,
+		pipeBufferSize,
+		0,
+		sa,
+	)
+	if err != nil {
+		return windows.InvalidHandle, fmt.Errorf("无法创建命名管道 %s: %v", l.path, err)
+	}
+	return h, nil
+}
+
+// Accept 创建管道实例并等待客户端连接
+func (l *pipeListener) Accept() (io.ReadWriteCloser, error) {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, errListenerClosed
+	}
+	h := l.next
+	l.next = 0
+	l.mu.Unlock()
+
+	if h == 0 {
+		var err error
+		if h, err = l.createInstance(0); err != nil {
+			return nil, err
+		}
+	}
+
+	// 阻塞等待客户端连接；客户端在实例创建后、等待前已连接时返回ERROR_PIPE_CONNECTED
+	err := windows.ConnectNamedPipe(h, nil)
+	if err != nil && err != windows.ERROR_PIPE_CONNECTED {
+		windows.CloseHandle(h)
+		return nil, fmt.Errorf("等待管道连接失败: %v", err)
+	}
+
+	// Close通过连接一次管道来唤醒阻塞中的ConnectNamedPipe，此时直接丢弃该连接
+	if l.isClosed() {
+		windows.CloseHandle(h)
+		return nil, errListenerClosed
+	}
+	return &pipeConn{File: os.NewFile(uintptr(h), l.path), handle: h}, nil
+}
+
+// isClosed 报告监听器是否已关闭
+func (l *pipeListener) isClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// Close 关闭监听器并唤醒阻塞中的Accept
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	pending := l.next
+	l.next = 0
+	l.mu.Unlock()
+
+	// 尚未被Accept使用的实例直接关闭
+	if pending != 0 {
+		windows.CloseHandle(pending)
+	}
+	// 以客户端身份连接一次，使阻塞中的ConnectNamedPipe返回
+	if conn, err := dial(l.path, time.Second); err == nil {
+		conn.Close()
+	}
+	return nil
+}
+
+// pipeConn 服务端的管道连接
+type pipeConn struct {
+	*os.File
+	handle windows.Handle
+}
+
+// Close 确保响应被客户端读取后再断开连接
+func (c *pipeConn) Close() error {
+	windows.FlushFileBuffers(c.handle)
+	windows.DisconnectNamedPipe(c.handle)
+	return c.File.Close()
+}
+
+// dial 连接命名管道
+// 所有管道实例都忙时在超时时间内重试
+func dial(endpoint string, timeout time.Duration) (io.ReadWriteCloser, error) {
+	name, err := windows.UTF16PtrFromString(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		h, err := windows.CreateFile(
+			name,
+			windows.GENERIC_READ|windows.GENERIC_WRITE,
+			0,
+			nil,
+			windows.OPEN_EXISTING,
+			0,
+			0,
+		)
+		if err == nil {
+			return os.NewFile(uintptr(h), endpoint), nil
+		}
+		if err == windows.ERROR_FILE_NOT_FOUND {
+			return nil, ErrNotRunning
+		}
+		if err != windows.ERROR_PIPE_BUSY || time.Now().After(deadline) {
+			return nil, fmt.Errorf("无法连接控制通道: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
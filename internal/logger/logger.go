@@ -77,6 +77,11 @@ func parseLogLevel(levelStr string) LogLevel {
 	}
 }
 
+// ParseLevel 将字符串解析为日志级别，无法识别时返回INFO
+func ParseLevel(levelStr string) LogLevel {
+	return parseLogLevel(levelStr)
+}
+
 // SetOutputFile 设置日志输出到文件
 func (l *Logger) SetOutputFile(filePath string) error {
 	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+// Dispatcher 将转换事件格式化为通知并限制发送频率
+// 连续复制多个路径时逐条弹出通知会干扰用户，这里采用"首条立即发送、
+// 窗口期内合并"的策略：窗口期内的后续事件被合并为一条通知，在窗口结束时发送，
+// 合并通知的正文使用最后一次转换，标题显示合并的次数
+type Dispatcher struct {
+	mu        sync.Mutex
+	notifier  Notifier        // 实际发送通知的对象
+	formatter *Formatter      // 通知正文格式化器
+	clock     clock.Clock     // 时间源，测试时可替换
+	window    time.Duration   // 合并窗口，不大于0时不限制频率
+	onError   func(err error) // 格式化或发送失败时的回调
+	pending   *Event          // 窗口期内等待合并发送的事件
+	timer     clock.Timer     // 当前窗口的结束定时器，为nil表示不在窗口期内
+}
+
+// NewDispatcher 创建通知分发器
+// 参数:
+//   - n: 通知发送器
+//   - f: 通知正文格式化器
+//   - c: 时间源
+//   - window: 合并窗口
+//
+// 返回值:
+//   - *Dispatcher: 分发器实例
+func NewDispatcher(n Notifier, f *Formatter, c clock.Clock, window time.Duration) *Dispatcher {
+	return &Dispatcher{notifier: n, formatter: f, clock: c, window: window}
+}
+
+// SetFormatter 替换通知正文格式化器，用于重新加载配置
+func (d *Dispatcher) SetFormatter(f *Formatter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.formatter = f
+}
+
+// SetOnError 设置格式化或发送失败时的回调
+func (d *Dispatcher) SetOnError(f func(err error)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onError = f
+}
+
+// Post 提交一次转换事件
+// 不在窗口期内时立即发送并开启新窗口，否则与窗口期内的其他事件合并
+// 参数:
+//   - e: 转换事件，Count为0时按1计
+func (d *Dispatcher) Post(e Event) {
+	if e.Count <= 0 {
+		e.Count = 1
+	}
+
+	d.mu.Lock()
+	if d.timer != nil {
+		if d.pending != nil {
+			e.Count += d.pending.Count
+		}
+		d.pending = &e
+		d.mu.Unlock()
+		return
+	}
+	d.startWindowLocked()
+	d.sendAndUnlock(e)
+}
+
+// Close 停止合并窗口并立即发送尚未发送的合并通知
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	pending := d.pending
+	d.pending = nil
+	if pending == nil {
+		d.mu.Unlock()
+		return
+	}
+	d.sendAndUnlock(*pending)
+}
+
+// flush 窗口结束时发送合并的通知
+// 有事件被发送时开启新的窗口，使持续的复制操作仍然受到频率限制
+func (d *Dispatcher) flush() {
+	d.mu.Lock()
+	d.timer = nil
+	pending := d.pending
+	d.pending = nil
+	if pending == nil {
+		d.mu.Unlock()
+		return
+	}
+	d.startWindowLocked()
+	d.sendAndUnlock(*pending)
+}
+
+// startWindowLocked 开启合并窗口，调用方需持有锁
+func (d *Dispatcher) startWindowLocked() {
+	if d.window > 0 {
+		d.timer = d.clock.AfterFunc(d.window, d.flush)
+	}
+}
+
+// sendAndUnlock 释放锁后格式化并发送通知，调用方需持有锁
+// 发送气泡通知可能较慢，不在持锁状态下进行
+func (d *Dispatcher) sendAndUnlock(e Event) {
+	formatter, notifier, onError := d.formatter, d.notifier, d.onError
+	d.mu.Unlock()
+
+	n, err := formatter.Format(e)
+	if err == nil {
+		err = notifier.Notify(n)
+	}
+	if err != nil && onError != nil {
+		onError(err)
+	}
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+// newTestDispatcher 创建使用假时钟和记录器的分发器
+func newTestDispatcher(t *testing.T, window time.Duration) (*Dispatcher, *Recorder, *clock.Fake) {
+	t.Helper()
+	f, err := NewFormatter("{{.Converted}}")
+	if err != nil {
+		t.Fatalf("NewFormatter failed: %v", err)
+	}
+	rec := &Recorder{}
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	return NewDispatcher(rec, f, clk, window), rec, clk
+}
+
+func TestDispatcher_FirstEventIsImmediate(t *testing.T) {
+	d, rec, _ := newTestDispatcher(t, 2*time.Second)
+	d.Post(Event{Converted: "a"})
+
+	got := rec.Notifications()
+	if len(got) != 1 || got[0].Body != "a" || got[0].Title != "路径已转换" {
+		t.Fatalf("notifications = %v", got)
+	}
+}
+
+func TestDispatcher_CoalescesBurst(t *testing.T) {
+	d, rec, clk := newTestDispatcher(t, 2*time.Second)
+	d.Post(Event{Converted: "a"})
+	d.Post(Event{Converted: "b"})
+	clk.Advance(time.Second)
+	d.Post(Event{Converted: "c"})
+
+	if n := len(rec.Notifications()); n != 1 {
+		t.Fatalf("got %d notifications inside the window, want 1", n)
+	}
+
+	clk.Advance(time.Second)
+	got := rec.Notifications()
+	if len(got) != 2 {
+		t.Fatalf("got %d notifications after the window, want 2", len(got))
+	}
+	if got[1].Body != "c" || got[1].Title != "已转换 2 个路径" {
+		t.Errorf("coalesced notification = %+v", got[1])
+	}
+}
+
+func TestDispatcher_QuietWindowResets(t *testing.T) {
+	d, rec, clk := newTestDispatcher(t, 2*time.Second)
+	d.Post(Event{Converted: "a"})
+	clk.Advance(2 * time.Second)
+	// 窗口内没有新事件，窗口结束后不应发送任何通知
+	if n := len(rec.Notifications()); n != 1 {
+		t.Fatalf("got %d notifications, want 1", n)
+	}
+
+	d.Post(Event{Converted: "b"})
+	got := rec.Notifications()
+	if len(got) != 2 || got[1].Body != "b" || got[1].Title != "路径已转换" {
+		t.Fatalf("notifications = %v", got)
+	}
+}
+
+func TestDispatcher_ZeroWindowSendsEverything(t *testing.T) {
+	d, rec, _ := newTestDispatcher(t, 0)
+	for _, s := range []string{"a", "b", "c"} {
+		d.Post(Event{Converted: s})
+	}
+	if n := len(rec.Notifications()); n != 3 {
+		t.Errorf("got %d notifications, want 3", n)
+	}
+}
+
+func TestDispatcher_CloseFlushesPending(t *testing.T) {
+	d, rec, clk := newTestDispatcher(t, 2*time.Second)
+	d.Post(Event{Converted: "a"})
+	d.Post(Event{Converted: "b"})
+	d.Close()
+
+	got := rec.Notifications()
+	if len(got) != 2 || got[1].Body != "b" {
+		t.Fatalf("notifications = %v", got)
+	}
+	// 关闭后定时器已停止，不应再发送
+	clk.Advance(time.Minute)
+	if n := len(rec.Notifications()); n != 2 {
+		t.Errorf("got %d notifications after Close, want 2", n)
+	}
+}
+
+func TestDispatcher_ReportsErrors(t *testing.T) {
+	f, _ := NewFormatter("{{.Missing}}")
+	var errs []error
+	d := NewDispatcher(&Recorder{}, f, clock.NewFake(time.Time{}), 0)
+	d.SetOnError(func(err error) { errs = append(errs, err) })
+	d.Post(Event{})
+	if len(errs) != 1 {
+		t.Errorf("got %d errors, want 1", len(errs))
+	}
+}
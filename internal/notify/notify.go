@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"text/template"
+
+	"github.com/lyj404/win-path-convert/internal/logger"
+)
+
+// DefaultTemplate 默认的通知正文模板，显示转换前后的内容
+const DefaultTemplate = "{{.Original}} → {{.Converted}}"
+
+// Notification 一条面向用户的通知
+type Notification struct {
+	Title string // 通知标题
+	Body  string // 通知正文
+}
+
+// Notifier 通知发送器
+// 不同的实现将通知显示为系统气泡、写入日志或记录下来供测试检查
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// Event 一次路径转换事件，是通知模板的数据来源
+type Event struct {
+	Original  string // 转换前的内容
+	Converted string // 转换后的内容
+	Dialect   string // 使用的输出格式
+	Count     int    // 合并的转换次数，单次转换时为1
+}
+
+// Formatter 按模板生成通知正文
+type Formatter struct {
+	tmpl *template.Template
+}
+
+// NewFormatter 解析通知正文模板
+// 模板使用text/template语法，可引用Event的字段，例如 {{.Original}} → {{.Converted}}
+// 参数:
+//   - text: 模板文本，为空时使用DefaultTemplate
+//
+// 返回值:
+//   - *Formatter: 格式化器实例
+//   - error: 模板语法错误时返回错误
+func NewFormatter(text string) (*Formatter, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("notification").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("无效的通知模板: %v", err)
+	}
+	return &Formatter{tmpl: tmpl}, nil
+}
+
+// Format 生成转换事件对应的通知
+// 参数:
+//   - e: 转换事件
+//
+// 返回值:
+//   - Notification: 通知内容
+//   - error: 模板执行失败时返回错误
+func (f *Formatter) Format(e Event) (Notification, error) {
+	var buf bytes.Buffer
+	if err := f.tmpl.Execute(&buf, e); err != nil {
+		return Notification{}, fmt.Errorf("无法生成通知内容: %v", err)
+	}
+	return Notification{Title: titleFor(e.Count), Body: buf.String()}, nil
+}
+
+// titleFor 根据合并的转换次数生成通知标题
+func titleFor(count int) string {
+	if count > 1 {
+		return fmt.Sprintf("已转换 %d 个路径", count)
+	}
+	return "路径已转换"
+}
+
+// LogNotifier 将通知写入日志
+// 在没有托盘图标（例如轮询模式）时作为兜底实现
+type LogNotifier struct {
+	log *logger.Logger
+}
+
+// NewLogNotifier 创建写入日志的通知发送器
+// 参数:
+//   - l: 日志记录器
+//
+// 返回值:
+//   - *LogNotifier: 通知发送器实例
+func NewLogNotifier(l *logger.Logger) *LogNotifier {
+	return &LogNotifier{log: l}
+}
+
+// Notify 以Info级别记录通知
+func (n *LogNotifier) Notify(msg Notification) error {
+	n.log.Info("%s: %s", msg.Title, msg.Body)
+	return nil
+}
+
+// Recorder 记录收到的通知，供测试检查
+type Recorder struct {
+	mu            sync.Mutex
+	notifications []Notification
+}
+
+// Notify 记录通知
+func (r *Recorder) Notify(n Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications = append(r.notifications, n)
+	return nil
+}
+
+// Notifications 返回已记录通知的副本，按发送顺序排列
+func (r *Recorder) Notifications() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Notification(nil), r.notifications...)
+}
+
+// fallback 依次尝试多个通知发送器，直到有一个成功
+type fallback []Notifier
+
+// Fallback 组合多个通知发送器
+// 前一个发送失败（例如托盘图标不可用）时改用下一个
+// 参数:
+//   - notifiers: 按优先级排列的通知发送器
+//
+// 返回值:
+//   - Notifier: 组合后的通知发送器，全部失败时返回最后一个错误
+func Fallback(notifiers ...Notifier) Notifier {
+	return fallback(notifiers)
+}
+
+// Notify 依次尝试发送通知
+func (f fallback) Notify(n Notification) error {
+	var err error
+	for _, notifier := range f {
+		if err = notifier.Notify(n); err == nil {
+			return nil
+		}
+	}
+	return err
+}
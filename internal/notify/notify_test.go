@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatter_DefaultTemplate(t *testing.T) {
+	f, err := NewFormatter("")
+	if err != nil {
+		t.Fatalf("NewFormatter failed: %v", err)
+	}
+	n, err := f.Format(Event{Original: `C:\a`, Converted: "C:/a", Count: 1})
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if n.Body != `C:\a → C:/a` {
+		t.Errorf("Body = %q", n.Body)
+	}
+	if n.Title != "路径已转换" {
+		t.Errorf("Title = %q", n.Title)
+	}
+}
+
+func TestFormatter_CustomTemplateAndCount(t *testing.T) {
+	f, err := NewFormatter("[{{.Dialect}}] {{.Converted}}")
+	if err != nil {
+		t.Fatalf("NewFormatter failed: %v", err)
+	}
+	n, err := f.Format(Event{Converted: "/mnt/c/a", Dialect: "wsl", Count: 3})
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if n.Body != "[wsl] /mnt/c/a" {
+		t.Errorf("Body = %q", n.Body)
+	}
+	if n.Title != "已转换 3 个路径" {
+		t.Errorf("Title = %q", n.Title)
+	}
+}
+
+func TestFormatter_InvalidTemplate(t *testing.T) {
+	if _, err := NewFormatter("{{.Original"); err == nil {
+		t.Error("expected a syntax error")
+	}
+	f, err := NewFormatter("{{.Missing}}")
+	if err != nil {
+		t.Fatalf("NewFormatter failed: %v", err)
+	}
+	if _, err := f.Format(Event{}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+// failingNotifier 总是发送失败的通知发送器
+type failingNotifier struct{ calls int }
+
+func (f *failingNotifier) Notify(Notification) error {
+	f.calls++
+	return errors.New("unavailable")
+}
+
+func TestFallback(t *testing.T) {
+	primary := &failingNotifier{}
+	rec := &Recorder{}
+	n := Fallback(primary, rec)
+
+	if err := n.Notify(Notification{Title: "t", Body: "b"}); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if primary.calls != 1 {
+		t.Errorf("primary called %d times, want 1", primary.calls)
+	}
+	if got := rec.Notifications(); len(got) != 1 || got[0].Body != "b" {
+		t.Errorf("recorded %v", got)
+	}
+
+	if err := Fallback(&failingNotifier{}).Notify(Notification{}); err == nil {
+		t.Error("expected an error when every notifier fails")
+	}
+}
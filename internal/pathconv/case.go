@@ -0,0 +1,197 @@
+package pathconv
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+// DriveCase 盘符的大小写
+type DriveCase string
+
+const (
+	DriveCaseKeep  DriveCase = "keep"  // 保持原样
+	DriveCaseLower DriveCase = "lower" // 小写，如 c:/Users
+	DriveCaseUpper DriveCase = "upper" // 大写，如 C:/Users
+)
+
+// ParseDriveCase 解析盘符的大小写，不区分大小写，空字符串视为keep
+// 参数:
+//   - name: 大小写名称
+//
+// 返回值:
+//   - DriveCase: 对应的大小写
+//   - error: 名称无效时返回错误
+func ParseDriveCase(name string) (DriveCase, error) {
+	switch c := DriveCase(strings.ToLower(strings.TrimSpace(name))); c {
+	case "":
+		return DriveCaseKeep, nil
+	case DriveCaseKeep, DriveCaseLower, DriveCaseUpper:
+		return c, nil
+	}
+	return "", fmt.Errorf("未知的盘符大小写: %s", name)
+}
+
+// applyDriveCase 按配置改写路径开头盘符的大小写
+// wsl 和 msys 格式的挂载点总是小写，不受影响
+func applyDriveCase(p string, c DriveCase) string {
+	if len(p) < 2 || p[1] != ':' || !isASCIILetter(p[0]) {
+		return p
+	}
+	switch c {
+	case DriveCaseLower:
+		return strings.ToLower(p[:1]) + p[1:]
+	case DriveCaseUpper:
+		return string(upper(p[0])) + p[1:]
+	}
+	return p
+}
+
+// CaseResolver 按文件系统中的实际大小写还原路径
+// Windows的文件系统不区分大小写，同一路径可以有多种写法，而WSL、Go模块缓存和Docker卷等区分大小写
+type CaseResolver interface {
+	// ResolveCase 返回与p指向同一文件、大小写与文件系统一致的路径
+	// 不存在的部分保持原样，无法解析时返回p
+	ResolveCase(p string) string
+}
+
+// EntryNameFS 能够直接查询单个目录项实际名称的文件系统
+// 实现该接口的文件系统无需列出整个目录即可还原大小写，其他文件系统退回到列出目录内容
+type EntryNameFS interface {
+	fs.FS
+
+	// EntryName 返回与name（以 / 分隔的路径）指向同一目录项的实际名称，只含最后一段
+	EntryName(name string) (string, error)
+}
+
+// FSCaseResolver 通过文件系统还原路径的大小写
+// 只处理盘符和UNC开头的绝对路径，盘符统一为大写；查询带超时，结果按LRU缓存一段时间（见timedLookup）
+type FSCaseResolver struct {
+	open   func(root string) fs.FS // 打开驱动器或共享的根目录，如 C:\ 或 \\server\share
+	lookup *timedLookup[[]string]  // 带超时和缓存的查询，结果为路径开头各段的实际名称
+}
+
+// NewFSCaseResolver 创建基于文件系统的大小写还原
+// 参数:
+//   - open: 按根目录打开文件系统，测试时可以使用内存中的文件系统
+//   - timeout: 单次还原的超时时间，不大于0时不限制
+//   - ttl: 还原结果的缓存时间，不大于0时不缓存
+//   - c: 时间源
+//
+// 返回值:
+//   - *FSCaseResolver: 大小写还原实例
+func NewFSCaseResolver(open func(root string) fs.FS, timeout, ttl time.Duration, c clock.Clock) *FSCaseResolver {
+	return &FSCaseResolver{open: open, lookup: newTimedLookup[[]string](timeout, ttl, c)}
+}
+
+// ResolveCase 用文件系统中的实际名称替换路径中的各段
+// 参数:
+//   - p: Windows路径
+//
+// 返回值:
+//   - string: 还原大小写后的路径，分隔符保持不变；查询超时时只改写盘符
+func (r *FSCaseResolver) ResolveCase(p string) string {
+	if p == "" || hasNamespacePrefix(p) {
+		return p
+	}
+	root, rest, absolute := splitRoot(p)
+	if !absolute || root == `\` || strings.HasPrefix(root, "%") {
+		return p
+	}
+	if len(root) >= 2 && root[1] == ':' {
+		root = string(upper(root[0])) + root[1:]
+	}
+	fsRoot := strings.TrimSuffix(root, `\`)
+	segments := strings.FieldsFunc(rest, func(r rune) bool { return r == '\\' || r == '/' })
+	// 缓存的键区分大小写：区分大小写的目录中，仅大小写不同的写法可能对应不同的目录项
+	names, _ := r.lookup.do(fsRoot+"/"+strings.Join(segments, "/"), func() []string {
+		return resolveNames(r.open(fsRoot), segments)
+	})
+
+	var out strings.Builder
+	out.WriteString(root)
+	n := 0
+	for i := 0; i < len(rest); {
+		// 保留原有的分隔符
+		j := i
+		for j < len(rest) && isSeparator(rest[j]) {
+			j++
+		}
+		out.WriteString(rest[i:j])
+		if j == len(rest) {
+			break
+		}
+		k := j
+		for k < len(rest) && !isSeparator(rest[k]) {
+			k++
+		}
+		if n < len(names) {
+			out.WriteString(names[n])
+		} else {
+			// 不存在的部分保持原样
+			out.WriteString(rest[j:k])
+		}
+		n++
+		i = k
+	}
+	return out.String()
+}
+
+// resolveNames 逐段查询路径各段的实际名称，直到某一段不存在
+// 返回值:
+//   - []string: 路径开头存在的各段的实际名称
+func resolveNames(fsys fs.FS, segments []string) []string {
+	var names []string
+	dir := "."
+	for _, seg := range segments {
+		name, ok := matchEntry(fsys, dir, seg)
+		if !ok {
+			break
+		}
+		names = append(names, name)
+		dir = path.Join(dir, name)
+	}
+	return names
+}
+
+// matchEntry 在目录中查找与名称仅大小写不同的目录项
+// 文件系统实现了EntryNameFS时只查询该目录项；否则列出目录，名称完全相同的目录项优先（区分大小写的目录中可能同时存在多个）
+// 返回值:
+//   - string: 目录项的实际名称
+//   - bool: 是否找到
+func matchEntry(fsys fs.FS, dir, name string) (string, bool) {
+	if fsys == nil || name == "." || name == ".." {
+		return "", false
+	}
+	if nfs, ok := fsys.(EntryNameFS); ok {
+		actual, err := nfs.EntryName(path.Join(dir, name))
+		return actual, err == nil && strings.EqualFold(actual, name)
+	}
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return "", false
+	}
+	found := ""
+	for _, e := range entries {
+		switch {
+		case e.Name() == name:
+			return name, true
+		case found == "" && strings.EqualFold(e.Name(), name):
+			found = e.Name()
+		}
+	}
+	return found, found != ""
+}
+
+// SetCaseOptions 设置盘符大小写与路径大小写的还原
+// 参数:
+//   - driveCase: 盘符的大小写
+//   - resolver: 按文件系统还原路径大小写，为nil时不还原
+func (pc *PathConverter) SetCaseOptions(driveCase DriveCase, resolver CaseResolver) {
+	pc.driveCase = driveCase
+	pc.caseResolver = resolver
+}
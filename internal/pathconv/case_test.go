@@ -0,0 +1,186 @@
+package pathconv
+
+import (
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+// fakeDrives 内存中的驱动器，测试大小写还原时代替真实的文件系统
+type fakeDrives map[string]fstest.MapFS
+
+// open 按根目录返回对应的文件系统，未知的根返回nil
+func (f fakeDrives) open(root string) fs.FS {
+	if fsys, ok := f[root]; ok {
+		return fsys
+	}
+	return nil
+}
+
+func newFakeResolver() *FSCaseResolver {
+	drives := fakeDrives{
+		"C:": fstest.MapFS{
+			"Users/me/repo/main.go":       {},
+			"Users/me/Repo.bak/x":         {},
+			"Program Files/Go/bin/go.exe": {},
+			"case/Readme.md":              {},
+			"case/README.md":              {},
+		},
+		`\\nas\proj`: fstest.MapFS{
+			"Builds/Nightly/app.zip": {},
+		},
+	}
+	return NewFSCaseResolver(drives.open, time.Second, time.Minute, clock.NewFake(time.Unix(0, 0)))
+}
+
+// entryNameFS 只支持按名称查询目录项的文件系统，列出目录时报告测试失败
+type entryNameFS struct {
+	t       *testing.T
+	names   map[string]string // 小写的路径到最后一段实际名称
+	lookups *atomic.Int32
+}
+
+// Open 文件系统实现了EntryNameFS时不应列出目录
+func (f entryNameFS) Open(name string) (fs.File, error) {
+	f.t.Errorf("Open(%q): directories should not be listed when EntryName is available", name)
+	return nil, fs.ErrNotExist
+}
+
+// EntryName 返回目录项的实际名称并计数
+func (f entryNameFS) EntryName(name string) (string, error) {
+	f.lookups.Add(1)
+	if actual, ok := f.names[strings.ToLower(name)]; ok {
+		return actual, nil
+	}
+	return "", fs.ErrNotExist
+}
+
+func TestFSCaseResolver(t *testing.T) {
+	r := newFakeResolver()
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`c:\users\ME\repo`, `C:\Users\me\repo`},
+		{`c:\USERS\me\REPO\MAIN.GO`, `C:\Users\me\repo\main.go`},
+		{`C:\program files\go\BIN\`, `C:\Program Files\Go\bin\`},
+		// 不存在的部分保持原样
+		{`c:\users\me\missing\File.txt`, `C:\Users\me\missing\File.txt`},
+		// 名称完全相同的目录项优先
+		{`C:\case\README.md`, `C:\case\README.md`},
+		{`C:\case\Readme.md`, `C:\case\Readme.md`},
+		{`\\nas\proj\builds\nightly`, `\\nas\proj\Builds\Nightly`},
+		// 未知的驱动器、相对路径和带前缀的路径不处理
+		{`d:\users\me`, `D:\users\me`},
+		{`users\me`, `users\me`},
+		{`\\?\c:\users`, `\\?\c:\users`},
+		{`%USERPROFILE%\repo`, `%USERPROFILE%\repo`},
+	}
+	for _, tt := range tests {
+		if got := r.ResolveCase(tt.input); got != tt.want {
+			t.Errorf("ResolveCase(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFSCaseResolver_EntryName(t *testing.T) {
+	var lookups atomic.Int32
+	fsys := entryNameFS{
+		t:       t,
+		names:   map[string]string{"users": "Users", "users/me": "me", "users/me/repo": "Repo"},
+		lookups: &lookups,
+	}
+	clk := clock.NewFake(time.Unix(0, 0))
+	r := NewFSCaseResolver(func(string) fs.FS { return fsys }, time.Second, time.Minute, clk)
+
+	if got := r.ResolveCase(`c:\USERS\me\repo\new.go`); got != `C:\Users\me\Repo\new.go` {
+		t.Errorf("ResolveCase = %q", got)
+	}
+	if got := lookups.Load(); got != 4 {
+		t.Errorf("each segment should be looked up once until one is missing, got %d lookups", got)
+	}
+	// 结果缓存一段时间，过期后重新查询
+	r.ResolveCase(`C:\USERS\me\repo\new.go`)
+	if got := lookups.Load(); got != 4 {
+		t.Errorf("cached resolutions should not query the file system, got %d lookups", got)
+	}
+	clk.Advance(time.Minute)
+	r.ResolveCase(`C:\USERS\me\repo\new.go`)
+	if got := lookups.Load(); got != 8 {
+		t.Errorf("expired resolutions should be looked up again, got %d lookups", got)
+	}
+}
+
+func TestFSCaseResolver_Timeout(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	slow := blockingFS{
+		FS:      fstest.MapFS{"Users/me/repo/main.go": {}},
+		started: make(chan struct{}, 8),
+		release: make(chan struct{}),
+	}
+	r := NewFSCaseResolver(func(string) fs.FS { return slow }, 200*time.Millisecond, time.Minute, clk)
+
+	done := make(chan string)
+	go func() { done <- r.ResolveCase(`c:\users\ME\repo`) }()
+	<-slow.started
+	clk.Advance(200 * time.Millisecond)
+	if got := <-done; got != `C:\users\ME\repo` {
+		t.Errorf("a resolution that times out should only change the drive letter, got %q", got)
+	}
+	close(slow.release)
+}
+
+func TestConvertTo_DriveCase(t *testing.T) {
+	pc := newTestConverter()
+	tests := []struct {
+		driveCase DriveCase
+		input     string
+		dialect   Dialect
+		want      string
+	}{
+		{DriveCaseKeep, `c:\Users\me`, DialectForward, `c:/Users/me`},
+		{DriveCaseUpper, `c:\Users\me`, DialectForward, `C:/Users/me`},
+		{DriveCaseLower, `C:\Users\me`, DialectForward, `c:/Users/me`},
+		{DriveCaseLower, `C:\Users\me`, DialectEscaped, `"c:\\Users\\me"`},
+		{DriveCaseUpper, `c:\Users\me`, DialectFileURI, `file:///C:/Users/me`},
+		// 挂载点总是小写
+		{DriveCaseUpper, `C:\Users\me`, DialectWSL, `/mnt/c/Users/me`},
+	}
+	for _, tt := range tests {
+		pc.SetCaseOptions(tt.driveCase, nil)
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) with drive case %s = %q, want %q", tt.input, tt.dialect, tt.driveCase, got, tt.want)
+		}
+	}
+}
+
+func TestConvertTo_ResolveCase(t *testing.T) {
+	pc := newTestConverter()
+	pc.SetCaseOptions(DriveCaseKeep, newFakeResolver())
+	if got := pc.ConvertTo(`c:\users\ME\repo`, DialectForward); got != `C:/Users/me/repo` {
+		t.Errorf("ConvertTo with case resolution = %q", got)
+	}
+	if got := pc.ConvertTo(`c:\users\ME\repo`, DialectWSL); got != `/mnt/c/Users/me/repo` {
+		t.Errorf("ConvertTo with case resolution = %q", got)
+	}
+	pc.SetCaseOptions(DriveCaseLower, newFakeResolver())
+	if got := pc.ConvertTo(`C:\USERS\me`, DialectForward); got != `c:/Users/me` {
+		t.Errorf("drive case should apply after case resolution, got %q", got)
+	}
+}
+
+func TestParseDriveCase(t *testing.T) {
+	for name, want := range map[string]DriveCase{"": DriveCaseKeep, "Lower": DriveCaseLower, " UPPER ": DriveCaseUpper} {
+		if got, err := ParseDriveCase(name); err != nil || got != want {
+			t.Errorf("ParseDriveCase(%q) = (%q, %v), want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseDriveCase("title"); err == nil {
+		t.Error("ParseDriveCase should reject unknown names")
+	}
+}
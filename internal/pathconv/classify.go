@@ -0,0 +1,204 @@
+package pathconv
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Class 含反斜杠文本的类别
+// 注册表键、账户名等同样使用反斜杠，但翻转斜杠后就无法粘贴到regedit、PowerShell或权限设置中
+type Class string
+
+const (
+	ClassFile     Class = "file"     // 文件系统路径，如 C:\dir、\\server\share、%TEMP%\x、src\main.go
+	ClassRegistry Class = "registry" // 注册表键，如 HKEY_LOCAL_MACHINE\SOFTWARE\Foo、HKCU\Console
+	ClassAccount  Class = "account"  // 账户名，如 CORP\jdoe、NT AUTHORITY\SYSTEM
+	ClassProvider Class = "provider" // PowerShell提供程序路径，如 HKLM:\Software、Cert:\CurrentUser\My
+	ClassUnknown  Class = "unknown"  // 无法识别，如多行文本或含有文件名中不允许的字符
+)
+
+// allClasses 所有类别，顺序即帮助信息中的显示顺序
+var allClasses = []Class{ClassFile, ClassRegistry, ClassAccount, ClassProvider, ClassUnknown}
+
+// registryRoots 注册表根键的全称与缩写
+var registryRoots = []string{
+	"HKEY_LOCAL_MACHINE", "HKEY_CURRENT_USER", "HKEY_CLASSES_ROOT", "HKEY_USERS",
+	"HKEY_CURRENT_CONFIG", "HKEY_PERFORMANCE_DATA",
+	"HKLM", "HKCU", "HKCR", "HKU", "HKCC",
+}
+
+// knownExtensions 常见的文件扩展名（小写，不含点）
+var knownExtensions = map[string]bool{
+	"txt": true, "log": true, "md": true, "csv": true, "json": true, "xml": true, "yaml": true, "yml": true,
+	"toml": true, "ini": true, "cfg": true, "conf": true, "go": true, "c": true, "h": true, "cpp": true,
+	"hpp": true, "cs": true, "java": true, "py": true, "js": true, "ts": true, "tsx": true, "jsx": true,
+	"rs": true, "rb": true, "php": true, "sh": true, "ps1": true, "psm1": true, "bat": true, "cmd": true,
+	"exe": true, "dll": true, "sys": true, "msi": true, "lib": true, "obj": true, "pdb": true, "zip": true,
+	"7z": true, "tar": true, "gz": true, "iso": true, "png": true, "jpg": true, "jpeg": true, "gif": true,
+	"svg": true, "ico": true, "pdf": true, "doc": true, "docx": true, "xls": true, "xlsx": true, "ppt": true,
+	"pptx": true, "html": true, "htm": true, "css": true, "sql": true, "db": true, "sln": true, "csproj": true,
+	"vcxproj": true, "props": true, "lnk": true, "reg": true, "mp3": true, "mp4": true, "wav": true,
+}
+
+// hasKnownExtension 报告文件名是否带有常见的扩展名
+func hasKnownExtension(name string) bool {
+	i := strings.LastIndexByte(name, '.')
+	return i >= 0 && knownExtensions[strings.ToLower(name[i+1:])]
+}
+
+// wellKnownDomains 系统内置账户使用的域名称，其中一些包含空格
+var wellKnownDomains = []string{
+	"NT AUTHORITY", "BUILTIN", "NT SERVICE", "NT VIRTUAL MACHINE", "IIS APPPOOL",
+	"WINDOW MANAGER", "FONT DRIVER HOST", "APPLICATION PACKAGE AUTHORITY",
+}
+
+var (
+	// providerDrivePattern PowerShell驱动器路径，驱动器名称至少两个字符，避免与盘符混淆
+	providerDrivePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]+:([\\/]|$)`)
+	// providerQualifiedPattern 提供程序限定路径，如 Registry::HKEY_USERS、Microsoft.PowerShell.Core\FileSystem::C:\
+	providerQualifiedPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9.]*\\)?[A-Za-z]+::`)
+	// netBIOSDomainPattern 按惯例以大写书写的NetBIOS域名称，最长15个字符
+	netBIOSDomainPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]{0,14}$`)
+	// accountUserPattern 账户名中不允许出现的字符之外的部分
+	accountUserPattern = regexp.MustCompile(`^[^\\/"\[\]:;|=,+*?<>]+$`)
+	// loginNamePattern 按惯例以小写书写的登录名，如 jdoe、john.doe
+	loginNamePattern = regexp.MustCompile(`^[a-z][a-z0-9._-]*$`)
+)
+
+// Classify 判断含反斜杠的文本属于哪一类
+// 参数:
+//   - text: 要判断的文本，两端的引号会被忽略
+//
+// 返回值:
+//   - Class: 文本的类别
+func Classify(text string) Class {
+	p := strings.TrimSpace(strings.Trim(text, `"`))
+	switch {
+	case p == "" || strings.ContainsAny(p, "\r\n"):
+		return ClassUnknown
+	case isAbsoluteFilePath(p):
+		return ClassFile
+	case isRegistryKey(p):
+		return ClassRegistry
+	case providerDrivePattern.MatchString(p) || providerQualifiedPattern.MatchString(p):
+		return ClassProvider
+	case isAccountName(p):
+		return ClassAccount
+	case strings.ContainsAny(p, `\/`) && !strings.ContainsAny(p, `<>|"*?`):
+		return ClassFile
+	}
+	return ClassUnknown
+}
+
+// isAbsoluteFilePath 报告文本是否为不会与其他类别混淆的文件路径
+// 包括盘符路径、UNC路径、带命名空间前缀的路径和以环境变量开头的路径
+func isAbsoluteFilePath(p string) bool {
+	if hasNamespacePrefix(p) {
+		return true
+	}
+	if len(p) >= 3 && p[1] == ':' && isASCIILetter(p[0]) && isSeparator(p[2]) {
+		return true
+	}
+	if _, _, ok := splitUNC(p); ok {
+		return true
+	}
+	loc := envVarPattern.FindStringIndex(p)
+	return loc != nil && loc[0] == 0 && loc[1] < len(p) && isSeparator(p[loc[1]])
+}
+
+// isRegistryKey 报告文本是否为注册表键
+func isRegistryKey(p string) bool {
+	if hasRegistryRoot(p) {
+		return true
+	}
+	// regedit地址栏复制的路径带有 Computer\ 或本地化的前缀，此时只接受根键全称
+	_, rest, ok := strings.Cut(p, `\`)
+	return ok && strings.HasPrefix(strings.ToUpper(rest), "HKEY_") && hasRegistryRoot(rest)
+}
+
+// hasRegistryRoot 报告文本是否以注册表根键开头
+func hasRegistryRoot(p string) bool {
+	for _, root := range registryRoots {
+		if len(p) >= len(root) && strings.EqualFold(p[:len(root)], root) &&
+			(len(p) == len(root) || p[len(root)] == '\\') {
+			return true
+		}
+	}
+	return false
+}
+
+// isAccountName 报告文本是否为 DOMAIN\user 形式的账户名
+// 只有一个反斜杠，域名称为系统内置名称，或为按惯例大写的NetBIOS名称且用户名为小写的登录名；
+// BUILD\Release、LICENSES\MIT、SRC\main.go 这样首字母大写或带扩展名的第二段更可能是相对路径
+func isAccountName(p string) bool {
+	domain, user, ok := strings.Cut(p, `\`)
+	if !ok || user == "" || !accountUserPattern.MatchString(user) {
+		return false
+	}
+	for _, known := range wellKnownDomains {
+		if strings.EqualFold(domain, known) {
+			return true
+		}
+	}
+	return netBIOSDomainPattern.MatchString(domain) && loginNamePattern.MatchString(user) && !hasKnownExtension(user)
+}
+
+// ParseClass 解析类别名称，不区分大小写
+// 参数:
+//   - name: 类别名称
+//
+// 返回值:
+//   - Class: 对应的类别
+//   - error: 名称无效时返回错误
+func ParseClass(name string) (Class, error) {
+	c := Class(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range allClasses {
+		if c == known {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("未知的文本类别: %s", name)
+}
+
+// ClassPolicy 某一类文本的转换策略
+type ClassPolicy string
+
+const (
+	ClassConvert ClassPolicy = "convert" // 按路径转换
+	ClassSkip    ClassPolicy = "skip"    // 保持原样
+)
+
+// defaultClassPolicies 各类别的默认策略
+// 只有文件路径默认转换，注册表键、账户名、提供程序路径和无法识别的文本都保持原样
+var defaultClassPolicies = map[Class]ClassPolicy{
+	ClassFile:     ClassConvert,
+	ClassRegistry: ClassSkip,
+	ClassAccount:  ClassSkip,
+	ClassProvider: ClassSkip,
+	ClassUnknown:  ClassSkip,
+}
+
+// ParseClassPolicies 解析各类别的转换策略
+// 参数:
+//   - spec: 类别名称到策略名称的映射，如 {"registry": "convert"}
+//
+// 返回值:
+//   - map[Class]ClassPolicy: 解析后的策略
+//   - error: 类别或策略无效时返回错误
+func ParseClassPolicies(spec map[string]string) (map[Class]ClassPolicy, error) {
+	policies := make(map[Class]ClassPolicy, len(spec))
+	for name, value := range spec {
+		c, err := ParseClass(name)
+		if err != nil {
+			return nil, err
+		}
+		switch p := ClassPolicy(strings.ToLower(strings.TrimSpace(value))); p {
+		case ClassConvert, ClassSkip:
+			policies[c] = p
+		default:
+			return nil, fmt.Errorf("未知的转换策略: %s", value)
+		}
+	}
+	return policies, nil
+}
@@ -0,0 +1,115 @@
+package pathconv
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Class
+	}{
+		{`C:\Users\me`, ClassFile},
+		{`"C:\Program Files\App"`, ClassFile},
+		{`\\server\share\dir`, ClassFile},
+		{`\\?\C:\very\long\path`, ClassFile},
+		{`%USERPROFILE%\.ssh`, ClassFile},
+		{`src\main.go`, ClassFile},
+		{`SRC\main.go`, ClassFile},
+		{`BUILD\Release`, ClassFile},
+		{`LICENSES\MIT`, ClassFile},
+		{`OUT\Debug\app.exe`, ClassFile},
+		{`..\lib\util`, ClassFile},
+		{`\Windows\System32`, ClassFile},
+
+		{`HKEY_LOCAL_MACHINE\SOFTWARE\Foo`, ClassRegistry},
+		{`hkey_current_user\Console`, ClassRegistry},
+		{`HKCU\Software\Microsoft`, ClassRegistry},
+		{`HKLM`, ClassRegistry},
+		{`Computer\HKEY_CLASSES_ROOT\.txt`, ClassRegistry},
+		{`计算机\HKEY_LOCAL_MACHINE\SYSTEM`, ClassRegistry},
+
+		{`HKLM:\Software`, ClassProvider},
+		{`Cert:\CurrentUser\My`, ClassProvider},
+		{`Env:\PATH`, ClassProvider},
+		{`Registry::HKEY_USERS\.DEFAULT`, ClassProvider},
+		{`Microsoft.PowerShell.Core\FileSystem::C:\dir`, ClassProvider},
+
+		{`DOMAIN\username`, ClassAccount},
+		{`CORP\john.doe`, ClassAccount},
+		{`NT AUTHORITY\SYSTEM`, ClassAccount},
+		{`nt authority\local service`, ClassAccount},
+		{`BUILTIN\Administrators`, ClassAccount},
+		{`IIS APPPOOL\DefaultAppPool`, ClassAccount},
+
+		{"C:\\a\nC:\\b", ClassUnknown},
+		{`a\b|c`, ClassUnknown},
+		{`src\*.go`, ClassUnknown},
+		{``, ClassUnknown},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.input); got != tt.want {
+			t.Errorf("Classify(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestShouldConvert_ClassPolicies(t *testing.T) {
+	pc := newTestConverter()
+	for _, text := range []string{
+		`HKEY_LOCAL_MACHINE\SOFTWARE\Foo`,
+		`HKLM:\Software`,
+		`DOMAIN\username`,
+		`Cert:\CurrentUser\My`,
+		`NT AUTHORITY\SYSTEM`,
+		// 无法识别的文本默认保持原样
+		`src\*.go`,
+		`a\b|c`,
+	} {
+		if pc.ShouldConvert(text) {
+			t.Errorf("ShouldConvert(%q) should be false by default", text)
+		}
+	}
+	if !pc.ShouldConvert(`C:\Users\me`) {
+		t.Error("ShouldConvert should accept file paths by default")
+	}
+
+	pc.SetClassPolicies(map[Class]ClassPolicy{ClassRegistry: ClassConvert, ClassFile: ClassSkip})
+	if !pc.ShouldConvert(`HKCU\Software`) {
+		t.Error("ShouldConvert should accept registry keys when configured")
+	}
+	if pc.ShouldConvert(`C:\Users\me`) {
+		t.Error("ShouldConvert should reject file paths when configured to skip them")
+	}
+	if pc.ShouldConvert(`DOMAIN\username`) {
+		t.Error("unconfigured classes should keep their default policy")
+	}
+
+	// 不拆分列表时多行文本属于unknown，默认保持原样
+	pc.SetClassPolicies(nil)
+	pc.SetListOptions(false, false)
+	if pc.ShouldConvert("C:\\a\nC:\\b") {
+		t.Error("ShouldConvert should skip unknown text by default")
+	}
+	pc.SetClassPolicies(map[Class]ClassPolicy{ClassUnknown: ClassConvert})
+	if !pc.ShouldConvert("C:\\a\nC:\\b") {
+		t.Error("ShouldConvert should accept unknown text when configured")
+	}
+}
+
+func TestParseClassPolicies(t *testing.T) {
+	got, err := ParseClassPolicies(map[string]string{"Registry": "CONVERT", "unknown": "skip"})
+	if err != nil {
+		t.Fatalf("ParseClassPolicies returned error: %v", err)
+	}
+	if got[ClassRegistry] != ClassConvert || got[ClassUnknown] != ClassSkip {
+		t.Errorf("ParseClassPolicies = %v", got)
+	}
+
+	for _, spec := range []map[string]string{
+		{"registry": "ignore"},
+		{"url": "skip"},
+	} {
+		if _, err := ParseClassPolicies(spec); err == nil {
+			t.Errorf("ParseClassPolicies(%v) should fail", spec)
+		}
+	}
+}
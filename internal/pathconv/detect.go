@@ -0,0 +1,200 @@
+package pathconv
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultThreshold ShouldConvert认为文本是路径所需的最低得分
+// 盘符路径和UNC路径的得分远高于该值，只有两段的相对路径（如 docs\readme）略低于该值
+const DefaultThreshold = 0.5
+
+// 各项特征的权重，按 testdata/detect/corpus.txt 中的标注样本调整
+const (
+	weightAnchored     = 0.6  // 以盘符、UNC、命名空间前缀或环境变量开头
+	weightRootRelative = 0.4  // 以单个反斜杠开头，相对于当前驱动器的根目录
+	weightRelative     = 0.3  // 相对路径
+	weightSegment      = 0.1  // 每多一段路径，最多计入 maxSegmentBonus
+	maxSegmentBonus    = 0.3  // 段数加分的上限
+	weightExtension    = 0.2  // 最后一段带有常见的文件扩展名
+	weightDotSegment   = 0.15 // 含有 . 或 .. 段
+	penaltyInvalid     = 0.4  // 段中含有文件名不允许的字符，或以空格、点结尾
+	penaltyReserved    = 0.3  // 段为 CON、NUL、COM1 等保留设备名
+	penaltyEscape      = 0.25 // 段形如 \n、\d{3} 等转义序列
+	penaltyBrace       = 0.15 // 段中含有 { 或 }，常见于LaTeX和正则表达式
+	penaltyNoAlnum     = 0.2  // 段中没有字母或数字，如 ¯\_(ツ)_/¯
+	penaltyDollar      = 0.1  // 含有 $，常见于LaTeX公式和正则表达式
+	weightProse        = 0.8  // 乘以文本中英文虚词所占的比例
+)
+
+// reservedNames Windows保留的设备名，不能用作文件名（带扩展名同样保留）
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// proseWords 常见的英文虚词，在文本中所占比例越高越像自然语言
+var proseWords = map[string]bool{
+	"a": true, "an": true, "the": true, "is": true, "are": true, "was": true, "be": true, "to": true,
+	"of": true, "and": true, "or": true, "in": true, "on": true, "at": true, "for": true, "with": true,
+	"by": true, "from": true, "as": true, "it": true, "this": true, "that": true, "use": true, "see": true,
+	"if": true, "you": true, "can": true, "not": true, "then": true, "into": true,
+}
+
+// escapeLetters 反斜杠后常见的转义字母，如 \n、\t 以及正则表达式中的 \d、\w
+const escapeLetters = "abfnrtvdswDSWB0"
+
+// Score 估计文本是Windows路径的可能性
+// 综合考虑开头的盘符或UNC前缀、段数、文件扩展名、非法字符、保留设备名、转义序列和自然语言的比例等特征；
+// 多行文本取各非空行得分的平均值
+// 参数:
+//   - text: 要评估的文本，两端的引号会被忽略
+//
+// 返回值:
+//   - float64: 0到1之间的得分，越高越像路径
+func Score(text string) float64 {
+	lines := strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\r' })
+	if len(lines) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, line := range lines {
+		total += scoreLine(line)
+	}
+	return total / float64(len(lines))
+}
+
+// scoreLine 计算单行文本的得分
+func scoreLine(line string) float64 {
+	p := strings.Trim(strings.TrimSpace(line), `"`)
+	if !strings.Contains(p, `\`) {
+		return 0
+	}
+
+	score, rest, anchored, relative := 0.0, p, false, false
+	switch root, after, absolute := splitRoot(p); {
+	case hasNamespacePrefix(p):
+		score, rest, anchored = weightAnchored, "", true
+	case absolute && root != `\`:
+		score, rest, anchored = weightAnchored, after, true
+	case absolute:
+		score, rest = weightRootRelative, after
+	default:
+		score, relative = weightRelative, true
+	}
+
+	segments := strings.FieldsFunc(rest, func(r rune) bool { return r == '\\' || r == '/' })
+	if len(segments) == 0 && !anchored {
+		return 0
+	}
+	if len(segments) > 1 {
+		score += min(weightSegment*float64(len(segments)-1), maxSegmentBonus)
+	}
+	if len(segments) > 0 && hasKnownExtension(segments[len(segments)-1]) {
+		score += weightExtension
+	}
+
+	dotSegment := false
+	for i, seg := range segments {
+		// 相对路径的第一段前面没有反斜杠，不可能是转义序列；盘符等开头的路径中单个字母的目录很常见，不按转义序列处理
+		afterBackslash := !anchored && (i > 0 || !relative)
+		score -= segmentPenalty(seg, afterBackslash)
+		dotSegment = dotSegment || seg == "." || seg == ".."
+	}
+	if dotSegment {
+		score += weightDotSegment
+	}
+	if strings.Contains(p, "$") {
+		score -= penaltyDollar
+	}
+	score -= weightProse * proseRatio(p)
+	return max(0, min(1, score))
+}
+
+// segmentPenalty 计算一段路径因不像文件名而扣除的分数
+// 参数:
+//   - seg: 路径段
+//   - afterBackslash: 该段是否紧跟在反斜杠之后，只有这样的段才可能是转义序列
+//
+// 返回值:
+//   - float64: 扣除的分数
+func segmentPenalty(seg string, afterBackslash bool) float64 {
+	if seg == "." || seg == ".." {
+		return 0
+	}
+	penalty := 0.0
+	if strings.ContainsAny(seg, `<>|"*?`) || strings.HasSuffix(seg, " ") || strings.HasSuffix(seg, ".") {
+		penalty += penaltyInvalid
+	}
+	name := seg
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	if reservedNames[strings.ToUpper(strings.TrimSpace(name))] {
+		penalty += penaltyReserved
+	}
+	if afterBackslash && isEscapeSequence(seg) {
+		penalty += penaltyEscape
+	}
+	if strings.ContainsAny(seg, "{}") {
+		penalty += penaltyBrace
+	}
+	if strings.IndexFunc(seg, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+		penalty += penaltyNoAlnum
+	}
+	return penalty
+}
+
+// isEscapeSequence 报告路径段是否以转义序列开头
+// 如 n、d{3}、w+ 以及十六进制的 x41、u00e9；后跟字母或数字的普通名称（如 tsc、bin）不算
+func isEscapeSequence(seg string) bool {
+	switch {
+	case strings.IndexByte(escapeLetters, seg[0]) >= 0:
+		return len(seg) == 1 || !isFileNameChar(rune(seg[1]))
+	case seg[0] == 'x':
+		return len(seg) >= 3 && isHex(seg[1:3]) && (len(seg) == 3 || !isFileNameChar(rune(seg[3])))
+	case seg[0] == 'u':
+		return len(seg) >= 5 && isHex(seg[1:5]) && (len(seg) == 5 || !isFileNameChar(rune(seg[5])))
+	}
+	return false
+}
+
+// isHex 报告字符串是否全部由十六进制数字组成
+func isHex(s string) bool {
+	return strings.Trim(s, "0123456789abcdefABCDEF") == ""
+}
+
+// isFileNameChar 报告字符是否常见于文件名中：字母、数字以及 -_.
+func isFileNameChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.'
+}
+
+// proseRatio 返回文本中英文虚词所占的比例，单词少于两个时为0
+func proseRatio(p string) float64 {
+	words := strings.Fields(p)
+	if len(words) < 2 {
+		return 0
+	}
+	n := 0
+	for _, w := range words {
+		if proseWords[strings.ToLower(strings.Trim(w, `.,;:!?()"'`))] {
+			n++
+		}
+	}
+	return float64(n) / float64(len(words))
+}
+
+// ValidateThreshold 检查路径识别阈值是否在0到1之间
+// 参数:
+//   - threshold: 阈值
+//
+// 返回值:
+//   - error: 阈值无效时返回错误
+func ValidateThreshold(threshold float64) error {
+	if threshold < 0 || threshold > 1 {
+		return fmt.Errorf("路径识别阈值必须在0到1之间: %v", threshold)
+	}
+	return nil
+}
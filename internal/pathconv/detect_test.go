@@ -0,0 +1,100 @@
+package pathconv
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadCorpus 读取 testdata/detect/corpus.txt 中的标注样本
+func loadCorpus(t *testing.T) map[string]bool {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "detect", "corpus.txt"))
+	if err != nil {
+		t.Fatalf("open corpus: %v", err)
+	}
+	defer f.Close()
+
+	samples := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		label, sample, ok := strings.Cut(text, "\t")
+		if !ok || (label != "0" && label != "1") {
+			t.Fatalf("corpus line %d: malformed entry %q", line, text)
+		}
+		samples[sample] = label == "1"
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read corpus: %v", err)
+	}
+	return samples
+}
+
+func TestScore_Corpus(t *testing.T) {
+	for sample, isPath := range loadCorpus(t) {
+		score := Score(sample)
+		if (score >= DefaultThreshold) != isPath {
+			t.Errorf("Score(%q) = %.2f, labelled path=%v", sample, score, isPath)
+		}
+	}
+}
+
+func TestScore_Range(t *testing.T) {
+	for sample := range loadCorpus(t) {
+		if s := Score(sample); s < 0 || s > 1 {
+			t.Errorf("Score(%q) = %v, out of [0, 1]", sample, s)
+		}
+	}
+	if s := Score("no separators here"); s != 0 {
+		t.Errorf("Score of text without backslashes = %v, want 0", s)
+	}
+}
+
+func TestScore_MultiLine(t *testing.T) {
+	paths := "C:\\Users\\me\\a.txt\nC:\\Users\\me\\b.txt\n"
+	if s := Score(paths); s < DefaultThreshold {
+		t.Errorf("a list of paths should score above the threshold, got %.2f", s)
+	}
+	prose := "Use \\n to break lines\nand \\t to indent them"
+	if s := Score(prose); s >= DefaultThreshold {
+		t.Errorf("prose with escapes should score below the threshold, got %.2f", s)
+	}
+}
+
+func TestShouldConvert_Threshold(t *testing.T) {
+	pc := newTestConverter()
+	if pc.ShouldConvert(`Use \n to break lines`) {
+		t.Error("ShouldConvert should reject prose by default")
+	}
+	if pc.ShouldConvert(`docs\readme`) {
+		t.Error("a two-segment relative path scores below the default threshold")
+	}
+
+	pc.SetThreshold(0.4)
+	if !pc.ShouldConvert(`docs\readme`) {
+		t.Error("ShouldConvert should accept docs\\readme with a lower threshold")
+	}
+	pc.SetThreshold(1)
+	if !pc.ShouldConvert(`C:\Users\me\Documents\report.docx`) {
+		t.Error("a long drive path with a known extension should reach the maximum score")
+	}
+}
+
+func TestValidateThreshold(t *testing.T) {
+	for _, v := range []float64{0, 0.5, 1} {
+		if err := ValidateThreshold(v); err != nil {
+			t.Errorf("ValidateThreshold(%v) returned error: %v", v, err)
+		}
+	}
+	for _, v := range []float64{-0.1, 1.5} {
+		if err := ValidateThreshold(v); err == nil {
+			t.Errorf("ValidateThreshold(%v) should fail", v)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package pathconv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect 路径输出格式
+// 不同的粘贴目标需要不同形式的路径，例如编辑器接受 C:/...，WSL终端需要 /mnt/c/...
+type Dialect string
+
+const (
+	DialectForward  Dialect = "forward"  // 正斜杠格式，如 C:/Users/me
+	DialectWSL      Dialect = "wsl"      // WSL挂载格式，如 /mnt/c/Users/me
+	DialectMSYS     Dialect = "msys"     // Git Bash/MSYS2格式，如 /c/Users/me
+	DialectEscaped  Dialect = "escaped"  // 带引号且反斜杠转义的格式，如 "C:\\Users\\me"
+	DialectOriginal Dialect = "original" // 保持原始内容不变
+	DialectSMB      Dialect = "smb"      // smb URI，仅适用于UNC路径，如 smb://server/share/dir
+	DialectFileURI  Dialect = "file-uri" // RFC 8089 file URI，如 file:///C:/Users/me、file://server/share
+
+	// 编程语言的字符串字面量，粘贴到源代码或配置文件中
+	DialectJSON          Dialect = "json"           // JSON字符串，如 "C:\\Users\\me"
+	DialectC             Dialect = "c"              // C/C++字符串，如 "C:\\Users\\me"
+	DialectGo            Dialect = "go"             // Go字符串，如 "C:\\Users\\me"
+	DialectPython        Dialect = "python"         // Python原始字符串，如 r"C:\Users\me"
+	DialectCSharp        Dialect = "csharp"         // C#逐字字符串，如 @"C:\Users\me"
+	DialectStringForward Dialect = "string-forward" // 正斜杠的字符串，以上语言通用，如 "C:/Users/me"
+)
+
+// allDialects 所有支持的输出格式，顺序即帮助信息中的显示顺序
+var allDialects = []Dialect{
+	DialectForward, DialectWSL, DialectMSYS, DialectEscaped, DialectOriginal, DialectSMB, DialectFileURI,
+	DialectJSON, DialectC, DialectGo, DialectPython, DialectCSharp, DialectStringForward,
+}
+
+// Dialects 返回所有支持的输出格式
+func Dialects() []Dialect {
+	return append([]Dialect(nil), allDialects...)
+}
+
+// ParseDialect 解析输出格式名称，不区分大小写
+// 参数:
+//   - name: 格式名称
+//
+// 返回值:
+//   - Dialect: 对应的输出格式
+//   - error: 名称无效时返回错误
+func ParseDialect(name string) (Dialect, error) {
+	d := Dialect(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range allDialects {
+		if d == known {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("未知的输出格式: %s", name)
+}
+
+// quotesOwnOutput 报告该格式是否自带引号
+// 自带引号的格式不再保留原文本两端的引号，避免出现双重引号
+func (d Dialect) quotesOwnOutput() bool {
+	return d == DialectEscaped || d.isLiteral()
+}
+
+// formatPath 将不含外层引号的Windows路径格式化为指定输出格式
+// 参数:
+//   - content: 路径内容
+//   - d: 输出格式
+//
+// 返回值:
+//   - string: 格式化后的路径
+func formatPath(content string, d Dialect) string {
+	if d.isLiteral() {
+		return formatLiteral(content, d)
+	}
+	switch d {
+	case DialectOriginal:
+		return content
+	case DialectEscaped:
+		return `"` + strings.ReplaceAll(content, `\`, `\\`) + `"`
+	case DialectWSL:
+		if drive, rest, ok := splitDrive(content); ok {
+			return "/mnt/" + drive + toSlash(rest)
+		}
+	case DialectMSYS:
+		if drive, rest, ok := splitDrive(content); ok {
+			return "/" + drive + toSlash(rest)
+		}
+	case DialectSMB:
+		if uri, ok := toSMBURI(content); ok {
+			return uri
+		}
+	case DialectFileURI:
+		if uri, ok := toFileURI(content); ok {
+			return uri
+		}
+	}
+	// 正斜杠格式，同时作为没有盘符的路径在其他格式下的回退
+	return toSlash(content)
+}
+
+// splitDrive 拆分以盘符开头的绝对路径
+// 例如 C:\Users 拆分为 "c" 和 "\Users"；仅有盘符的 C: 拆分为 "c" 和 ""
+// 参数:
+//   - p: 路径
+//
+// 返回值:
+//   - string: 小写的盘符字母
+//   - string: 盘符之后的部分
+//   - bool: 路径是否以盘符开头
+func splitDrive(p string) (string, string, bool) {
+	if len(p) < 2 || p[1] != ':' || !isASCIILetter(p[0]) {
+		return "", "", false
+	}
+	rest := p[2:]
+	if rest != "" && rest[0] != '\\' && rest[0] != '/' {
+		// C:foo 是相对于驱动器当前目录的路径，无法映射到挂载点
+		return "", "", false
+	}
+	return strings.ToLower(p[:1]), rest, true
+}
+
+// isASCIILetter 报告字节是否为ASCII字母
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// toSlash 将反斜杠替换为正斜杠
+func toSlash(p string) string {
+	return strings.ReplaceAll(p, `\`, "/")
+}
+
+// wslToWindows 将WSL挂载路径转换为Windows路径，如 /mnt/c/Users → C:\Users
+// 参数:
+//   - p: WSL路径
+//
+// 返回值:
+//   - string: Windows路径
+//   - bool: 路径是否为WSL挂载路径
+func wslToWindows(p string) (string, bool) {
+	const mnt = "/mnt/"
+	if !strings.HasPrefix(p, mnt) || len(p) < len(mnt)+1 || !isASCIILetter(p[len(mnt)]) {
+		return "", false
+	}
+	rest := p[len(mnt)+1:]
+	if rest != "" && rest[0] != '/' {
+		return "", false
+	}
+	if rest == "" {
+		rest = "/"
+	}
+	return string(upper(p[len(mnt)])) + ":" + strings.ReplaceAll(rest, "/", `\`), true
+}
@@ -0,0 +1,49 @@
+package pathconv
+
+import (
+	"io/fs"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// windowsDirFS 操作系统的文件系统，按名称直接查询单个目录项的实际名称
+type windowsDirFS struct {
+	fs.FS        // 以根目录打开的os.DirFS
+	root  string // 根目录，如 C: 或 \\server\share
+}
+
+// DirFS 以操作系统的文件系统打开根目录，供NewFSExistenceChecker和NewFSCaseResolver使用
+// 参数:
+//   - root: 根目录，如 C: 或 \\server\share
+//
+// 返回值:
+//   - fs.FS: 实现了fs.StatFS和EntryNameFS的文件系统
+func DirFS(root string) fs.FS {
+	return windowsDirFS{FS: os.DirFS(root + `\`), root: root}
+}
+
+// Stat 查询文件信息
+func (f windowsDirFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(f.FS, name)
+}
+
+// EntryName 通过FindFirstFile查询目录项的实际名称，无需列出整个目录
+func (f windowsDirFS) EntryName(name string) (string, error) {
+	if !fs.ValidPath(name) || name == "." || strings.ContainsAny(name, `*?`) {
+		// 通配符会匹配其他目录项
+		return "", &fs.PathError{Op: "entryname", Path: name, Err: fs.ErrInvalid}
+	}
+	p, err := windows.UTF16PtrFromString(f.root + `\` + strings.ReplaceAll(name, "/", `\`))
+	if err != nil {
+		return "", &fs.PathError{Op: "entryname", Path: name, Err: err}
+	}
+	var data windows.Win32finddata
+	h, err := windows.FindFirstFile(p, &data)
+	if err != nil {
+		return "", &fs.PathError{Op: "entryname", Path: name, Err: err}
+	}
+	windows.FindClose(h)
+	return windows.UTF16ToString(data.FileName[:]), nil
+}
@@ -0,0 +1,185 @@
+package pathconv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvMode %VAR% 形式环境变量的处理方式
+type EnvMode string
+
+const (
+	EnvKeep        EnvMode = "keep"         // 保持 %VAR% 不变
+	EnvExpand      EnvMode = "expand"       // 展开为变量的值，未定义的变量保持不变
+	EnvPOSIX       EnvMode = "posix"        // 转换为 $VAR
+	EnvPOSIXBraced EnvMode = "posix-braced" // 转换为 ${VAR}
+)
+
+// ContractMode 将路径前缀收缩为环境变量的方式
+type ContractMode string
+
+const (
+	ContractNone  ContractMode = "none"  // 不收缩
+	ContractEnv   ContractMode = "env"   // 收缩为 %VAR%，如 C:\Users\me\.ssh → %USERPROFILE%\.ssh
+	ContractTilde ContractMode = "tilde" // 将用户目录收缩为 ~，如 C:\Users\me\.ssh → ~\.ssh
+)
+
+// contractVars ContractEnv模式下参与收缩的环境变量
+// 多个变量都能匹配时使用值最长的一个，例如 %APPDATA% 优先于 %USERPROFILE%
+var contractVars = []string{
+	"USERPROFILE", "APPDATA", "LOCALAPPDATA", "TEMP",
+	"ProgramFiles", "ProgramFiles(x86)", "ProgramData", "SystemRoot",
+}
+
+// Env 查询环境变量，变量不存在时返回false
+type Env func(name string) (string, bool)
+
+// MapEnv 基于固定映射表的环境变量查询，变量名不区分大小写（与Windows一致）
+// 参数:
+//   - vars: 变量名到值的映射
+//
+// 返回值:
+//   - Env: 查询函数
+func MapEnv(vars map[string]string) Env {
+	upperVars := make(map[string]string, len(vars))
+	for k, v := range vars {
+		upperVars[strings.ToUpper(k)] = v
+	}
+	return func(name string) (string, bool) {
+		v, ok := upperVars[strings.ToUpper(name)]
+		return v, ok
+	}
+}
+
+// ParseEnvMode 解析环境变量处理方式，不区分大小写，空字符串视为keep
+// 参数:
+//   - name: 处理方式名称
+//
+// 返回值:
+//   - EnvMode: 对应的处理方式
+//   - error: 名称无效时返回错误
+func ParseEnvMode(name string) (EnvMode, error) {
+	switch m := EnvMode(strings.ToLower(strings.TrimSpace(name))); m {
+	case "":
+		return EnvKeep, nil
+	case EnvKeep, EnvExpand, EnvPOSIX, EnvPOSIXBraced:
+		return m, nil
+	}
+	return "", fmt.Errorf("未知的环境变量处理方式: %s", name)
+}
+
+// ParseContractMode 解析路径收缩方式，不区分大小写，空字符串视为none
+// 参数:
+//   - name: 收缩方式名称
+//
+// 返回值:
+//   - ContractMode: 对应的收缩方式
+//   - error: 名称无效时返回错误
+func ParseContractMode(name string) (ContractMode, error) {
+	switch m := ContractMode(strings.ToLower(strings.TrimSpace(name))); m {
+	case "":
+		return ContractNone, nil
+	case ContractNone, ContractEnv, ContractTilde:
+		return m, nil
+	}
+	return "", fmt.Errorf("未知的路径收缩方式: %s", name)
+}
+
+// expandEnv 将 %VAR% 替换为变量的值，未定义的变量保持不变
+func expandEnv(p string, env Env) string {
+	return envVarPattern.ReplaceAllStringFunc(p, func(ref string) string {
+		if v, ok := env(ref[1 : len(ref)-1]); ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// translateEnv 将 %VAR% 转换为POSIX shell语法
+// 变量名不是合法的shell标识符（如 ProgramFiles(x86)）时保持不变；
+// posix模式下变量后紧跟标识符字符时自动加花括号，避免与后面的文字连在一起
+func translateEnv(p string, mode EnvMode) string {
+	if mode != EnvPOSIX && mode != EnvPOSIXBraced {
+		return p
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range envVarPattern.FindAllStringIndex(p, -1) {
+		name := p[loc[0]+1 : loc[1]-1]
+		if !isShellIdentifier(name) {
+			continue
+		}
+		b.WriteString(p[last:loc[0]])
+		followed := loc[1] < len(p) && isIdentifierChar(p[loc[1]])
+		if mode == EnvPOSIXBraced || followed {
+			b.WriteString("${" + name + "}")
+		} else {
+			b.WriteString("$" + name)
+		}
+		last = loc[1]
+	}
+	b.WriteString(p[last:])
+	return b.String()
+}
+
+// contractPath 将路径开头与环境变量值相同的部分替换为变量引用或 ~
+// 前缀比较不区分大小写，且只在路径分隔符处匹配
+func contractPath(p string, mode ContractMode, env Env) string {
+	switch mode {
+	case ContractTilde:
+		if home, ok := env("USERPROFILE"); ok {
+			if n := matchEnvValue(p, home); n > 0 {
+				return "~" + p[n:]
+			}
+		}
+	case ContractEnv:
+		bestName, bestLen := "", 0
+		for _, name := range contractVars {
+			v, ok := env(name)
+			if !ok {
+				continue
+			}
+			if n := matchEnvValue(p, v); n > bestLen {
+				bestName, bestLen = name, n
+			}
+		}
+		if bestLen > 0 {
+			return "%" + bestName + "%" + p[bestLen:]
+		}
+	}
+	return p
+}
+
+// matchEnvValue 返回路径p中与变量值匹配的前缀长度，不匹配时返回0
+func matchEnvValue(p, value string) int {
+	value = strings.TrimRight(value, `\/`)
+	if len(value) < 2 || !hasPathPrefix(p, value) {
+		return 0
+	}
+	return len(value)
+}
+
+// isShellIdentifier 报告名称是否为合法的POSIX shell变量名
+func isShellIdentifier(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isIdentifierChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentifierChar 报告字节是否可以出现在shell变量名中
+func isIdentifierChar(c byte) bool {
+	return isASCIILetter(c) || (c >= '0' && c <= '9') || c == '_'
+}
+
+// osEnv 查询当前进程的环境变量
+func osEnv(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
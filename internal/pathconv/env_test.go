@@ -0,0 +1,102 @@
+package pathconv
+
+import "testing"
+
+// testEnv 测试使用的环境变量
+var testEnv = MapEnv(map[string]string{
+	"USERPROFILE":       `C:\Users\me`,
+	"APPDATA":           `C:\Users\me\AppData\Roaming`,
+	"ProgramFiles":      `C:\Program Files`,
+	"ProgramFiles(x86)": `C:\Program Files (x86)`,
+	"TEMP":              `C:\Users\me\AppData\Local\Temp\`,
+})
+
+func TestConvertTo_EnvModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    EnvMode
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{"keep", EnvKeep, `%USERPROFILE%\.ssh`, DialectForward, `%USERPROFILE%/.ssh`},
+		{"expand", EnvExpand, `%USERPROFILE%\.ssh`, DialectForward, `C:/Users/me/.ssh`},
+		{"expand case-insensitive", EnvExpand, `%userprofile%\.ssh`, DialectWSL, `/mnt/c/Users/me/.ssh`},
+		{"expand unknown var", EnvExpand, `%NOPE%\x`, DialectForward, `%NOPE%/x`},
+		{"posix", EnvPOSIX, `%USERPROFILE%\.ssh`, DialectForward, `$USERPROFILE/.ssh`},
+		{"posix needs braces", EnvPOSIX, `%APPDATA%_old\x`, DialectForward, `${APPDATA}_old/x`},
+		{"posix braced", EnvPOSIXBraced, `%APPDATA%\x`, DialectWSL, `${APPDATA}/x`},
+		{"posix invalid name", EnvPOSIX, `%ProgramFiles(x86)%\x`, DialectForward, `%ProgramFiles(x86)%/x`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := newTestConverter()
+			pc.SetEnvOptions(tt.mode, ContractNone, testEnv)
+			if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+				t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertTo_Contraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     EnvMode
+		contract ContractMode
+		input    string
+		want     string
+	}{
+		{"env", EnvKeep, ContractEnv, `C:\Users\me\.ssh\config`, `%USERPROFILE%/.ssh/config`},
+		{"env prefers longest", EnvKeep, ContractEnv, `C:\Users\me\AppData\Roaming\Code`, `%APPDATA%/Code`},
+		{"env trailing separator in value", EnvKeep, ContractEnv, `C:\Users\me\AppData\Local\Temp\x.log`, `%TEMP%/x.log`},
+		{"env case-insensitive", EnvKeep, ContractEnv, `c:\users\ME\x`, `%USERPROFILE%/x`},
+		{"env segment boundary", EnvKeep, ContractEnv, `C:\Users\meg\x`, `C:/Users/meg/x`},
+		{"env exact", EnvKeep, ContractEnv, `C:\Program Files`, `%ProgramFiles%`},
+		{"env then posix", EnvPOSIX, ContractEnv, `C:\Users\me\.ssh`, `$USERPROFILE/.ssh`},
+		{"tilde", EnvKeep, ContractTilde, `C:\Users\me\.ssh`, `~/.ssh`},
+		{"tilde outside home", EnvKeep, ContractTilde, `D:\x`, `D:/x`},
+		{"none", EnvKeep, ContractNone, `C:\Users\me\.ssh`, `C:/Users/me/.ssh`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := newTestConverter()
+			pc.SetEnvOptions(tt.mode, tt.contract, testEnv)
+			if got := pc.Convert(tt.input); got != tt.want {
+				t.Errorf("Convert(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertTo_MappingTakesPrecedenceOverContraction(t *testing.T) {
+	m, err := NewMapper([]Mapping{{Windows: `C:\Users\me\work`, Target: "/home/me/work"}})
+	if err != nil {
+		t.Fatalf("NewMapper failed: %v", err)
+	}
+	pc := newTestConverter()
+	pc.SetMappings(m)
+	pc.SetEnvOptions(EnvKeep, ContractTilde, testEnv)
+
+	if got := pc.Convert(`C:\Users\me\work\a`); got != "/home/me/work/a" {
+		t.Errorf("mapped path = %q", got)
+	}
+	if got := pc.Convert(`C:\Users\me\notes`); got != "~/notes" {
+		t.Errorf("contracted path = %q", got)
+	}
+}
+
+func TestParseEnvAndContractModes(t *testing.T) {
+	if m, err := ParseEnvMode("POSIX-Braced"); err != nil || m != EnvPOSIXBraced {
+		t.Errorf("ParseEnvMode = %q, %v", m, err)
+	}
+	if _, err := ParseEnvMode("bash"); err == nil {
+		t.Error("expected error for unknown env mode")
+	}
+	if m, err := ParseContractMode(""); err != nil || m != ContractNone {
+		t.Errorf("ParseContractMode = %q, %v", m, err)
+	}
+	if _, err := ParseContractMode("home"); err == nil {
+		t.Error("expected error for unknown contract mode")
+	}
+}
@@ -0,0 +1,163 @@
+package pathconv
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+// ExistMode 转换前检查路径是否存在的方式
+type ExistMode string
+
+const (
+	ExistOff    ExistMode = "off"    // 不检查
+	ExistPath   ExistMode = "path"   // 路径本身必须存在
+	ExistParent ExistMode = "parent" // 路径或其所在目录存在即可，适用于尚未创建的文件
+)
+
+// ParseExistMode 解析存在性检查方式，不区分大小写，空字符串视为off
+// 参数:
+//   - name: 检查方式名称
+//
+// 返回值:
+//   - ExistMode: 对应的检查方式
+//   - error: 名称无效时返回错误
+func ParseExistMode(name string) (ExistMode, error) {
+	switch m := ExistMode(strings.ToLower(strings.TrimSpace(name))); m {
+	case "":
+		return ExistOff, nil
+	case ExistOff, ExistPath, ExistParent:
+		return m, nil
+	}
+	return "", fmt.Errorf("未知的存在性检查方式: %s", name)
+}
+
+// ExistenceChecker 查询路径是否存在
+type ExistenceChecker interface {
+	// Exists 报告盘符或UNC开头的绝对路径是否存在
+	// 无法在限定时间内得到结果时应返回true，避免因查询缓慢而漏掉真实的路径
+	Exists(p string) bool
+}
+
+// FSExistenceChecker 通过文件系统查询路径是否存在
+// 查询带超时，结果按LRU缓存一段时间（见timedLookup）
+type FSExistenceChecker struct {
+	open   func(root string) fs.FS // 打开驱动器或共享的根目录，如 C:\ 或 \\server\share
+	lookup *timedLookup[bool]      // 带超时和缓存的查询
+}
+
+// NewFSExistenceChecker 创建基于文件系统的存在性检查
+// 参数:
+//   - open: 按根目录打开文件系统，测试时可以使用内存中的文件系统
+//   - timeout: 单次查询的超时时间，不大于0时不限制
+//   - ttl: 查询结果的缓存时间，不大于0时不缓存
+//   - c: 时间源
+//
+// 返回值:
+//   - *FSExistenceChecker: 存在性检查实例
+func NewFSExistenceChecker(open func(root string) fs.FS, timeout, ttl time.Duration, c clock.Clock) *FSExistenceChecker {
+	return &FSExistenceChecker{open: open, lookup: newTimedLookup[bool](timeout, ttl, c)}
+}
+
+// Exists 查询路径是否存在
+// 参数:
+//   - p: 盘符或UNC开头的绝对路径，其他路径总是返回false
+//
+// 返回值:
+//   - bool: 路径存在或查询超时时返回true
+func (c *FSExistenceChecker) Exists(p string) bool {
+	root, rel, ok := splitFSPath(p)
+	if !ok {
+		return false
+	}
+	exists, ok := c.lookup.do(strings.ToLower(root+"/"+rel), func() bool {
+		fsys := c.open(root)
+		if fsys == nil {
+			return false
+		}
+		_, err := fs.Stat(fsys, rel)
+		return err == nil
+	})
+	return exists || !ok
+}
+
+// splitFSPath 将绝对路径拆分为根目录和fs.FS使用的相对路径
+// 返回值:
+//   - string: 根目录，如 C: 或 \\server\share，盘符为大写
+//   - string: 以 / 分隔的相对路径，根目录本身为 .
+//   - bool: 是否为盘符或UNC开头的绝对路径
+func splitFSPath(p string) (string, string, bool) {
+	if p == "" || hasNamespacePrefix(p) {
+		return "", "", false
+	}
+	root, rest, _ := splitRoot(Normalize(p, TrailingStrip))
+	switch {
+	case len(root) == 3 && root[1] == ':':
+		// 盘符的根目录 C:\
+		root = string(upper(root[0])) + ":"
+	case strings.HasPrefix(root, `\\`) && strings.Contains(root[2:], `\`):
+		// 完整的UNC根 \\server\share，只有服务器名的无法查询
+	default:
+		return "", "", false
+	}
+	rel := strings.Trim(toSlash(rest), "/")
+	if rel == "" {
+		rel = "."
+	}
+	if !fs.ValidPath(rel) {
+		return "", "", false
+	}
+	return root, rel, true
+}
+
+// parentPath 返回路径所在的目录，根目录的上级是其本身
+func parentPath(p string) string {
+	root, rest, _ := splitRoot(strings.TrimRight(p, `\/`))
+	if i := strings.LastIndexAny(rest, `\/`); i >= 0 {
+		return root + rest[:i]
+	}
+	return root
+}
+
+// SetExistenceCheck 设置转换前的存在性检查
+// 参数:
+//   - mode: 检查方式
+//   - checker: 存在性查询，为nil时不检查
+//   - allowedRoots: 允许的根目录，其下的路径无需查询文件系统即可转换
+func (pc *PathConverter) SetExistenceCheck(mode ExistMode, checker ExistenceChecker, allowedRoots []string) {
+	if checker == nil {
+		mode = ExistOff
+	}
+	pc.existMode = mode
+	pc.existChecker = checker
+	pc.allowedRoots = nil
+	for _, root := range allowedRoots {
+		if root = strings.TrimRight(strings.TrimSpace(root), `\/`); root != "" {
+			pc.allowedRoots = append(pc.allowedRoots, root)
+		}
+	}
+}
+
+// pathExists 按检查方式判断路径是否存在或位于允许的根目录下
+// 环境变量先按当前环境展开，相对路径按基准目录解析；仍不是绝对路径的无法检查，视为不存在
+func (pc *PathConverter) pathExists(p string) bool {
+	if pc.existMode == ExistOff {
+		return true
+	}
+	if strings.Contains(p, "%") {
+		p = expandEnv(p, pc.env)
+	}
+	p = pc.resolveRelative(p)
+	for _, root := range pc.allowedRoots {
+		if hasPathPrefix(p, root) {
+			return true
+		}
+	}
+	if pc.existChecker.Exists(p) {
+		return true
+	}
+	return pc.existMode == ExistParent && pc.existChecker.Exists(parentPath(p))
+}
@@ -0,0 +1,199 @@
+package pathconv
+
+import (
+	"io/fs"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+// countingFS 记录打开次数的文件系统，用于确认查询是否命中缓存
+type countingFS struct {
+	fs.FS
+	opens *atomic.Int32
+}
+
+// Open 打开文件并计数
+func (f countingFS) Open(name string) (fs.File, error) {
+	f.opens.Add(1)
+	return f.FS.Open(name)
+}
+
+// blockingFS 在release关闭前阻塞所有打开操作的文件系统，模拟无响应的网络共享
+type blockingFS struct {
+	fs.FS
+	started chan struct{}
+	release chan struct{}
+}
+
+// Open 通知查询已开始，等待release后打开文件
+func (f blockingFS) Open(name string) (fs.File, error) {
+	f.started <- struct{}{}
+	<-f.release
+	return f.FS.Open(name)
+}
+
+func newTestExistChecker(clk clock.Clock, opens *atomic.Int32) *FSExistenceChecker {
+	drives := fakeDrives{
+		"C:": fstest.MapFS{
+			"Users/me/repo/main.go": {},
+			"Windows/System32":      {Mode: fs.ModeDir},
+		},
+		`\\nas\proj`: fstest.MapFS{
+			"builds/app.zip": {},
+		},
+	}
+	open := func(root string) fs.FS {
+		fsys := drives.open(root)
+		if fsys == nil {
+			return nil
+		}
+		return countingFS{FS: fsys, opens: opens}
+	}
+	return NewFSExistenceChecker(open, time.Second, time.Minute, clk)
+}
+
+func TestFSExistenceChecker_Exists(t *testing.T) {
+	var opens atomic.Int32
+	c := newTestExistChecker(clock.NewFake(time.Unix(0, 0)), &opens)
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{`C:\Users\me\repo\main.go`, true},
+		{`c:\Users\me\repo\`, true},
+		{`C:/Users/me/repo/../repo/main.go`, true},
+		{`C:\`, true},
+		{`C:\Windows\System32`, true},
+		{`C:\Users\me\missing.txt`, false},
+		{`D:\Users`, false},
+		{`\\nas\proj\builds\app.zip`, true},
+		{`\\nas\other\x`, false},
+		// 相对路径和只有服务器名的UNC路径无法查询
+		{`Users\me\repo`, false},
+		{`C:Users`, false},
+		{`\\nas`, false},
+		{`%USERPROFILE%\repo`, false},
+	}
+	for _, tt := range tests {
+		if got := c.Exists(tt.input); got != tt.want {
+			t.Errorf("Exists(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFSExistenceChecker_Cache(t *testing.T) {
+	var opens atomic.Int32
+	clk := clock.NewFake(time.Unix(0, 0))
+	c := newTestExistChecker(clk, &opens)
+
+	c.Exists(`C:\Users\me\repo\main.go`)
+	c.Exists(`C:\Users\me\missing.txt`)
+	n := opens.Load()
+	// 缓存不区分大小写，不存在的结果同样缓存
+	c.Exists(`c:\users\me\repo\main.go`)
+	c.Exists(`C:\Users\me\missing.txt`)
+	if got := opens.Load(); got != n {
+		t.Errorf("cached lookups should not open files, opened %d more", got-n)
+	}
+
+	clk.Advance(time.Minute)
+	c.Exists(`C:\Users\me\repo\main.go`)
+	if got := opens.Load(); got == n {
+		t.Error("expired entries should be looked up again")
+	}
+}
+
+func TestFSExistenceChecker_Timeout(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	slow := blockingFS{
+		FS:      fstest.MapFS{"builds/app.zip": {}},
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	c := NewFSExistenceChecker(func(string) fs.FS { return slow }, 200*time.Millisecond, time.Minute, clk)
+
+	done := make(chan bool)
+	go func() { done <- c.Exists(`\\slow\share\builds\missing.zip`) }()
+	<-slow.started
+	clk.Advance(200 * time.Millisecond)
+	if !<-done {
+		t.Error("a lookup that times out should be treated as existing")
+	}
+
+	// 超时的查询完成后结果写入缓存
+	close(slow.release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if exists, ok := c.lookup.cache.get(`\\slow\share/builds/missing.zip`, clk.Now()); ok {
+			if exists {
+				t.Error("the late result should be cached as missing")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the late result was not cached")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if c.Exists(`\\slow\share\builds\missing.zip`) {
+		t.Error("the cached result should be used after the slow lookup finishes")
+	}
+}
+
+func TestShouldConvert_Existence(t *testing.T) {
+	var opens atomic.Int32
+	pc := newTestConverter()
+	pc.SetEnvOptions(EnvKeep, ContractNone, MapEnv(map[string]string{"USERPROFILE": `C:\Users\me`}))
+	pc.SetExistenceCheck(ExistPath, newTestExistChecker(clock.NewFake(time.Unix(0, 0)), &opens), []string{`D:\src\`})
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{`C:\Users\me\repo\main.go`, true},
+		{`C:\Users\me\repo\new.go`, false},
+		{`%USERPROFILE%\repo\main.go`, true},
+		// 允许的根目录下的路径无需存在
+		{`D:\src\app\new.go`, true},
+		{`d:\SRC`, true},
+		{`D:\srcs\x`, false},
+		{`src\app\main.go`, false},
+		{`C:\Users\me\repo\main.go;C:\missing`, true},
+	}
+	for _, tt := range tests {
+		if got := pc.ShouldConvert(tt.input); got != tt.want {
+			t.Errorf("ShouldConvert(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	pc.SetExistenceCheck(ExistParent, newTestExistChecker(clock.NewFake(time.Unix(0, 0)), &opens), nil)
+	if !pc.ShouldConvert(`C:\Users\me\repo\new.go`) {
+		t.Error("parent mode should accept a missing file in an existing directory")
+	}
+	if pc.ShouldConvert(`C:\Users\me\missing\new.go`) {
+		t.Error("parent mode should reject a file whose directory is missing")
+	}
+	// 相对路径按基准目录解析后检查，位置后缀不参与检查
+	pc.SetBaseDir(`C:\Users\me`)
+	if !pc.ShouldConvert(`repo\main.go:12:3`) {
+		t.Error("relative paths should be checked against the base directory")
+	}
+	if got := pc.ConvertTo(`C:\Users\me\repo\main.go;C:\nope\missing`, DialectForward); got != `C:/Users/me/repo/main.go;C:\nope\missing` {
+		t.Errorf("only existing list items should be converted, got %q", got)
+	}
+}
+
+func TestParseExistMode(t *testing.T) {
+	for name, want := range map[string]ExistMode{"": ExistOff, "Path": ExistPath, " parent ": ExistParent} {
+		if got, err := ParseExistMode(name); err != nil || got != want {
+			t.Errorf("ParseExistMode(%q) = (%q, %v), want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseExistMode("always"); err == nil {
+		t.Error("ParseExistMode should reject unknown modes")
+	}
+}
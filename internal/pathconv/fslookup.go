@@ -0,0 +1,177 @@
+package pathconv
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+// 文件系统查询的默认值，存在性检查与大小写还原共用
+const (
+	DefaultFSTimeout   = 200 * time.Millisecond // 单次查询的超时时间
+	DefaultFSCacheTTL  = time.Minute            // 查询结果的缓存时间
+	DefaultFSCacheSize = 256                    // 缓存的最大条目数
+)
+
+// timedLookup 带超时和缓存的文件系统查询
+// 查询在独立协程中进行，超时后立即返回，慢速网络共享不会阻塞剪贴板处理；
+// 超时的查询完成后结果仍会写入缓存，供之后的查询使用。
+// 同一个键同时只有一个查询，查询未完成时再次查询该键会等待同一个结果，
+// 无响应的网络共享不会因反复复制而堆积协程
+type timedLookup[V any] struct {
+	timeout time.Duration   // 单次查询的超时时间，不大于0时不限制
+	clock   clock.Clock     // 时间源，用于超时和缓存过期，测试时可替换
+	cache   *lookupCache[V] // 查询结果缓存
+
+	mu    sync.Mutex                // 保护calls
+	calls map[string]*lookupCall[V] // 进行中的查询，按键索引
+}
+
+// lookupCall 一次进行中的查询
+type lookupCall[V any] struct {
+	done  chan struct{} // 查询完成时关闭
+	value V             // 查询结果，done关闭后有效
+}
+
+// newTimedLookup 创建带超时和缓存的查询
+// 参数:
+//   - timeout: 单次查询的超时时间，不大于0时不限制
+//   - ttl: 查询结果的缓存时间，不大于0时不缓存
+//   - c: 时间源
+//
+// 返回值:
+//   - *timedLookup[V]: 查询实例
+func newTimedLookup[V any](timeout, ttl time.Duration, c clock.Clock) *timedLookup[V] {
+	return &timedLookup[V]{
+		timeout: timeout,
+		clock:   c,
+		cache:   newLookupCache[V](DefaultFSCacheSize, ttl),
+		calls:   make(map[string]*lookupCall[V]),
+	}
+}
+
+// do 返回缓存中的结果，没有时执行查询
+// 参数:
+//   - key: 缓存的键
+//   - query: 实际的文件系统查询
+//
+// 返回值:
+//   - V: 查询结果
+//   - bool: 是否在超时前得到结果
+func (l *timedLookup[V]) do(key string, query func() V) (V, bool) {
+	if v, ok := l.cache.get(key, l.clock.Now()); ok {
+		return v, true
+	}
+
+	call := l.start(key, query)
+	timedOut := make(chan struct{})
+	if l.timeout > 0 {
+		timer := l.clock.AfterFunc(l.timeout, func() { close(timedOut) })
+		defer timer.Stop()
+	}
+
+	select {
+	case <-call.done:
+		return call.value, true
+	case <-timedOut:
+		var zero V
+		return zero, false
+	}
+}
+
+// start 返回该键进行中的查询，没有时在新协程中开始查询
+func (l *timedLookup[V]) start(key string, query func() V) *lookupCall[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if call, ok := l.calls[key]; ok {
+		return call
+	}
+	call := &lookupCall[V]{done: make(chan struct{})}
+	l.calls[key] = call
+	go func() {
+		call.value = query()
+		l.cache.put(key, call.value, l.clock.Now())
+		l.mu.Lock()
+		delete(l.calls, key)
+		l.mu.Unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+// lookupCache 带过期时间的LRU缓存，记录文件系统查询的结果
+type lookupCache[V any] struct {
+	mu       sync.Mutex               // 保护以下字段，查询协程与调用方并发访问
+	capacity int                      // 最大条目数
+	ttl      time.Duration            // 条目的有效时长
+	order    *list.List               // 按最近使用排序的条目，最近使用的在前
+	items    map[string]*list.Element // 键到条目的索引
+}
+
+// lookupEntry 缓存的一条查询结果
+type lookupEntry[V any] struct {
+	key     string    // 缓存的键，由查询方决定
+	value   V         // 查询结果
+	expires time.Time // 过期时间
+}
+
+// newLookupCache 创建缓存
+// 参数:
+//   - capacity: 最大条目数，超出时淘汰最久未使用的条目
+//   - ttl: 条目的有效时长，不大于0时不缓存
+//
+// 返回值:
+//   - *lookupCache[V]: 缓存实例
+func newLookupCache[V any](capacity int, ttl time.Duration) *lookupCache[V] {
+	return &lookupCache[V]{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// get 查询未过期的条目，过期的条目被删除
+// 返回值:
+//   - V: 缓存的结果
+//   - bool: 是否命中
+func (c *lookupCache[V]) get(key string, now time.Time) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var zero V
+	e, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	entry := e.Value.(*lookupEntry[V])
+	if !now.Before(entry.expires) {
+		c.order.Remove(e)
+		delete(c.items, key)
+		return zero, false
+	}
+	c.order.MoveToFront(e)
+	return entry.value, true
+}
+
+// put 添加或更新条目
+func (c *lookupCache[V]) put(key string, value V, now time.Time) {
+	if c.ttl <= 0 || c.capacity <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		entry := e.Value.(*lookupEntry[V])
+		entry.value, entry.expires = value, now.Add(c.ttl)
+		c.order.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lookupEntry[V]{key: key, value: value, expires: now.Add(c.ttl)})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lookupEntry[V]).key)
+	}
+}
@@ -0,0 +1,80 @@
+package pathconv
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+func TestLookupCache_Evict(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := newLookupCache[bool](2, time.Minute)
+	c.put("a", true, now)
+	c.put("b", true, now)
+	c.get("a", now)
+	c.put("c", false, now)
+	if _, ok := c.get("b", now); ok {
+		t.Error("the least recently used entry should be evicted")
+	}
+	if exists, ok := c.get("a", now); !ok || !exists {
+		t.Errorf("get(a) = (%v, %v), want (true, true)", exists, ok)
+	}
+	if exists, ok := c.get("c", now); !ok || exists {
+		t.Errorf("get(c) = (%v, %v), want (false, true)", exists, ok)
+	}
+	if _, ok := c.get("a", now.Add(time.Minute)); ok {
+		t.Error("entries should expire after the TTL")
+	}
+}
+
+func TestTimedLookup_SharesInFlightQuery(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	l := newTimedLookup[bool](200*time.Millisecond, time.Minute, clk)
+	var queries atomic.Int32
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	query := func() bool {
+		queries.Add(1)
+		started <- struct{}{}
+		<-release
+		return true
+	}
+
+	// 查询未完成时反复超时的调用不应再开始新的查询
+	for i := 0; i < 3; i++ {
+		done := make(chan bool)
+		go func() {
+			_, ok := l.do("key", query)
+			done <- ok
+		}()
+		if i == 0 {
+			<-started
+		}
+		// 调用方注册超时定时器的时机不确定，推进时间直到调用返回
+		for waiting := true; waiting; {
+			clk.Advance(200 * time.Millisecond)
+			select {
+			case ok := <-done:
+				if ok {
+					t.Errorf("call %d: a blocked lookup should time out", i)
+				}
+				waiting = false
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}
+
+	done := make(chan bool)
+	go func() {
+		v, ok := l.do("key", query)
+		done <- v && ok
+	}()
+	close(release)
+	if !<-done {
+		t.Error("a waiting call should receive the result of the in-flight query")
+	}
+	if got := queries.Load(); got != 1 {
+		t.Errorf("concurrent misses started %d queries, want 1", got)
+	}
+}
@@ -0,0 +1,143 @@
+package pathconv
+
+import "strings"
+
+// 路径列表的分隔符
+const (
+	listSepSemicolon = ";"    // PATH环境变量形式的列表
+	listSepColon     = ":"    // Unix PATH形式的列表
+	listSepLF        = "\n"   // 每行一个路径，如搜索结果
+	listSepCRLF      = "\r\n" // Windows换行符分隔的列表
+)
+
+// SplitList 将文本拆分为路径列表
+// 含有换行符的文本按行拆分，保留空行和结尾的空元素以便原样拼接；
+// 否则按分号拆分，双引号内的分号不作为分隔符（PATH中含分号的目录需要加引号）
+// 参数:
+//   - text: 要拆分的文本
+//
+// 返回值:
+//   - []string: 列表元素，不是列表时为nil
+//   - string: 元素之间的分隔符，不是列表时为空字符串
+func SplitList(text string) ([]string, string) {
+	if strings.Contains(text, "\n") {
+		sep := listSepLF
+		if strings.Contains(text, listSepCRLF) {
+			sep = listSepCRLF
+		}
+		return strings.Split(text, sep), sep
+	}
+
+	var items []string
+	start, inQuotes := 0, false
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ';':
+			if !inQuotes {
+				items = append(items, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if items == nil {
+		return nil, ""
+	}
+	return append(items, text[start:]), listSepSemicolon
+}
+
+// joinsWithColon 报告该格式的路径列表是否使用冒号分隔
+// WSL和MSYS的PATH与Unix相同，使用冒号分隔
+func (d Dialect) joinsWithColon() bool {
+	return d == DialectWSL || d == DialectMSYS
+}
+
+// isList 报告文本是否应按路径列表逐个转换
+// 分号可以出现在文件名中，只有至少两个元素且每个非空元素都是盘符、UNC或环境变量开头的路径时才按分号拆分；
+// 多行文本只有每个非空行都是路径时才按行拆分，避免只改写脚本中的个别行。
+// 设置了dropNonPaths时允许不是路径的元素（它们会被去掉），但分号列表仍需至少两个这样的路径
+func (pc *PathConverter) isList(text string) bool {
+	items, sep := SplitList(text)
+	if sep == "" {
+		return false
+	}
+	anchored, paths, others := 0, 0, 0
+	for _, item := range items {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		if isAnchoredItem(item) {
+			anchored++
+		}
+		if pc.shouldConvertItem(item) {
+			paths++
+		} else {
+			others++
+		}
+	}
+	if sep == listSepSemicolon {
+		if anchored < 2 || (anchored < paths+others && !pc.dropNonPaths) {
+			return false
+		}
+	} else if others > 0 && !pc.dropNonPaths {
+		return false
+	}
+	return paths > 0
+}
+
+// isAnchoredItem 报告列表元素是否为盘符、UNC或环境变量开头的路径，元素两端的空白和引号不影响判断
+func isAnchoredItem(item string) bool {
+	item = strings.Trim(strings.TrimSpace(item), `"`)
+	if item == "" {
+		return false
+	}
+	root, _, absolute := splitRoot(item)
+	return absolute && root != `\`
+}
+
+// convertList 分别转换列表中的每个路径，再按输出格式重新拼接
+// 分号列表在WSL、MSYS格式下改用冒号拼接，得到有效的Unix PATH，此时空元素（表示当前目录）被去掉；
+// 分号列表中的元素不按shell规则加引号，只保留或去掉原有的引号
+// 参数:
+//   - text: 要转换的文本
+//   - d: 输出格式
+//
+// 返回值:
+//   - string: 转换后的文本
+//   - bool: 文本是否为路径列表
+func (pc *PathConverter) convertList(text string, d Dialect) (string, bool) {
+	if !pc.isList(text) {
+		return "", false
+	}
+	items, sep := SplitList(text)
+
+	join, policy := sep, pc.quotePolicy
+	if sep == listSepSemicolon {
+		policy = QuoteKeep
+		if d.joinsWithColon() {
+			join, policy = listSepColon, QuoteNone
+		}
+	}
+
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if !pc.shouldConvertItem(item) {
+			if pc.dropNonPaths || (join == listSepColon && strings.TrimSpace(item) == "") {
+				continue
+			}
+			out = append(out, item)
+			continue
+		}
+		// 保留元素两端的空白，如 "C:\a; C:\b" 中的空格；冒号列表中的空白没有意义
+		core := strings.TrimSpace(item)
+		converted := pc.convertText(core, d, policy)
+		if join != listSepColon {
+			i := strings.Index(item, core)
+			converted = item[:i] + converted + item[i+len(core):]
+		}
+		out = append(out, converted)
+	}
+	pc.logger.Debug("路径列表转换(%s): %d 个元素", d, len(items))
+	return strings.Join(out, join), true
+}
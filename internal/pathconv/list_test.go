@@ -0,0 +1,101 @@
+package pathconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		input string
+		items []string
+		sep   string
+	}{
+		{`C:\a;C:\b`, []string{`C:\a`, `C:\b`}, ";"},
+		{`"C:\a;b";C:\c`, []string{`"C:\a;b"`, `C:\c`}, ";"},
+		{`C:\a;;C:\b;`, []string{`C:\a`, ``, `C:\b`, ``}, ";"},
+		{"C:\\a\nC:\\b\n", []string{`C:\a`, `C:\b`, ``}, "\n"},
+		{"C:\\a\r\nC:\\b", []string{`C:\a`, `C:\b`}, "\r\n"},
+		{`C:\a`, nil, ""},
+	}
+	for _, tt := range tests {
+		items, sep := SplitList(tt.input)
+		if !reflect.DeepEqual(items, tt.items) || sep != tt.sep {
+			t.Errorf("SplitList(%q) = (%q, %q), want (%q, %q)", tt.input, items, sep, tt.items, tt.sep)
+		}
+	}
+}
+
+func TestConvertTo_Lists(t *testing.T) {
+	pc := newTestConverter()
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`C:\a;C:\b;%SystemRoot%\system32`, DialectForward, `C:/a;C:/b;%SystemRoot%/system32`},
+		{`C:\a;C:\b`, DialectWSL, `/mnt/c/a:/mnt/c/b`},
+		{`C:\a;;D:\b;`, DialectMSYS, `/c/a:/d/b`},
+		{`"C:\Program Files\App";C:\bin`, DialectForward, `"C:/Program Files/App";C:/bin`},
+		{`"C:\Program Files\App";C:\bin`, DialectWSL, `/mnt/c/Program Files/App:/mnt/c/bin`},
+		{`C:\a; C:\b`, DialectForward, `C:/a; C:/b`},
+		{`C:\a;C:\b`, DialectEscaped, `"C:\\a";"C:\\b"`},
+		// 含分号的单个路径不拆分
+		{`C:\Users\me\My;Stuff\a.txt`, DialectWSL, `/mnt/c/Users/me/My;Stuff/a.txt`},
+		{`C:\a;%X%;\\srv\s`, DialectForward, `C:/a;%X%;//srv/s`},
+		{"C:\\src\\a.go\nC:\\src\\b.go\n", DialectWSL, "/mnt/c/src/a.go\n/mnt/c/src/b.go\n"},
+		{"C:\\src\\a.go\r\n\r\nD:\\b.txt", DialectMSYS, "/c/src/a.go\r\n\r\n/d/b.txt"},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestConvertTo_ListQuoting(t *testing.T) {
+	pc := newTestConverter()
+	pc.SetQuoting(QuoteAuto, ShellPOSIX)
+	if got := pc.ConvertTo("C:\\My Files\\a.txt\nC:\\b.txt", DialectWSL); got != "'/mnt/c/My Files/a.txt'\n/mnt/c/b.txt" {
+		t.Errorf("newline lists should quote each element, got %q", got)
+	}
+	if got := pc.ConvertTo(`C:\My Files;C:\b`, DialectWSL); got != `/mnt/c/My Files:/mnt/c/b` {
+		t.Errorf("PATH lists should not be shell-quoted, got %q", got)
+	}
+}
+
+func TestConvertTo_DropNonPaths(t *testing.T) {
+	pc := newTestConverter()
+	pc.SetListOptions(true, true)
+	input := "Found 2 files:\nC:\\src\\a.go\n\nC:\\src\\b.go\n"
+	if got := pc.ConvertTo(input, DialectForward); got != "C:/src/a.go\nC:/src/b.go" {
+		t.Errorf("ConvertTo with dropNonPaths = %q", got)
+	}
+	if got := pc.ConvertTo(`C:\a;HKLM\Software;C:\b`, DialectForward); got != `C:/a;C:/b` {
+		t.Errorf("ConvertTo with dropNonPaths = %q", got)
+	}
+}
+
+func TestShouldConvert_Lists(t *testing.T) {
+	pc := newTestConverter()
+	if pc.ShouldConvert(`HKLM\Software;HKCU\Console`) {
+		t.Error("a list without paths should not be convertible")
+	}
+	// 混有其他内容的多行文本不按行拆分，不会只改写其中的个别行
+	for _, text := range []string{"@echo off\nC:\\tools\\build.bat\ncd C:\\src", "see below:\nC:\\src\\a.go"} {
+		if pc.ShouldConvert(text) {
+			t.Errorf("ShouldConvert(%q) should be false when some lines are not paths", text)
+		}
+	}
+	if _, ok := pc.convertList(`C:\a;HKLM\Software`, DialectForward); ok {
+		t.Error("a semicolon list with an element that is not an anchored path should not be split")
+	}
+	if _, ok := pc.convertList(`C:\a;`, DialectForward); ok {
+		t.Error("a semicolon list needs at least two anchored paths")
+	}
+
+	pc.SetListOptions(true, true)
+	if !pc.ShouldConvert("see below:\nC:\\src\\a.go") {
+		t.Error("with dropNonPaths a text containing a path should be convertible")
+	}
+}
@@ -0,0 +1,118 @@
+package pathconv
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// isLiteral 报告该格式是否为编程语言的字符串字面量
+func (d Dialect) isLiteral() bool {
+	switch d {
+	case DialectJSON, DialectC, DialectGo, DialectPython, DialectCSharp, DialectStringForward:
+		return true
+	}
+	return false
+}
+
+// formatLiteral 将路径编码为目标语言的字符串字面量，字面量解码后与路径完全相同
+// 参数:
+//   - content: 不含外层引号的路径
+//   - d: 字面量格式
+//
+// 返回值:
+//   - string: 带引号的字符串字面量
+func formatLiteral(content string, d Dialect) string {
+	switch d {
+	case DialectJSON:
+		return jsonLiteral(content)
+	case DialectGo:
+		return strconv.Quote(content)
+	case DialectPython:
+		return pythonLiteral(content)
+	case DialectCSharp:
+		// 逐字字符串中只有双引号需要写成两个
+		return `@"` + strings.ReplaceAll(content, `"`, `""`) + `"`
+	case DialectStringForward:
+		// JSON的转义在Go、Python、C#中同样有效；Windows路径不含控制字符，C中也只需转义双引号
+		return jsonLiteral(toSlash(content))
+	}
+	return cLiteral(content)
+}
+
+// jsonLiteral 编码JSON字符串，不转义 <、>、& 以保持路径可读
+func jsonLiteral(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		// 字符串的编码不会失败，无效的UTF-8被替换为U+FFFD
+		return strconv.Quote(s)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// cLiteral 编码C字符串
+// 控制字符使用三位八进制转义，避免十六进制转义吞掉后面的字符；连续的 ?? 写成 ?\? 以免构成三字符组
+func cLiteral(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' || c == '"':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '?' && i > 0 && s[i-1] == '?':
+			b.WriteString(`\?`)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case isControl(rune(c)):
+			fmt.Fprintf(&b, `\%03o`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// pythonLiteral 编码Python字符串，优先使用原始字符串 r"..."
+// 原始字符串不能含有双引号和控制字符，也不能以奇数个反斜杠结尾（如 r"C:\dir\"），此时改用普通字符串
+func pythonLiteral(s string) string {
+	trailing := len(s) - len(strings.TrimRight(s, `\`))
+	if !strings.Contains(s, `"`) && strings.IndexFunc(s, isControl) < 0 && trailing%2 == 0 {
+		return `r"` + s + `"`
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '"':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case isControl(r):
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// isControl 报告字符是否为ASCII控制字符
+func isControl(r rune) bool {
+	return r < ' ' || r == 0x7f
+}
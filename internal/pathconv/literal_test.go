@@ -0,0 +1,171 @@
+package pathconv
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestConvertTo_Literals(t *testing.T) {
+	pc := newTestConverter()
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`C:\Users\me`, DialectJSON, `"C:\\Users\\me"`},
+		{`C:\Users\me`, DialectC, `"C:\\Users\\me"`},
+		{`C:\Users\me`, DialectGo, `"C:\\Users\\me"`},
+		{`C:\Users\me`, DialectPython, `r"C:\Users\me"`},
+		{`C:\Users\me`, DialectCSharp, `@"C:\Users\me"`},
+		{`C:\Users\me`, DialectStringForward, `"C:/Users/me"`},
+		{`"C:\Program Files\App"`, DialectPython, `r"C:\Program Files\App"`},
+		{`C:\a&b\<c>`, DialectJSON, `"C:\\a&b\\<c>"`},
+		{`C:\what??\x`, DialectC, `"C:\\what?\?\\x"`},
+		{`C:\日本語\ファイル.txt`, DialectGo, `"C:\\日本語\\ファイル.txt"`},
+		// 原始字符串不能以奇数个反斜杠结尾
+		{`C:\dir\`, DialectPython, `"C:\\dir\\"`},
+		{`\\server\share`, DialectCSharp, `@"\\server\share"`},
+		{`\\?\C:\very\long`, DialectJSON, `"\\\\?\\C:\\very\\long"`},
+		{`\\?\C:\very\long`, DialectStringForward, `"C:/very/long"`},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %s, want %s", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestFormatLiteral_Special(t *testing.T) {
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{"C:\\a\"b", DialectPython, `"C:\\a\"b"`},
+		{"C:\\a\"b", DialectCSharp, `@"C:\a""b"`},
+		{"C:\\a\tb", DialectPython, `"C:\\a\tb"`},
+		{"C:\\a\x01b", DialectC, `"C:\\a\001b"`},
+		{"C:\\a\x01b", DialectJSON, `"C:\\a\u0001b"`},
+	}
+	for _, tt := range tests {
+		if got := formatLiteral(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("formatLiteral(%q, %s) = %s, want %s", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+// decodeLiteral 按目标语言的规则解码字符串字面量，只支持formatLiteral会产生的转义
+func decodeLiteral(lit string, d Dialect) (string, error) {
+	switch d {
+	case DialectJSON, DialectStringForward:
+		var s string
+		err := json.Unmarshal([]byte(lit), &s)
+		return s, err
+	case DialectGo:
+		return strconv.Unquote(lit)
+	case DialectCSharp:
+		if !strings.HasPrefix(lit, `@"`) || !strings.HasSuffix(lit, `"`) || len(lit) < 3 {
+			return "", strconv.ErrSyntax
+		}
+		body := lit[2 : len(lit)-1]
+		if strings.Count(body, `"`)%2 != 0 {
+			return "", strconv.ErrSyntax
+		}
+		return strings.ReplaceAll(body, `""`, `"`), nil
+	case DialectPython:
+		if strings.HasPrefix(lit, `r"`) {
+			body := lit[2 : len(lit)-1]
+			trailing := len(body) - len(strings.TrimRight(body, `\`))
+			if strings.Contains(body, `"`) || trailing%2 != 0 {
+				return "", strconv.ErrSyntax
+			}
+			return body, nil
+		}
+	}
+	return decodeEscapes(lit)
+}
+
+// decodeEscapes 解码C和Python的普通字符串，支持 \\ \" \? \n \r \t \ooo \xhh
+func decodeEscapes(lit string) (string, error) {
+	if len(lit) < 2 || lit[0] != '"' || lit[len(lit)-1] != '"' {
+		return "", strconv.ErrSyntax
+	}
+	body := lit[1 : len(lit)-1]
+	if strings.Contains(body, "??/") || strings.Contains(body, "??=") {
+		return "", strconv.ErrSyntax // 三字符组
+	}
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c == '"' {
+			return "", strconv.ErrSyntax
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		if i++; i >= len(body) {
+			return "", strconv.ErrSyntax
+		}
+		switch e := body[i]; {
+		case e == '\\' || e == '"' || e == '?':
+			b.WriteByte(e)
+		case e == 'n':
+			b.WriteByte('\n')
+		case e == 'r':
+			b.WriteByte('\r')
+		case e == 't':
+			b.WriteByte('\t')
+		case e == 'x' && i+2 < len(body):
+			v, err := strconv.ParseUint(body[i+1:i+3], 16, 8)
+			if err != nil {
+				return "", err
+			}
+			b.WriteByte(byte(v))
+			i += 2
+		case e >= '0' && e <= '7' && i+2 < len(body):
+			v, err := strconv.ParseUint(body[i:i+3], 8, 8)
+			if err != nil {
+				return "", err
+			}
+			b.WriteByte(byte(v))
+			i += 2
+		default:
+			return "", strconv.ErrSyntax
+		}
+	}
+	return b.String(), nil
+}
+
+func TestLiterals_RoundTrip(t *testing.T) {
+	roundTrip := func(d Dialect) func(p windowsPath) bool {
+		return func(p windowsPath) bool {
+			in := string(p)
+			want := in
+			if d == DialectStringForward {
+				want = toSlash(in)
+			}
+			lit := formatLiteral(in, d)
+			back, err := decodeLiteral(lit, d)
+			if err != nil || back != want {
+				t.Logf("%q -> %s -> %q (%v)", in, lit, back, err)
+				return false
+			}
+			return true
+		}
+	}
+
+	for _, d := range []Dialect{DialectJSON, DialectC, DialectGo, DialectPython, DialectCSharp, DialectStringForward} {
+		if err := quick.Check(roundTrip(d), &quick.Config{MaxCount: 2000}); err != nil {
+			t.Errorf("%s round trip: %v", d, err)
+		}
+		for _, in := range []string{"C:\\a\"b\\", "C:\\??\\x\t\x01\x7f", `C:\dir\\`, `\\?\UNC\s\x`} {
+			if !roundTrip(d)(windowsPath(in)) {
+				t.Errorf("%s round trip failed for %q", d, in)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package pathconv
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Location 编译器和构建工具输出中的源码位置
+type Location struct {
+	Path   string // 文件路径
+	Line   int    // 行号
+	Column int    // 列号，没有时为0
+	Suffix string // 路径之后的位置后缀原文，如 :12:3 或 (12,3)
+}
+
+// gccLocationPattern gcc、go等工具的位置格式：file:line 或 file:line:col，结尾可以有一个冒号
+var gccLocationPattern = regexp.MustCompile(`^(.+?)(:(\d+)(?::(\d+))?:?)$`)
+
+// msbuildLocationPattern MSBuild的位置格式：file(line)、file(line,col) 或 file(line,col,endLine,endCol)
+var msbuildLocationPattern = regexp.MustCompile(`^(.+?)(\((\d+)(?:,(\d+)(?:,\d+,\d+)?)?\):?)$`)
+
+// ParseLocation 识别带位置后缀的文件路径，如 src\pkg\file.go:12:3 或 Program.cs(12,3)
+// 文件名中不能出现冒号，因此 :line:col 形式没有歧义；(line,col) 形式要求文件名带扩展名，
+// 避免把 New Folder(2) 这样的名称误认为位置
+// 参数:
+//   - text: 要识别的文本
+//
+// 返回值:
+//   - Location: 路径与位置
+//   - bool: 文本是否带有位置后缀
+func ParseLocation(text string) (Location, bool) {
+	if m := gccLocationPattern.FindStringSubmatch(text); m != nil && !isDriveLetter(m[1]) {
+		return newLocation(m), true
+	}
+	if m := msbuildLocationPattern.FindStringSubmatch(text); m != nil && hasExtension(m[1]) {
+		return newLocation(m), true
+	}
+	return Location{}, false
+}
+
+// newLocation 由位置格式的匹配结果创建Location
+func newLocation(m []string) Location {
+	loc := Location{Path: m[1], Suffix: m[2]}
+	loc.Line, _ = strconv.Atoi(m[3])
+	if m[4] != "" {
+		loc.Column, _ = strconv.Atoi(m[4])
+	}
+	return loc
+}
+
+// isDriveLetter 报告文本是否只是一个盘符字母，如 C:12 中的 C
+func isDriveLetter(s string) bool {
+	return len(s) == 1 && isASCIILetter(s[0])
+}
+
+// hasExtension 报告路径的最后一段是否带有扩展名
+func hasExtension(p string) bool {
+	name := p[strings.LastIndexAny(p, `\/`)+1:]
+	i := strings.LastIndex(name, ".")
+	return i > 0 && i < len(name)-1 && !strings.ContainsAny(name[i:], " \t")
+}
+
+// splitLocation 拆分路径与位置后缀，不带位置后缀时后缀为空
+func splitLocation(text string) (string, string) {
+	if loc, ok := ParseLocation(text); ok {
+		return loc.Path, loc.Suffix
+	}
+	return text, ""
+}
+
+// formatLocation 按输出格式格式化路径并加上位置后缀
+// 自带引号的格式将后缀写入字符串内部，其他格式追加在路径之后
+func formatLocation(content, suffix string, d Dialect) string {
+	if d.quotesOwnOutput() {
+		return formatPath(content+suffix, d)
+	}
+	return formatPath(content, d) + suffix
+}
+
+// ValidateBaseDir 检查相对路径的基准目录是否为绝对路径
+// 参数:
+//   - dir: 基准目录，空字符串表示不解析相对路径
+//
+// 返回值:
+//   - error: 基准目录不是盘符、UNC或环境变量开头的绝对路径时返回错误
+func ValidateBaseDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	if root, _, absolute := splitRoot(dir); !absolute || root == `\` {
+		return fmt.Errorf("基准目录必须是绝对路径: %s", dir)
+	}
+	return nil
+}
+
+// SetBaseDir 设置解析相对路径的基准目录
+// 参数:
+//   - dir: 基准目录，调用方需先用ValidateBaseDir检查；空字符串表示不解析相对路径
+func (pc *PathConverter) SetBaseDir(dir string) {
+	pc.baseDir = strings.TrimRight(dir, `\/`)
+}
+
+// resolveRelative 将相对路径（如 src\main.go、..\lib）拼接到基准目录之后并规范化
+// 绝对路径、C:foo 这样相对于驱动器当前目录的路径和多行文本保持不变
+func (pc *PathConverter) resolveRelative(p string) string {
+	if pc.baseDir == "" || p == "" || strings.ContainsAny(p, "\r\n") {
+		return p
+	}
+	if root, _, _ := splitRoot(p); root != "" {
+		return p
+	}
+	resolved := Normalize(pc.baseDir+`\`+p, pc.trailing)
+	pc.logger.Debug("解析相对路径: %s -> %s", p, resolved)
+	return resolved
+}
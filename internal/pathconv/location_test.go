@@ -0,0 +1,111 @@
+package pathconv
+
+import "testing"
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Location
+		ok    bool
+	}{
+		// gcc、go等工具的 file:line:col 形式
+		{`src\pkg\file.go:12:3`, Location{`src\pkg\file.go`, 12, 3, ":12:3"}, true},
+		{`C:\src\main.c:7`, Location{`C:\src\main.c`, 7, 0, ":7"}, true},
+		{`C:\src\main.c:7:1:`, Location{`C:\src\main.c`, 7, 1, ":7:1:"}, true},
+		{`Makefile:12`, Location{`Makefile`, 12, 0, ":12"}, true},
+		// MSBuild的 file(line,col) 形式
+		{`src\Program.cs(12,3)`, Location{`src\Program.cs`, 12, 3, "(12,3)"}, true},
+		{`C:\proj\App.xaml(5)`, Location{`C:\proj\App.xaml`, 5, 0, "(5)"}, true},
+		{`C:\proj\a.cs(10,5,10,9):`, Location{`C:\proj\a.cs`, 10, 5, "(10,5,10,9):"}, true},
+		{`C:\dir (1)\a.cs(3,4)`, Location{`C:\dir (1)\a.cs`, 3, 4, "(3,4)"}, true},
+		// 不带扩展名的 (n) 是文件名的一部分
+		{`C:\Users\me\New Folder(2)`, Location{}, false},
+		{`C:\Users\me\report (2)`, Location{}, false},
+		{`C:12`, Location{}, false},
+		{`C:\src\main.go`, Location{}, false},
+		{`C:\src\main.go:x`, Location{}, false},
+		{":12", Location{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseLocation(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseLocation(%q) = (%+v, %v), want (%+v, %v)", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestConvertTo_Location(t *testing.T) {
+	pc := newTestConverter()
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`C:\src\pkg\file.go:12:3`, DialectWSL, `/mnt/c/src/pkg/file.go:12:3`},
+		{`C:\src\Program.cs(12,3)`, DialectMSYS, `/c/src/Program.cs(12,3)`},
+		{`"C:\src\pkg\file.go:12:3"`, DialectForward, `"C:/src/pkg/file.go:12:3"`},
+		// 自带引号的格式将后缀写入字符串内部
+		{`C:\src\main.c:7`, DialectEscaped, `"C:\\src\\main.c:7"`},
+		{`C:\src\main.c:7`, DialectPython, `r"C:\src\main.c:7"`},
+		{`C:\src\main.c:7`, DialectFileURI, `file:///C:/src/main.c:7`},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestConvertTo_BaseDir(t *testing.T) {
+	pc := newTestConverter()
+	pc.SetBaseDir(`C:\work\app\`)
+	if !pc.ShouldConvert(`src\pkg\file.go:12:3`) {
+		t.Error("a relative path with a location suffix should be convertible")
+	}
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`src\pkg\file.go:12:3`, DialectWSL, `/mnt/c/work/app/src/pkg/file.go:12:3`},
+		{`src\Program.cs(12,3)`, DialectForward, `C:/work/app/src/Program.cs(12,3)`},
+		{`.\cmd\main.go`, DialectMSYS, `/c/work/app/cmd/main.go`},
+		{`..\lib\util.go`, DialectForward, `C:/work/lib/util.go`},
+		// 绝对路径和相对于驱动器当前目录的路径不解析
+		{`D:\other\x.go`, DialectForward, `D:/other/x.go`},
+		{`D:other\x.go`, DialectForward, `D:other/x.go`},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+
+	// 解析后的路径同样参与路径映射
+	m, err := NewMapper([]Mapping{{Windows: `C:\work`, Target: "/home/me/work"}})
+	if err != nil {
+		t.Fatalf("NewMapper returned error: %v", err)
+	}
+	pc.SetMappings(m)
+	if got := pc.ConvertTo(`src\main.go:3`, DialectForward); got != "/home/me/work/app/src/main.go:3" {
+		t.Errorf("relative paths should be mapped after resolution, got %q", got)
+	}
+
+	pc.SetBaseDir("")
+	if got := pc.ConvertTo(`src\main.go`, DialectWSL); got != "src/main.go" {
+		t.Errorf("without a base directory relative paths only change separators, got %q", got)
+	}
+}
+
+func TestValidateBaseDir(t *testing.T) {
+	for _, dir := range []string{"", `C:\work`, `C:\`, `\\nas\proj`, `%USERPROFILE%\src`} {
+		if err := ValidateBaseDir(dir); err != nil {
+			t.Errorf("ValidateBaseDir(%q) returned error: %v", dir, err)
+		}
+	}
+	for _, dir := range []string{`work\app`, `C:work`, `\work`} {
+		if err := ValidateBaseDir(dir); err == nil {
+			t.Errorf("ValidateBaseDir(%q) should fail", dir)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package pathconv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mapping 一条路径前缀映射规则，如 C:\work → /home/me/work
+type Mapping struct {
+	Windows string `json:"windows"` // Windows侧的路径前缀，如 C:\work 或 \\nas\proj
+	Target  string `json:"target"`  // 目标侧的路径前缀，如 /home/me/work
+}
+
+// Mapper 按最长前缀匹配在Windows路径与目标路径之间转换
+// Windows侧比较不区分大小写，目标侧（通常为Linux路径）区分大小写；
+// 前缀只在路径分隔符处匹配，C:\work 不会匹配 C:\workspace
+type Mapper struct {
+	rules []Mapping // 规范化后的规则
+}
+
+// NewMapper 创建路径前缀映射
+// 参数:
+//   - rules: 映射规则，顺序不影响匹配结果
+//
+// 返回值:
+//   - *Mapper: 映射实例
+//   - error: 规则的任一侧为空时返回错误
+func NewMapper(rules []Mapping) (*Mapper, error) {
+	m := &Mapper{}
+	for _, r := range rules {
+		win := strings.TrimRight(strings.ReplaceAll(r.Windows, "/", `\`), `\`)
+		target := strings.TrimRight(r.Target, "/")
+		if win == "" || r.Target == "" {
+			return nil, fmt.Errorf("无效的路径映射: %q → %q", r.Windows, r.Target)
+		}
+		// C:\ 这样的驱动器根目录去掉结尾分隔符后为 C:，/ 去掉后为空，都按根目录处理
+		m.rules = append(m.rules, Mapping{Windows: win, Target: target})
+	}
+	return m, nil
+}
+
+// ToTarget 将Windows路径转换为目标路径
+// 参数:
+//   - p: Windows路径
+//
+// 返回值:
+//   - string: 目标路径，剩余部分的分隔符替换为 /
+//   - bool: 是否有规则匹配
+func (m *Mapper) ToTarget(p string) (string, bool) {
+	best := -1
+	for i, r := range m.rules {
+		if hasPathPrefix(p, r.Windows) && (best < 0 || len(r.Windows) > len(m.rules[best].Windows)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	r := m.rules[best]
+	return joinTarget(r.Target, toSlash(p[len(r.Windows):])), true
+}
+
+// ToWindows 将目标路径转换回Windows路径，是ToTarget的逆操作
+// 参数:
+//   - p: 目标路径
+//
+// 返回值:
+//   - string: Windows路径，剩余部分的分隔符替换为 \
+//   - bool: 是否有规则匹配
+func (m *Mapper) ToWindows(p string) (string, bool) {
+	best := -1
+	for i, r := range m.rules {
+		if hasTargetPrefix(p, r.Target) && (best < 0 || len(r.Target) > len(m.rules[best].Target)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	r := m.rules[best]
+	rest := strings.ReplaceAll(p[len(r.Target):], "/", `\`)
+	if rest == "" && strings.HasSuffix(r.Windows, ":") {
+		// 驱动器根目录需要保留分隔符，C: 表示驱动器的当前目录
+		rest = `\`
+	}
+	return r.Windows + rest, true
+}
+
+// hasTargetPrefix 报告目标路径p是否以prefix开头，且前缀在 / 或结尾处结束
+// 比较区分大小写；空前缀表示根目录 /，匹配所有绝对路径
+func hasTargetPrefix(p, prefix string) bool {
+	if prefix == "" {
+		return strings.HasPrefix(p, "/")
+	}
+	return strings.HasPrefix(p, prefix) && (len(p) == len(prefix) || p[len(prefix)] == '/')
+}
+
+// joinTarget 拼接目标前缀与剩余部分
+func joinTarget(target, rest string) string {
+	if target == "" && rest == "" {
+		return "/"
+	}
+	return target + rest
+}
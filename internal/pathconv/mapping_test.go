@@ -0,0 +1,115 @@
+package pathconv
+
+import "testing"
+
+// newMappingTestConverter 创建带有重叠前缀映射的转换器
+func newMappingTestConverter(t *testing.T) *PathConverter {
+	t.Helper()
+	m, err := NewMapper([]Mapping{
+		{Windows: `C:\work`, Target: "/home/me/work"},
+		{Windows: `C:\work\big-repo\`, Target: "/data/big-repo"},
+		{Windows: `\\nas\proj`, Target: "/mnt/nas/proj"},
+		{Windows: `D:\`, Target: "/srv/d"},
+	})
+	if err != nil {
+		t.Fatalf("NewMapper failed: %v", err)
+	}
+	pc := newTestConverter()
+	pc.SetMappings(m)
+	return pc
+}
+
+func TestConvertTo_PathMappings(t *testing.T) {
+	pc := newMappingTestConverter(t)
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`C:\work\app\main.go`, DialectForward, `/home/me/work/app/main.go`},
+		// 重叠的前缀选择最长的规则
+		{`C:\work\big-repo\src`, DialectForward, `/data/big-repo/src`},
+		{`C:\work\big-repo`, DialectForward, `/data/big-repo`},
+		// Windows侧不区分大小写
+		{`c:\WORK\Big-Repo\x`, DialectWSL, `/data/big-repo/x`},
+		// 前缀只在分隔符处匹配
+		{`C:\workspace\x`, DialectForward, `C:/workspace/x`},
+		{`C:\work-old\x`, DialectWSL, `/mnt/c/work-old/x`},
+		{`\\NAS\proj\docs\a.md`, DialectForward, `/mnt/nas/proj/docs/a.md`},
+		{`D:\logs`, DialectMSYS, `/srv/d/logs`},
+		{`D:\`, DialectForward, `/srv/d/`},
+		{`"C:\work\a b"`, DialectForward, `"/home/me/work/a b"`},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestConvertReverse(t *testing.T) {
+	pc := newMappingTestConverter(t)
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"/home/me/work/app/main.go", `C:\work\app\main.go`},
+		{"/data/big-repo/src", `C:\work\big-repo\src`},
+		{"/mnt/nas/proj", `\\nas\proj`},
+		{"/srv/d", `D:\`},
+		{`"/home/me/work/a b"`, `"C:\work\a b"`},
+		// 目标侧区分大小写且按分隔符匹配
+		{"/home/me/Work/x", "/home/me/Work/x"},
+		{"/home/me/workspace", "/home/me/workspace"},
+		// 没有映射规则时识别WSL挂载路径
+		{"/mnt/c/Users/me", `C:\Users\me`},
+		{"/mnt/e", `E:\`},
+		{"/mnt/cdrom/x", "/mnt/cdrom/x"},
+		{"relative/path", "relative/path"},
+		// smb和file-uri格式的逆操作
+		{"smb://nas/proj/a%20b/c.txt", `\\nas\proj\a b\c.txt`},
+		{`"smb://nas/proj"`, `"\\nas\proj"`},
+		{"file:///C:/Users/me/%E6%96%87%E6%A1%A3", `C:\Users\me\文档`},
+		{"file://nas/proj/x", `\\nas\proj\x`},
+		{"file:///home/me", "file:///home/me"},
+		{"https://example.com/a", "https://example.com/a"},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertReverse(tt.input); got != tt.want {
+			t.Errorf("ConvertReverse(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMapper_RoundTrip(t *testing.T) {
+	m, err := NewMapper([]Mapping{
+		{Windows: `C:\work`, Target: "/home/me/work"},
+		{Windows: `C:\work\nested`, Target: "/opt/nested"},
+		{Windows: `C:\`, Target: "/"},
+	})
+	if err != nil {
+		t.Fatalf("NewMapper failed: %v", err)
+	}
+	for _, p := range []string{`C:\work\a\b`, `C:\work\nested\x`, `C:\other\y`, `C:\work`} {
+		target, ok := m.ToTarget(p)
+		if !ok {
+			t.Fatalf("ToTarget(%q) did not match", p)
+		}
+		back, ok := m.ToWindows(target)
+		if !ok || back != p {
+			t.Errorf("%q -> %q -> %q", p, target, back)
+		}
+	}
+}
+
+func TestNewMapper_Invalid(t *testing.T) {
+	for _, rules := range [][]Mapping{
+		{{Windows: "", Target: "/x"}},
+		{{Windows: `C:\x`, Target: ""}},
+		{{Windows: `\`, Target: "/x"}},
+	} {
+		if _, err := NewMapper(rules); err == nil {
+			t.Errorf("NewMapper(%v) expected error", rules)
+		}
+	}
+}
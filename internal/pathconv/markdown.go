@@ -0,0 +1,250 @@
+package pathconv
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MarkdownMode Markdown感知转换的启用方式
+type MarkdownMode string
+
+const (
+	MarkdownOff  MarkdownMode = "off"  // 不按Markdown处理
+	MarkdownAuto MarkdownMode = "auto" // 文本含有 ``` 或 ~~~ 代码块时按Markdown处理
+	MarkdownOn   MarkdownMode = "on"   // 多行文本和含有行内代码的文本总是按Markdown处理
+)
+
+// ParseMarkdownMode 解析Markdown感知转换的启用方式，不区分大小写，空字符串视为off
+// 参数:
+//   - name: 启用方式名称
+//
+// 返回值:
+//   - MarkdownMode: 对应的启用方式
+//   - error: 名称无效时返回错误
+func ParseMarkdownMode(name string) (MarkdownMode, error) {
+	switch m := MarkdownMode(strings.ToLower(strings.TrimSpace(name))); m {
+	case "":
+		return MarkdownOff, nil
+	case MarkdownOff, MarkdownAuto, MarkdownOn:
+		return m, nil
+	}
+	return "", fmt.Errorf("未知的Markdown模式: %s", name)
+}
+
+// embeddedPathPattern 正文中的Windows路径：盘符路径、UNC路径或以环境变量开头的路径
+// 正文中的路径以空白或全角标点结束，且不包含括号、引号和反引号，以便正确处理 (见 C:\x) 和 [链接](C:\x)
+var embeddedPathPattern = regexp.MustCompile(
+	`(?:[A-Za-z]:\\|\\\\[^\s\\/()\[\]` + "`" + `]+\\|%[A-Za-z_][A-Za-z0-9_()]*%\\)[^\s"'<>|*?()\[\]` + "`" + `。，；：！？、（）《》「」]*`)
+
+// fence 一个已打开的代码块
+type fence struct {
+	char    byte // 围栏字符，` 或 ~
+	length  int  // 围栏长度，关闭围栏不能短于该长度
+	convert bool // 是否转换代码块中的路径
+}
+
+// parseFenceOpen 解析代码块的开始行
+// 参数:
+//   - line: 不含换行符的一行
+//
+// 返回值:
+//   - fence: 代码块信息，convert字段由调用方设置
+//   - string: 小写的语言名称，没有声明时为空
+//   - bool: 该行是否为代码块的开始行
+func parseFenceOpen(line string) (fence, string, bool) {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if indent > 3 {
+		return fence{}, "", false
+	}
+	rest := line[indent:]
+	if rest == "" || (rest[0] != '`' && rest[0] != '~') {
+		return fence{}, "", false
+	}
+	n := len(rest) - len(strings.TrimLeft(rest, rest[:1]))
+	if n < 3 {
+		return fence{}, "", false
+	}
+	info := strings.TrimSpace(rest[n:])
+	// 反引号围栏的信息字符串中不能含有反引号，否则是行内代码
+	if rest[0] == '`' && strings.Contains(info, "`") {
+		return fence{}, "", false
+	}
+	lang := ""
+	if fields := strings.Fields(info); len(fields) > 0 {
+		lang = strings.ToLower(strings.Trim(fields[0], "{}."))
+	}
+	return fence{char: rest[0], length: n}, lang, true
+}
+
+// closes 报告该行是否关闭代码块
+func (f fence) closes(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false
+	}
+	n := len(trimmed) - len(strings.TrimLeft(trimmed, string(f.char)))
+	return n >= f.length && strings.TrimSpace(trimmed[n:]) == ""
+}
+
+// isMarkdown 报告文本在当前模式下是否按Markdown处理
+func (pc *PathConverter) isMarkdown(text string) bool {
+	switch pc.markdown {
+	case MarkdownOn:
+		return strings.Contains(text, "\n") || strings.Contains(text, "`")
+	case MarkdownAuto:
+		for _, line := range strings.Split(text, "\n") {
+			if _, _, ok := parseFenceOpen(strings.TrimSuffix(line, "\r")); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// convertMarkdown 转换Markdown文本中的路径
+// 正文和行内代码中的路径被转换，代码块只有在其语言配置为需要转换时才转换，未关闭的代码块延续到文本末尾
+// 参数:
+//   - text: Markdown文本
+//   - d: 输出格式
+//
+// 返回值:
+//   - string: 转换后的文本
+func (pc *PathConverter) convertMarkdown(text string, d Dialect) string {
+	var out, prose strings.Builder
+	flush := func() {
+		out.WriteString(pc.convertInline(prose.String(), d))
+		prose.Reset()
+	}
+
+	var open *fence
+	for _, line := range strings.SplitAfter(text, "\n") {
+		content := strings.TrimRight(line, "\r\n")
+		switch {
+		case open == nil:
+			if f, lang, ok := parseFenceOpen(content); ok {
+				flush()
+				f.convert = pc.fenceLanguages[lang]
+				open = &f
+				out.WriteString(line)
+				continue
+			}
+			prose.WriteString(line)
+			// 行内代码不能跨越段落，在空行处结束当前段落
+			if strings.TrimSpace(content) == "" {
+				flush()
+			}
+		case open.closes(content):
+			out.WriteString(line)
+			open = nil
+		case open.convert:
+			out.WriteString(pc.convertEmbedded(line, d))
+		default:
+			out.WriteString(line)
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// convertInline 转换正文中的路径，包括行内代码
+// 行内代码的内容整体是路径时整体转换（允许含空格，如 `C:\Program Files`），否则转换其中出现的路径
+// 参数:
+//   - text: 不含代码块的正文
+//   - d: 输出格式
+//
+// 返回值:
+//   - string: 转换后的正文
+func (pc *PathConverter) convertInline(text string, d Dialect) string {
+	var out strings.Builder
+	for {
+		start := strings.IndexByte(text, '`')
+		if start < 0 {
+			break
+		}
+		n := len(text[start:]) - len(strings.TrimLeft(text[start:], "`"))
+		end := findBacktickRun(text[start+n:], n)
+		if end < 0 {
+			// 没有配对的反引号，按普通文本处理
+			out.WriteString(pc.convertEmbedded(text[:start+n], d))
+			text = text[start+n:]
+			continue
+		}
+
+		out.WriteString(pc.convertEmbedded(text[:start], d))
+		code := text[start+n : start+n+end]
+		core := strings.TrimSpace(code)
+		if isCodePath(core) && pc.shouldConvertItem(core) {
+			i := strings.Index(code, core)
+			code = code[:i] + pc.convertText(core, d, QuoteKeep) + code[i+len(core):]
+		} else {
+			code = pc.convertEmbedded(code, d)
+		}
+		out.WriteString(text[start : start+n])
+		out.WriteString(code)
+		out.WriteString(text[start : start+n])
+		text = text[start+2*n+end:]
+	}
+	out.WriteString(pc.convertEmbedded(text, d))
+	return out.String()
+}
+
+// isCodePath 报告行内代码的内容是否整体是一个路径，即以盘符、UNC或环境变量开头的单行文本
+func isCodePath(code string) bool {
+	loc := embeddedPathPattern.FindStringIndex(strings.TrimPrefix(code, `"`))
+	return loc != nil && loc[0] == 0 && !strings.Contains(code, "\n")
+}
+
+// findBacktickRun 查找长度恰好为n的反引号串
+// 返回值:
+//   - int: 反引号串的起始位置，没有时返回-1
+func findBacktickRun(text string, n int) int {
+	for i := 0; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		run := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+// convertEmbedded 转换文本中出现的各个路径，其余文本保持不变
+// 路径前面不能紧跟ASCII字母或数字，结尾的句读符号不属于路径
+// 参数:
+//   - text: 文本
+//   - d: 输出格式
+//
+// 返回值:
+//   - string: 转换后的文本
+func (pc *PathConverter) convertEmbedded(text string, d Dialect) string {
+	var out strings.Builder
+	last := 0
+	for _, loc := range embeddedPathPattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if start > 0 && isWordByte(text[start-1]) {
+			continue
+		}
+		for end > start && strings.IndexByte(".,;:!?", text[end-1]) >= 0 {
+			end--
+		}
+		path := text[start:end]
+		if !pc.shouldConvertItem(path) {
+			continue
+		}
+		out.WriteString(text[last:start])
+		out.WriteString(pc.convertText(path, d, QuoteNone))
+		last = end
+	}
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+// isWordByte 报告字节是否为ASCII单词字符，中文等紧挨着路径的文字不影响识别
+func isWordByte(c byte) bool {
+	return isASCIILetter(c) || (c >= '0' && c <= '9') || c == '_'
+}
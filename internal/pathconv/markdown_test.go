@@ -0,0 +1,92 @@
+package pathconv
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update 重新生成 testdata/markdown 中的golden文件：go test -run Markdown -update
+var update = flag.Bool("update", false, "update golden files")
+
+func TestConvertTo_MarkdownGolden(t *testing.T) {
+	tests := []struct {
+		name      string
+		dialect   Dialect
+		languages []string
+	}{
+		{"prose", DialectForward, nil},
+		{"prose", DialectWSL, nil},
+		{"fences", DialectForward, nil},
+		{"fences", DialectWSL, []string{"bash", "SH", ""}},
+	}
+	for _, tt := range tests {
+		input, err := os.ReadFile(filepath.Join("testdata", "markdown", tt.name+".md"))
+		if err != nil {
+			t.Fatalf("read input: %v", err)
+		}
+		pc := newTestConverter()
+		pc.SetMarkdown(MarkdownOn, tt.languages)
+		got := pc.ConvertTo(string(input), tt.dialect)
+
+		golden := filepath.Join("testdata", "markdown", tt.name+"."+string(tt.dialect)+".golden")
+		if *update {
+			if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+				t.Fatalf("write golden: %v", err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("read golden: %v", err)
+		}
+		if got != string(want) {
+			t.Errorf("%s (%s) does not match %s:\n%s", tt.name, tt.dialect, golden, got)
+		}
+	}
+}
+
+func TestMarkdownModes(t *testing.T) {
+	pc := newTestConverter()
+	fenced := "See C:\\a\\b.txt\n```bat\ndir C:\\x\n```\n"
+	prose := "See C:\\a\\b.txt for details\nand C:\\c\\d.txt"
+
+	if pc.isMarkdown(fenced) {
+		t.Error("markdown mode should be off by default")
+	}
+
+	pc.SetMarkdown(MarkdownAuto, nil)
+	if !pc.isMarkdown(fenced) || pc.isMarkdown(prose) {
+		t.Error("auto mode should only apply to text with fenced code blocks")
+	}
+	if got := pc.ConvertTo(fenced, DialectForward); got != "See C:/a/b.txt\n```bat\ndir C:\\x\n```\n" {
+		t.Errorf("ConvertTo in auto mode = %q", got)
+	}
+	if !pc.ShouldConvert(fenced) {
+		t.Error("ShouldConvert should accept markdown with paths in prose")
+	}
+	if pc.ShouldConvert("```bat\ndir C:\\x\n```") {
+		t.Error("ShouldConvert should reject markdown whose only paths are in skipped fences")
+	}
+
+	pc.SetMarkdown(MarkdownOn, nil)
+	if got := pc.ConvertTo(prose, DialectForward); got != "See C:/a/b.txt for details\nand C:/c/d.txt" {
+		t.Errorf("ConvertTo in on mode = %q", got)
+	}
+	// 单行的普通路径不受影响
+	if got := pc.ConvertTo(`C:\Program Files\App`, DialectForward); got != `C:/Program Files/App` {
+		t.Errorf("single paths should convert as usual, got %q", got)
+	}
+}
+
+func TestParseMarkdownMode(t *testing.T) {
+	for _, name := range []string{"off", "AUTO", " on ", ""} {
+		if _, err := ParseMarkdownMode(name); err != nil {
+			t.Errorf("ParseMarkdownMode(%q) returned error: %v", name, err)
+		}
+	}
+	if _, err := ParseMarkdownMode("always"); err == nil {
+		t.Error("ParseMarkdownMode(always) should fail")
+	}
+}
@@ -0,0 +1,148 @@
+package pathconv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Namespace Win32路径的命名空间前缀类型
+// 带前缀的路径不经过Win32的路径解析：\\?\C:\a\..\b 中的 .. 是字面名称，正斜杠也不是分隔符
+type Namespace string
+
+const (
+	NamespaceNone   Namespace = ""       // 普通路径
+	NamespaceFile   Namespace = "file"   // Win32文件命名空间 \\?\，用于超过MAX_PATH的长路径
+	NamespaceDevice Namespace = "device" // Win32设备命名空间 \\.\，如 \\.\C:\dir、\\.\pipe\name
+	NamespaceNT     Namespace = "nt"     // NT对象管理器的 \??\，常见于驱动程序和事件日志的输出
+)
+
+// namespacePrefixes 命名空间前缀及其类型，只识别反斜杠形式
+var namespacePrefixes = []struct {
+	prefix string
+	ns     Namespace
+}{
+	{`\\?\`, NamespaceFile},
+	{`\\.\`, NamespaceDevice},
+	{`\??\`, NamespaceNT},
+}
+
+// SplitNamespace 拆分路径开头的命名空间前缀
+// 例如 \\?\C:\dir 拆分为 \\?\ 和 C:\dir；\\?\UNC\server\share 拆分为 \\?\UNC\ 和 \\server\share
+// 参数:
+//   - p: 路径
+//
+// 返回值:
+//   - Namespace: 前缀类型，没有前缀时为NamespaceNone
+//   - string: 前缀原文，包括UNC路径的 UNC\ 部分
+//   - string: 前缀所指向的路径，UNC路径恢复为 \\server\share 形式；设备名（如 pipe\name）原样返回
+func SplitNamespace(p string) (Namespace, string, string) {
+	for _, np := range namespacePrefixes {
+		if !strings.HasPrefix(p, np.prefix) {
+			continue
+		}
+		rest := p[len(np.prefix):]
+		if len(rest) >= 4 && strings.EqualFold(rest[:4], `UNC\`) {
+			return np.ns, p[:len(np.prefix)+4], `\\` + rest[4:]
+		}
+		return np.ns, np.prefix, rest
+	}
+	return NamespaceNone, "", p
+}
+
+// hasNamespacePrefix 报告路径是否以命名空间前缀开头
+func hasNamespacePrefix(p string) bool {
+	ns, _, _ := SplitNamespace(p)
+	return ns != NamespaceNone
+}
+
+// isFilePath 报告去掉前缀后的路径是否为盘符绝对路径或UNC路径
+// \\.\PhysicalDrive0、\\.\pipe\name 等设备名去掉前缀后不再是路径
+func isFilePath(p string) bool {
+	if _, _, ok := splitDrive(p); ok {
+		return true
+	}
+	_, _, ok := splitUNC(p)
+	return ok
+}
+
+// PrefixPolicy 转换带命名空间前缀的路径时的处理方式
+type PrefixPolicy string
+
+const (
+	PrefixStrip     PrefixPolicy = "strip"     // 去掉前缀，按普通路径转换；设备名无法去掉前缀，保持原样
+	PrefixTranslate PrefixPolicy = "translate" // 保留前缀并转换为该格式的写法，\??\ 改写为等价的 \\?\
+	PrefixKeep      PrefixPolicy = "keep"      // 保持原文不变
+)
+
+// defaultPrefixPolicies 各输出格式的默认处理方式
+// escaped 和反斜杠的字符串字面量通常粘贴到Windows程序的源代码中，保留前缀才能访问长路径；其他格式的目标无法理解前缀
+var defaultPrefixPolicies = map[Dialect]PrefixPolicy{
+	DialectForward:       PrefixStrip,
+	DialectWSL:           PrefixStrip,
+	DialectMSYS:          PrefixStrip,
+	DialectEscaped:       PrefixTranslate,
+	DialectOriginal:      PrefixKeep,
+	DialectSMB:           PrefixStrip,
+	DialectFileURI:       PrefixStrip,
+	DialectJSON:          PrefixTranslate,
+	DialectC:             PrefixTranslate,
+	DialectGo:            PrefixTranslate,
+	DialectPython:        PrefixTranslate,
+	DialectCSharp:        PrefixTranslate,
+	DialectStringForward: PrefixStrip,
+}
+
+// canTranslatePrefix 报告该格式能否表示命名空间前缀
+// Win32同样接受正斜杠形式的 //?/ 和 //./，字符串字面量只是对路径的编码；其他格式没有对应的写法
+func (d Dialect) canTranslatePrefix() bool {
+	return d == DialectForward || d == DialectEscaped || d.isLiteral()
+}
+
+// ParsePrefixPolicy 解析前缀处理方式，不区分大小写
+// 参数:
+//   - name: 处理方式名称
+//
+// 返回值:
+//   - PrefixPolicy: 对应的处理方式
+//   - error: 名称无效时返回错误
+func ParsePrefixPolicy(name string) (PrefixPolicy, error) {
+	switch p := PrefixPolicy(strings.ToLower(strings.TrimSpace(name))); p {
+	case PrefixStrip, PrefixTranslate, PrefixKeep:
+		return p, nil
+	}
+	return "", fmt.Errorf("未知的前缀处理方式: %s", name)
+}
+
+// ParsePrefixPolicies 解析各输出格式的前缀处理方式
+// 参数:
+//   - spec: 输出格式名称到处理方式名称的映射，如 {"forward": "translate"}
+//
+// 返回值:
+//   - map[Dialect]PrefixPolicy: 解析后的处理方式
+//   - error: 格式或处理方式无效，或该格式无法表示前缀却配置为translate时返回错误
+func ParsePrefixPolicies(spec map[string]string) (map[Dialect]PrefixPolicy, error) {
+	policies := make(map[Dialect]PrefixPolicy, len(spec))
+	for name, value := range spec {
+		d, err := ParseDialect(name)
+		if err != nil {
+			return nil, err
+		}
+		p, err := ParsePrefixPolicy(value)
+		if err != nil {
+			return nil, err
+		}
+		if p == PrefixTranslate && !d.canTranslatePrefix() {
+			return nil, fmt.Errorf("输出格式 %s 无法表示命名空间前缀", d)
+		}
+		policies[d] = p
+	}
+	return policies, nil
+}
+
+// translatePrefix 将前缀改写为Win32的写法，\??\ 与 \\?\ 指向同一个对象管理器目录
+func translatePrefix(prefix string) string {
+	if strings.HasPrefix(prefix, `\??\`) {
+		return `\\?\` + prefix[len(`\??\`):]
+	}
+	return prefix
+}
@@ -0,0 +1,136 @@
+package pathconv
+
+import "testing"
+
+func TestSplitNamespace(t *testing.T) {
+	tests := []struct {
+		input  string
+		ns     Namespace
+		prefix string
+		inner  string
+	}{
+		{`\\?\C:\very\long\path`, NamespaceFile, `\\?\`, `C:\very\long\path`},
+		{`\\?\UNC\server\share\dir`, NamespaceFile, `\\?\UNC\`, `\\server\share\dir`},
+		{`\\?\unc\server\share`, NamespaceFile, `\\?\unc\`, `\\server\share`},
+		{`\\.\C:\dir`, NamespaceDevice, `\\.\`, `C:\dir`},
+		{`\\.\pipe\name`, NamespaceDevice, `\\.\`, `pipe\name`},
+		{`\??\C:\Windows`, NamespaceNT, `\??\`, `C:\Windows`},
+		{`\??\UNC\nas\proj`, NamespaceNT, `\??\UNC\`, `\\nas\proj`},
+		{`\\server\share`, NamespaceNone, ``, `\\server\share`},
+		{`C:\dir`, NamespaceNone, ``, `C:\dir`},
+		{`//?/C:/dir`, NamespaceNone, ``, `//?/C:/dir`},
+	}
+	for _, tt := range tests {
+		ns, prefix, inner := SplitNamespace(tt.input)
+		if ns != tt.ns || prefix != tt.prefix || inner != tt.inner {
+			t.Errorf("SplitNamespace(%q) = (%q, %q, %q), want (%q, %q, %q)",
+				tt.input, ns, prefix, inner, tt.ns, tt.prefix, tt.inner)
+		}
+	}
+}
+
+func TestShouldConvert_NamespacePrefixes(t *testing.T) {
+	pc := newTestConverter()
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{`\\?\C:\very\long\path`, true},
+		{`\\?\UNC\server\share`, true},
+		{`\??\C:\Windows`, true},
+		// 设备名无法去掉前缀，forward默认保持原样
+		{`\\.\pipe\name`, false},
+		{`\\.\PhysicalDrive0`, false},
+	}
+	for _, tt := range tests {
+		if got := pc.ShouldConvert(tt.input); got != tt.want {
+			t.Errorf("ShouldConvert(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	pc.SetPrefixPolicies(map[Dialect]PrefixPolicy{DialectForward: PrefixKeep})
+	if pc.ShouldConvert(`\\?\C:\very\long\path`) {
+		t.Error("ShouldConvert should be false when the prefix policy keeps the text")
+	}
+}
+
+func TestConvertTo_NamespaceDefaults(t *testing.T) {
+	pc := newTestConverter()
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`\\?\C:\very\long\path`, DialectForward, `C:/very/long/path`},
+		{`\\?\C:\very\long\path`, DialectWSL, `/mnt/c/very/long/path`},
+		{`\\?\C:\very\long\path`, DialectMSYS, `/c/very/long/path`},
+		{`\\?\C:\very\long\path`, DialectEscaped, `"\\\\?\\C:\\very\\long\\path"`},
+		{`\\?\C:\very\long\path`, DialectFileURI, `file:///C:/very/long/path`},
+		{`\\?\UNC\server\share\dir`, DialectForward, `//server/share/dir`},
+		{`\\?\UNC\server\share\dir`, DialectSMB, `smb://server/share/dir`},
+		{`"\\.\C:\Program Files"`, DialectForward, `"C:/Program Files"`},
+		{`\??\C:\Windows`, DialectWSL, `/mnt/c/Windows`},
+		{`\??\C:\Windows`, DialectEscaped, `"\\\\?\\C:\\Windows"`},
+		{`\??\UNC\nas\proj`, DialectEscaped, `"\\\\?\\UNC\\nas\\proj"`},
+		// 前缀关闭了路径解析，.. 是字面名称
+		{`\\?\C:\a\..\b`, DialectForward, `C:/a/../b`},
+		// 设备名没有对应的路径
+		{`\\.\pipe\name`, DialectForward, `\\.\pipe\name`},
+		{`\\.\pipe\name`, DialectEscaped, `"\\\\.\\pipe\\name"`},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestConvertTo_NamespacePolicies(t *testing.T) {
+	pc := newTestConverter()
+	pc.SetNormalization(true, TrailingKeep)
+	pc.SetPrefixPolicies(map[Dialect]PrefixPolicy{
+		DialectForward: PrefixTranslate,
+		DialectWSL:     PrefixKeep,
+		DialectEscaped: PrefixStrip,
+	})
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`\\?\C:\very\long\path`, DialectForward, `//?/C:/very/long/path`},
+		{`\??\C:\Windows`, DialectForward, `//?/C:/Windows`},
+		{`\\.\pipe\name`, DialectForward, `//./pipe/name`},
+		{`\\?\C:\very\long\path`, DialectWSL, `\\?\C:\very\long\path`},
+		{`\\?\C:\a\..\b`, DialectEscaped, `"C:\\a\\..\\b"`},
+		// 不带前缀的路径照常规范化
+		{`C:\a\..\b`, DialectEscaped, `"C:\\b"`},
+		// 未配置的格式使用默认处理方式
+		{`\\?\C:\very\long\path`, DialectMSYS, `/c/very/long/path`},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestParsePrefixPolicies(t *testing.T) {
+	got, err := ParsePrefixPolicies(map[string]string{"Forward": "TRANSLATE", "wsl": "keep"})
+	if err != nil {
+		t.Fatalf("ParsePrefixPolicies returned error: %v", err)
+	}
+	if got[DialectForward] != PrefixTranslate || got[DialectWSL] != PrefixKeep {
+		t.Errorf("ParsePrefixPolicies = %v", got)
+	}
+
+	for _, spec := range []map[string]string{
+		{"forward": "drop"},
+		{"unix": "strip"},
+		{"wsl": "translate"},
+	} {
+		if _, err := ParsePrefixPolicies(spec); err == nil {
+			t.Errorf("ParsePrefixPolicies(%v) should fail", spec)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package pathconv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TrailingPolicy 规范化路径时对结尾分隔符的处理方式
+type TrailingPolicy string
+
+const (
+	TrailingKeep  TrailingPolicy = "keep"  // 原路径以分隔符结尾时保留一个分隔符
+	TrailingStrip TrailingPolicy = "strip" // 去掉结尾的分隔符
+	TrailingAdd   TrailingPolicy = "add"   // 总是以分隔符结尾，适用于只复制目录的场景
+)
+
+// ParseTrailingPolicy 解析结尾分隔符处理方式，不区分大小写，空字符串视为keep
+// 参数:
+//   - name: 处理方式名称
+//
+// 返回值:
+//   - TrailingPolicy: 对应的处理方式
+//   - error: 名称无效时返回错误
+func ParseTrailingPolicy(name string) (TrailingPolicy, error) {
+	switch p := TrailingPolicy(strings.ToLower(strings.TrimSpace(name))); p {
+	case "":
+		return TrailingKeep, nil
+	case TrailingKeep, TrailingStrip, TrailingAdd:
+		return p, nil
+	}
+	return "", fmt.Errorf("未知的结尾分隔符处理方式: %s", name)
+}
+
+// Normalize 按Windows语义对路径进行词法规范化
+// 去掉 . 和重复的分隔符，并消解 ..；规范化只处理文本，不访问文件系统
+// 绝对路径不会越过根目录：驱动器根目录（C:\）、UNC共享根目录（\\server\share）
+// 以及开头的环境变量引用（%USERPROFILE%）都视为根，其上的 .. 被丢弃；
+// 相对路径开头无法消解的 .. 予以保留；带命名空间前缀（\\?\ 等）的路径不经过Win32的路径解析，原样返回
+// 参数:
+//   - p: Windows路径，正反斜杠均可
+//   - trailing: 结尾分隔符的处理方式
+//
+// 返回值:
+//   - string: 使用反斜杠分隔的规范化路径
+func Normalize(p string, trailing TrailingPolicy) string {
+	if p == "" || hasNamespacePrefix(p) {
+		return p
+	}
+	root, rest, absolute := splitRoot(p)
+	hadTrailing := rest != "" && isSeparator(rest[len(rest)-1])
+
+	var segments []string
+	for _, seg := range strings.FieldsFunc(rest, func(r rune) bool { return r == '\\' || r == '/' }) {
+		switch {
+		case seg == ".":
+		case seg == "..":
+			if len(segments) > 0 && segments[len(segments)-1] != ".." {
+				segments = segments[:len(segments)-1]
+			} else if !absolute {
+				segments = append(segments, "..")
+			}
+		default:
+			segments = append(segments, seg)
+		}
+	}
+
+	joined := strings.Join(segments, `\`)
+	// 相对路径的第一段形如 C: 或 %VAR% 时加上 .\，避免规范化后被当作根
+	if root == "" && joined != "" {
+		if r, _, _ := splitRoot(joined); r != "" {
+			joined = `.\` + joined
+		}
+	}
+	result := root
+	switch {
+	case joined == "" && root == "":
+		return "."
+	case joined == "":
+	case root == "" || strings.HasSuffix(root, `\`) || isDriveRelative(root):
+		// C:foo 是驱动器相对路径，盘符后不能插入分隔符
+		result += joined
+	default:
+		result += `\` + joined
+	}
+
+	// 驱动器根目录等以分隔符结尾的根本身保持不变，单独的 C: 加上分隔符会改变含义
+	if !strings.HasSuffix(result, `\`) && !isDriveRelative(result) && (trailing == TrailingAdd || (trailing == TrailingKeep && hadTrailing)) {
+		result += `\`
+	}
+	return result
+}
+
+// splitRoot 拆分路径的根部分
+// 返回值:
+//   - string: 根，如 C:\、C:、\、\\server\share 或 %USERPROFILE%，相对路径为空
+//   - string: 根之后的部分
+//   - bool: 根之上是否不允许 ..（即路径是否为绝对路径）
+func splitRoot(p string) (string, string, bool) {
+	// UNC路径：\\server\share 是不能越过的根
+	if server, rest, ok := splitUNC(p); ok {
+		rest = strings.TrimLeft(rest, `\/`)
+		share, after := rest, ""
+		if i := strings.IndexAny(rest, `\/`); i >= 0 {
+			share, after = rest[:i], rest[i:]
+		}
+		if share == "" {
+			return `\\` + server, after, true
+		}
+		return `\\` + server + `\` + share, after, true
+	}
+
+	// 盘符路径：C:\ 为绝对路径，C:foo 相对于驱动器的当前目录
+	if len(p) >= 2 && p[1] == ':' && isASCIILetter(p[0]) {
+		if len(p) > 2 && isSeparator(p[2]) {
+			return p[:2] + `\`, p[3:], true
+		}
+		return p[:2], p[2:], false
+	}
+
+	// 以分隔符开头：当前驱动器的根目录
+	if isSeparator(p[0]) {
+		return `\`, p[1:], true
+	}
+
+	// 以环境变量开头：变量的值未知，不能用 .. 越过
+	if loc := envVarPattern.FindStringIndex(p); loc != nil && loc[0] == 0 &&
+		!strings.ContainsAny(p[:loc[1]], `\/`) && (loc[1] == len(p) || isSeparator(p[loc[1]])) {
+		return p[:loc[1]], p[loc[1]:], true
+	}
+	return "", p, false
+}
+
+// isDriveRelative 判断根是否为不带分隔符的盘符（如 C:）
+func isDriveRelative(root string) bool {
+	return len(root) == 2 && root[1] == ':' && isASCIILetter(root[0])
+}
@@ -0,0 +1,128 @@
+package pathconv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input    string
+		trailing TrailingPolicy
+		want     string
+	}{
+		{`C:\a\b\..\c\\d\.\e\`, TrailingKeep, `C:\a\c\d\e\`},
+		{`C:\a\b\..\c\\d\.\e\`, TrailingStrip, `C:\a\c\d\e`},
+		{`C:\a\b`, TrailingAdd, `C:\a\b\`},
+		{`C:/a//b/./c`, TrailingKeep, `C:\a\b\c`},
+		{`C:\a\..\..\..\b`, TrailingKeep, `C:\b`},
+		{`C:\..`, TrailingKeep, `C:\`},
+		{`C:\`, TrailingStrip, `C:\`},
+		{`C:a\..\..\b`, TrailingKeep, `C:..\b`},
+		{`\a\..\..\b`, TrailingKeep, `\b`},
+		{`\\server\share\a\..\..\b`, TrailingKeep, `\\server\share\b`},
+		{`\\server\share\..`, TrailingKeep, `\\server\share`},
+		{`\\server\share\`, TrailingKeep, `\\server\share\`},
+		{`\\server\share`, TrailingAdd, `\\server\share\`},
+		{`//server//share/a`, TrailingKeep, `\\server\share\a`},
+		{`%USERPROFILE%\..\.ssh`, TrailingKeep, `%USERPROFILE%\.ssh`},
+		{`%USERPROFILE%\a\..\`, TrailingKeep, `%USERPROFILE%\`},
+		{`a\b\..\..\..\c`, TrailingKeep, `..\c`},
+		{`.\a\.`, TrailingKeep, `a`},
+		{`a\..`, TrailingKeep, `.`},
+		{`.\C:x`, TrailingKeep, `.\C:x`},
+		{``, TrailingKeep, ``},
+		{`\\?\C:\a\..\b`, TrailingStrip, `\\?\C:\a\..\b`},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.input, tt.trailing); got != tt.want {
+			t.Errorf("Normalize(%q, %s) = %q, want %q", tt.input, tt.trailing, got, tt.want)
+		}
+	}
+}
+
+func TestConvertTo_Normalization(t *testing.T) {
+	pc := newTestConverter()
+	if got := pc.ConvertTo(`C:\a\..\b`, DialectForward); got != `C:/a/../b` {
+		t.Errorf("normalization should be off by default, got %q", got)
+	}
+
+	pc.SetNormalization(true, TrailingStrip)
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`C:\a\b\..\c\\d\.\e\`, DialectForward, `C:/a/c/d/e`},
+		{`"C:\Program Files\..\Tools\"`, DialectWSL, `"/mnt/c/Tools"`},
+		{`\\server\share\..\..\x`, DialectForward, `//server/share/x`},
+		// 多行文本逐行规范化
+		{"C:\\a\\..\\b\nC:\\c\\.", DialectForward, "C:/b\nC:/c"},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestParseTrailingPolicy(t *testing.T) {
+	for _, name := range []string{"keep", "STRIP", " add ", ""} {
+		if _, err := ParseTrailingPolicy(name); err != nil {
+			t.Errorf("ParseTrailingPolicy(%q) returned error: %v", name, err)
+		}
+	}
+	if _, err := ParseTrailingPolicy("remove"); err == nil {
+		t.Error("ParseTrailingPolicy(remove) should fail")
+	}
+}
+
+func FuzzNormalize(f *testing.F) {
+	for _, seed := range []string{
+		`C:\a\b\..\c`, `C:\..\..`, `\\server\share\..\x`, `%TEMP%\..\y`,
+		`a\..\..\b`, `\.\..\`, `C:`, `//s/s/./..//`,
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, p string) {
+		if strings.ContainsAny(p, "\x00") {
+			return
+		}
+		for _, trailing := range []TrailingPolicy{TrailingKeep, TrailingStrip, TrailingAdd} {
+			got := Normalize(p, trailing)
+			if again := Normalize(got, trailing); again != got {
+				t.Fatalf("Normalize is not idempotent: %q -> %q -> %q", p, got, again)
+			}
+			if p == "" {
+				continue
+			}
+			if hasNamespacePrefix(p) {
+				if got != p {
+					t.Fatalf("Normalize(%q) = %q, prefixed paths must be left untouched", p, got)
+				}
+				continue
+			}
+
+			root, _, absolute := splitRoot(p)
+			gotRoot, gotRest, _ := splitRoot(got)
+			if absolute && gotRoot != normalizeRoot(root) {
+				t.Fatalf("Normalize(%q) changed root %q to %q", p, root, gotRoot)
+			}
+			for i, seg := range strings.Split(strings.TrimSuffix(gotRest, `\`), `\`) {
+				switch {
+				case seg == "." && got != "." && !(i == 0 && root == ""):
+					t.Fatalf("Normalize(%q) = %q contains a . segment", p, got)
+				case seg == ".." && absolute:
+					t.Fatalf("Normalize(%q) = %q walks above the root", p, got)
+				case seg == "" && gotRest != "" && !(i == 0 && isSeparator(gotRest[0])):
+					t.Fatalf("Normalize(%q) = %q contains an empty segment", p, got)
+				}
+			}
+		}
+	})
+}
+
+// normalizeRoot 返回根在规范化后的写法
+func normalizeRoot(root string) string {
+	return strings.ReplaceAll(root, "/", `\`)
+}
@@ -15,9 +15,34 @@ var envVarPattern = regexp.MustCompile(`%[^%]+%`)
 // PathConverter 处理路径检测和转换的核心结构体
 // 该结构体封装了路径转换的逻辑，包括路径检测规则和排除模式
 type PathConverter struct {
-	excludePatterns []string         // 用户配置的排除模式列表，支持通配符
-	excludeRegexps  []*regexp.Regexp // 编译后的排除模式正则表达式，用于高效匹配
-	logger          *logger.Logger   // 日志记录器，用于输出转换过程中的信息
+	excludePatterns []string                 // 用户配置的排除模式列表，支持通配符
+	excludeRegexps  []*regexp.Regexp         // 编译后的排除模式正则表达式，用于高效匹配
+	logger          *logger.Logger           // 日志记录器，用于输出转换过程中的信息
+	dialect         Dialect                  // 默认输出格式，Convert使用该格式
+	uncMode         UNCMode                  // 映射驱动器与UNC路径的转换方向
+	resolver        Resolver                 // 驱动器映射查询，uncMode为none时不使用
+	mapper          *Mapper                  // 用户定义的路径前缀映射，为nil时不映射
+	envMode         EnvMode                  // %VAR% 形式环境变量的处理方式
+	contractMode    ContractMode             // 将路径前缀收缩为环境变量的方式
+	env             Env                      // 环境变量查询，测试时可替换
+	quotePolicy     QuotePolicy              // 转换结果的加引号策略
+	shell           Shell                    // 加引号时遵循的目标shell规则
+	normalize       bool                     // 是否对路径进行词法规范化
+	trailing        TrailingPolicy           // 规范化时对结尾分隔符的处理方式
+	prefixPolicies  map[Dialect]PrefixPolicy // 各输出格式对命名空间前缀的处理方式，未列出的格式使用默认值
+	classPolicies   map[Class]ClassPolicy    // 各类文本的转换策略，未列出的类别使用默认值
+	threshold       float64                  // 路径识别得分的阈值，低于该值的文本不转换
+	splitLists      bool                     // 是否将分号列表和多行文本拆分后逐个转换
+	dropNonPaths    bool                     // 拆分列表时是否去掉不是路径的元素
+	markdown        MarkdownMode             // Markdown感知转换的启用方式
+	fenceLanguages  map[string]bool          // 需要转换其中路径的代码块语言
+	structured      bool                     // 是否按结构转换JSON、YAML和INI片段中的字符串值
+	driveCase       DriveCase                // 盘符的大小写
+	caseResolver    CaseResolver             // 按文件系统还原路径的大小写，为nil时不还原
+	existMode       ExistMode                // 转换前检查路径是否存在的方式
+	existChecker    ExistenceChecker         // 路径存在性查询，existMode为off时不使用
+	allowedRoots    []string                 // 无需检查存在性即可转换的根目录
+	baseDir         string                   // 解析相对路径的基准目录，为空时不解析
 }
 
 // NewPathConverter 创建新的路径转换器实例
@@ -31,8 +56,22 @@ type PathConverter struct {
 func NewPathConverter(excludePatterns []string, l *logger.Logger) *PathConverter {
 	// 创建PathConverter实例
 	pc := &PathConverter{
-		excludePatterns: excludePatterns, // 存储用户配置的排除模式
-		logger:          l,               // 存储日志记录器
+		excludePatterns: excludePatterns,  // 存储用户配置的排除模式
+		logger:          l,                // 存储日志记录器
+		dialect:         DialectForward,   // 默认输出正斜杠格式
+		uncMode:         UNCModeNone,      // 默认不做UNC转换
+		envMode:         EnvKeep,          // 默认保持环境变量不变
+		contractMode:    ContractNone,     // 默认不收缩路径
+		env:             osEnv,            // 默认查询当前进程的环境变量
+		quotePolicy:     QuoteKeep,        // 默认保留原文本的引号
+		shell:           ShellPOSIX,       // 默认按POSIX shell规则加引号
+		trailing:        TrailingKeep,     // 规范化时默认保留结尾分隔符
+		threshold:       DefaultThreshold, // 默认的路径识别阈值
+		splitLists:      true,             // 默认逐个转换列表中的路径
+		markdown:        MarkdownOff,      // 默认不按Markdown处理
+		structured:      true,             // 默认按结构转换JSON、YAML和INI片段
+		driveCase:       DriveCaseKeep,    // 默认保持盘符的大小写
+		existMode:       ExistOff,         // 默认不检查路径是否存在
 	}
 	// 预编译排除模式，提高后续匹配效率
 	pc.compileExcludePatterns()
@@ -68,20 +107,43 @@ func (pc *PathConverter) compileExcludePatterns() {
 
 // ShouldConvert 判断是否应该转换给定的文本
 // 该函数通过一系列规则判断文本是否包含需要转换的Windows路径
-// 包括检查反斜杠、驱动器字母格式、网络路径等，并考虑排除模式
+// 依次检查反斜杠、排除模式和文本类别，再按路径识别得分（见Score）与阈值比较，最后按配置检查路径是否存在；
+// 启用列表拆分时，路径列表（见isList）中有需要转换的路径即需要转换；Markdown文本中有需要转换的路径即需要转换
 // 参数:
 //   - text: 要检查的文本
 //
 // 返回值:
 //   - bool: 如果文本包含需要转换的Windows路径，返回true，否则返回false
 func (pc *PathConverter) ShouldConvert(text string) bool {
+	// Markdown文本中只要有需要转换的路径即需要转换
+	if pc.isMarkdown(text) {
+		return pc.convertMarkdown(text, pc.dialect) != text
+	}
+	// JSON、YAML和INI片段中只要有需要转换的字符串值即需要转换
+	if converted, ok := pc.convertStructured(text, pc.dialect); ok {
+		return converted != text
+	}
+	// 路径列表（PATH形式的分号列表或每行一个路径的文本）逐个元素转换
+	if pc.splitLists && pc.isList(text) {
+		return true
+	}
+	return pc.shouldConvertItem(text)
+}
+
+// shouldConvertItem 判断单个路径（不按列表拆分）是否应该转换
+// 参数:
+//   - text: 要检查的文本
+//
+// 返回值:
+//   - bool: 如果文本是需要转换的Windows路径，返回true，否则返回false
+func (pc *PathConverter) shouldConvertItem(text string) bool {
 	// 空文本不需要转换
 	if text == "" {
 		return false
 	}
 
-	// 去除文本两端的引号，Windows路径常被引号包围
-	trimmed := strings.Trim(text, "\"")
+	// 去除文本两端的引号，Windows路径常被引号包围；编译器输出中的位置后缀（如 :12:3）不参与判断
+	trimmed, _ := splitLocation(strings.Trim(text, "\""))
 
 	// 如果不包含反斜杠，则不可能是Windows路径，无需转换
 	if !strings.Contains(trimmed, "\\") {
@@ -93,26 +155,34 @@ func (pc *PathConverter) ShouldConvert(text string) bool {
 		return false
 	}
 
-	// 检查是否为绝对路径格式 (如 C:\ 或 C:/)
-	// 前3个字符应为 驱动器字母 + 冒号 + 路径分隔符
-	if len(trimmed) >= 3 {
-		if trimmed[1] == ':' && (trimmed[2] == '\\' || trimmed[2] == '/') {
-			return true
-		}
+	// 注册表键、账户名等不是文件路径的文本按类别的策略决定是否转换
+	switch class := Classify(trimmed); {
+	case pc.classPolicy(class) == ClassSkip:
+		pc.logger.Debug("跳过%s类别的文本: %s", class, trimmed)
+		return false
+	case class != ClassFile && class != ClassUnknown:
+		// 已明确识别为注册表键等类别且配置为转换时，不再按文件路径打分
+		return true
 	}
 
-	// 检查是否为UNC路径格式 (网络路径，以 \\ 开头)
-	if strings.HasPrefix(trimmed, "\\\\") {
-		return true
+	// 带长路径或设备命名空间前缀的路径，按当前格式保持原样时无需转换
+	if ns, _, inner := SplitNamespace(trimmed); ns != NamespaceNone {
+		return pc.prefixPolicy(pc.dialect, inner) != PrefixKeep
 	}
 
-	// 如果包含反斜杠，则视为需要转换的路径
-	if strings.Contains(trimmed, "\\") {
-		return true
+	// 按盘符、段数、扩展名、转义序列和自然语言比例等特征打分，达到阈值才视为路径
+	score := Score(trimmed)
+	if score < pc.threshold {
+		pc.logger.Debug("路径识别得分 %.2f 低于阈值 %.2f: %s", score, pc.threshold, trimmed)
+		return false
 	}
 
-	// 不满足任何路径特征，不需要转换
-	return false
+	// 按配置确认路径在文件系统中存在，查询较慢，放在最后
+	if !pc.pathExists(trimmed) {
+		pc.logger.Debug("路径不存在且不在允许的根目录下: %s", trimmed)
+		return false
+	}
+	return true
 }
 
 // isExcluded 检查文本是否匹配任何排除模式
@@ -153,14 +223,53 @@ func (pc *PathConverter) isExcluded(text string) bool {
 }
 
 // Convert 将Windows路径转换为Unix风格路径
-// 该函数将文本中的反斜杠(\)替换为正斜杠(/)，保持原有的引号格式
-// 注意: 该函数不会验证文本是否为有效路径，仅执行字符替换
+// 该函数使用默认输出格式转换文本，保持原有的引号格式
+// 注意: 该函数不会验证文本是否为有效路径，仅执行格式转换
 // 参数:
 //   - text: 要转换的文本
 //
 // 返回值:
 //   - string: 转换后的文本，如果不需要转换则返回原文
 func (pc *PathConverter) Convert(text string) string {
+	return pc.ConvertTo(text, pc.dialect)
+}
+
+// ConvertTo 将Windows路径转换为指定输出格式
+// 除自带引号的格式外，原文本两端的引号会被保留
+// 参数:
+//   - text: 要转换的文本
+//   - d: 输出格式
+//
+// 返回值:
+//   - string: 转换后的文本，如果不需要转换则返回原文
+func (pc *PathConverter) ConvertTo(text string, d Dialect) string {
+	// 保持原样的格式直接返回原文
+	if d == DialectOriginal {
+		return text
+	}
+	if pc.isMarkdown(text) {
+		return pc.convertMarkdown(text, d)
+	}
+	if converted, ok := pc.convertStructured(text, d); ok {
+		return converted
+	}
+	if pc.splitLists {
+		if converted, ok := pc.convertList(text, d); ok {
+			return converted
+		}
+	}
+	return pc.convertText(text, d, pc.quotePolicy)
+}
+
+// convertText 将单个路径转换为指定输出格式
+// 参数:
+//   - text: 要转换的文本
+//   - d: 输出格式
+//   - policy: 加引号策略
+//
+// 返回值:
+//   - string: 转换后的文本，如果不需要转换则返回原文
+func (pc *PathConverter) convertText(text string, d Dialect, policy QuotePolicy) string {
 	// 检查并记录文本是否被引号包围
 	hasQuotes := strings.HasPrefix(text, `"`) && strings.HasSuffix(text, `"`)
 	// 移除文本两端的引号，只处理内容部分
@@ -168,24 +277,271 @@ func (pc *PathConverter) Convert(text string) string {
 
 	// 保存原始内容，用于比较是否发生了变化
 	originalContent := content
-	// 将所有反斜杠替换为正斜杠
-	converted := strings.ReplaceAll(content, "\\", "/")
+	// 位置后缀（如 :12:3、(12,3)）原样保留，便于编辑器跳转
+	content, suffix := splitLocation(content)
+	var converted string
+	if ns, prefix, inner := SplitNamespace(content); ns == NamespaceNone {
+		converted = pc.convertPath(content, suffix, d, pc.normalize)
+	} else {
+		switch pc.prefixPolicy(d, inner) {
+		case PrefixStrip:
+			// 前缀关闭了Win32的路径解析，其中的 . 和 .. 是字面名称，不做规范化
+			converted = pc.convertPath(inner, suffix, d, false)
+		case PrefixTranslate:
+			// 带前缀的路径不展开环境变量也不做映射，只改写前缀和分隔符
+			converted = formatLocation(translatePrefix(prefix)+content[len(prefix):], suffix, d)
+		default:
+			return text
+		}
+	}
 
 	// 如果没有变化，直接返回原文
 	if converted == originalContent {
 		return text
 	}
 
-	// 如果原文本有引号，为转换后的内容添加引号
-	if hasQuotes {
-		converted = `"` + converted + `"`
+	// 按加引号策略处理，自带引号的格式不再加引号
+	if !d.quotesOwnOutput() {
+		keepVars := pc.envMode == EnvPOSIX || pc.envMode == EnvPOSIXBraced
+		converted = shellQuote(converted, hasQuotes, policy, pc.shell, keepVars)
 	}
 
 	// 记录转换过程（调试级别）
-	pc.logger.Debug("路径转换: %s -> %s", originalContent, converted)
+	pc.logger.Debug("路径转换(%s): %s -> %s", d, originalContent, converted)
+	return converted
+}
+
+// convertPath 将不含外层引号和命名空间前缀的路径转换为指定输出格式
+// 参数:
+//   - content: 路径内容
+//   - suffix: 路径之后的位置后缀，如 :12:3，没有时为空
+//   - d: 输出格式
+//   - normalize: 是否规范化路径
+//
+// 返回值:
+//   - string: 转换后的路径，不含外层引号
+func (pc *PathConverter) convertPath(content, suffix string, d Dialect, normalize bool) string {
+	// 展开环境变量，展开后的路径参与后续的全部转换
+	if pc.envMode == EnvExpand {
+		content = expandEnv(content, pc.env)
+	}
+	// 相对路径拼接到基准目录之后，再按绝对路径转换
+	content = pc.resolveRelative(content)
+	// 消解 . 和 ..，多行文本不是单个路径，不做规范化
+	if normalize && !strings.ContainsAny(content, "\r\n") {
+		content = Normalize(content, pc.trailing)
+	}
+	// 按配置在映射驱动器与UNC路径之间转换
+	content = pc.resolveUNC(content)
+	// 按文件系统还原路径的大小写，再按配置改写盘符的大小写
+	if pc.caseResolver != nil && !strings.ContainsAny(content, "\r\n") {
+		content = pc.caseResolver.ResolveCase(content)
+	}
+	content = applyDriveCase(content, pc.driveCase)
+	// 按用户定义的前缀映射替换为目标路径，映射后的路径仍按输出格式处理；
+	// 没有规则匹配时按配置将路径前缀收缩为环境变量，再转换环境变量的语法
+	mapped := false
+	if pc.mapper != nil {
+		var target string
+		if target, mapped = pc.mapper.ToTarget(content); mapped {
+			content = target
+		}
+	}
+	if !mapped {
+		content = contractPath(content, pc.contractMode, pc.env)
+		content = translateEnv(content, pc.envMode)
+	}
+	// 按输出格式转换路径内容
+	return formatLocation(content, suffix, d)
+}
+
+// prefixPolicy 返回指定格式下带命名空间前缀的路径的处理方式
+// 参数:
+//   - d: 输出格式
+//   - inner: 去掉前缀后的路径，设备名无法去掉前缀，按保持原样处理
+//
+// 返回值:
+//   - PrefixPolicy: 处理方式
+func (pc *PathConverter) prefixPolicy(d Dialect, inner string) PrefixPolicy {
+	p, ok := pc.prefixPolicies[d]
+	if !ok {
+		p = defaultPrefixPolicies[d]
+	}
+	switch {
+	case p == PrefixStrip && !isFilePath(inner):
+		return PrefixKeep
+	case p == PrefixTranslate && !d.canTranslatePrefix():
+		return PrefixKeep
+	case p == "":
+		return PrefixKeep
+	}
+	return p
+}
+
+// classPolicy 返回指定类别的转换策略，未配置的类别使用默认策略
+func (pc *PathConverter) classPolicy(c Class) ClassPolicy {
+	if p, ok := pc.classPolicies[c]; ok {
+		return p
+	}
+	return defaultClassPolicies[c]
+}
+
+// SetDialect 设置默认输出格式
+// 参数:
+//   - d: 新的默认输出格式
+func (pc *PathConverter) SetDialect(d Dialect) {
+	pc.dialect = d
+}
+
+// Dialect 返回默认输出格式
+func (pc *PathConverter) Dialect() Dialect {
+	return pc.dialect
+}
+
+// SetUNCResolution 设置映射驱动器与UNC路径的转换方式
+// 参数:
+//   - mode: 转换方向
+//   - r: 驱动器映射查询，mode为none时可以为nil
+func (pc *PathConverter) SetUNCResolution(mode UNCMode, r Resolver) {
+	if r == nil {
+		mode = UNCModeNone
+	}
+	pc.uncMode = mode
+	pc.resolver = r
+}
+
+// SetMappings 设置路径前缀映射
+// 参数:
+//   - m: 映射规则，为nil时不映射
+func (pc *PathConverter) SetMappings(m *Mapper) {
+	pc.mapper = m
+}
+
+// SetEnvOptions 设置环境变量的展开、语法转换与路径收缩
+// 参数:
+//   - mode: %VAR% 形式环境变量的处理方式
+//   - contract: 将路径前缀收缩为环境变量的方式
+//   - env: 环境变量查询，为nil时查询当前进程的环境变量
+func (pc *PathConverter) SetEnvOptions(mode EnvMode, contract ContractMode, env Env) {
+	if env == nil {
+		env = osEnv
+	}
+	pc.envMode = mode
+	pc.contractMode = contract
+	pc.env = env
+}
+
+// SetQuoting 设置转换结果的加引号策略
+// 参数:
+//   - policy: 加引号策略
+//   - sh: 目标shell
+func (pc *PathConverter) SetQuoting(policy QuotePolicy, sh Shell) {
+	pc.quotePolicy = policy
+	pc.shell = sh
+}
+
+// SetNormalization 设置路径的词法规范化
+// 参数:
+//   - enabled: 是否在转换前规范化路径
+//   - trailing: 结尾分隔符的处理方式
+func (pc *PathConverter) SetNormalization(enabled bool, trailing TrailingPolicy) {
+	pc.normalize = enabled
+	pc.trailing = trailing
+}
+
+// SetPrefixPolicies 设置各输出格式对带命名空间前缀（\\?\、\\.\、\??\）的路径的处理方式
+// 参数:
+//   - policies: 输出格式到处理方式的映射，未列出的格式使用默认处理方式
+func (pc *PathConverter) SetPrefixPolicies(policies map[Dialect]PrefixPolicy) {
+	pc.prefixPolicies = make(map[Dialect]PrefixPolicy, len(policies))
+	for d, p := range policies {
+		pc.prefixPolicies[d] = p
+	}
+}
+
+// SetClassPolicies 设置各类文本（文件路径、注册表键、账户名等）的转换策略
+// 参数:
+//   - policies: 类别到策略的映射，未列出的类别使用默认策略
+func (pc *PathConverter) SetClassPolicies(policies map[Class]ClassPolicy) {
+	pc.classPolicies = make(map[Class]ClassPolicy, len(policies))
+	for c, p := range policies {
+		pc.classPolicies[c] = p
+	}
+}
+
+// SetThreshold 设置路径识别得分的阈值
+// 参数:
+//   - threshold: 0到1之间的阈值，越高越严格
+func (pc *PathConverter) SetThreshold(threshold float64) {
+	pc.threshold = threshold
+}
+
+// SetListOptions 设置路径列表的转换方式
+// 参数:
+//   - split: 是否将分号列表和多行文本拆分后逐个转换
+//   - dropNonPaths: 拆分列表时是否去掉不是路径的元素
+func (pc *PathConverter) SetListOptions(split, dropNonPaths bool) {
+	pc.splitLists = split
+	pc.dropNonPaths = dropNonPaths
+}
+
+// SetMarkdown 设置Markdown感知转换
+// 参数:
+//   - mode: 启用方式
+//   - fenceLanguages: 需要转换其中路径的代码块语言（不区分大小写），空字符串表示没有声明语言的代码块
+func (pc *PathConverter) SetMarkdown(mode MarkdownMode, fenceLanguages []string) {
+	pc.markdown = mode
+	pc.fenceLanguages = make(map[string]bool, len(fenceLanguages))
+	for _, lang := range fenceLanguages {
+		pc.fenceLanguages[strings.ToLower(strings.TrimSpace(lang))] = true
+	}
+}
+
+// ConvertReverse 将目标路径转换回Windows路径，是Convert的逆操作
+// smb://和file:// URI按ParseURI解析，其他文本依次尝试用户定义的前缀映射和WSL挂载路径（/mnt/c/...），都不匹配时返回原文
+// 原文本两端的引号会被保留
+// 参数:
+//   - text: 要转换的文本
+//
+// 返回值:
+//   - string: Windows路径，如果无法转换则返回原文
+func (pc *PathConverter) ConvertReverse(text string) string {
+	hasQuotes := len(text) >= 2 && strings.HasPrefix(text, `"`) && strings.HasSuffix(text, `"`)
+	content := strings.Trim(text, `"`)
+
+	converted, ok := "", false
+	if p, err := ParseURI(content); err == nil {
+		converted, ok = p, true
+	}
+	if !ok && pc.mapper != nil {
+		converted, ok = pc.mapper.ToWindows(content)
+	}
+	if !ok {
+		converted, ok = wslToWindows(content)
+	}
+	if !ok {
+		return text
+	}
+
+	if hasQuotes {
+		converted = `"` + converted + `"`
+	}
+	pc.logger.Debug("路径反向转换: %s -> %s", content, converted)
 	return converted
 }
 
+// resolveUNC 按转换方向在映射驱动器路径与UNC路径之间转换
+func (pc *PathConverter) resolveUNC(content string) string {
+	switch pc.uncMode {
+	case UNCModeToUNC:
+		return driveToUNC(content, pc.resolver)
+	case UNCModeToDrive:
+		return uncToDrive(content, pc.resolver)
+	default:
+		return content
+	}
+}
+
 // UpdateExcludePatterns 更新排除模式
 // 该函数允许运行时更新排除模式，常用于配置热更新
 // 参数:
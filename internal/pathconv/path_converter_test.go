@@ -60,3 +60,49 @@ func TestConvert_PreservesQuotes(t *testing.T) {
 		t.Fatalf("expected quotes preserved, got %q", out)
 	}
 }
+
+func TestConvertTo_Dialects(t *testing.T) {
+	pc := newTestConverter()
+	tests := []struct {
+		name     string
+		input    string
+		dialect  Dialect
+		expected string
+	}{
+		{"Forward", `C:\Users\me`, DialectForward, `C:/Users/me`},
+		{"WSL", `C:\Users\me`, DialectWSL, `/mnt/c/Users/me`},
+		{"WSL drive root", `D:\`, DialectWSL, `/mnt/d/`},
+		{"WSL quoted", `"C:\Program Files\App"`, DialectWSL, `"/mnt/c/Program Files/App"`},
+		{"WSL UNC falls back to forward", `\\server\share\x`, DialectWSL, `//server/share/x`},
+		{"MSYS", `E:\src\repo`, DialectMSYS, `/e/src/repo`},
+		{"MSYS drive relative falls back", `C:foo\bar`, DialectMSYS, `C:foo/bar`},
+		{"Escaped", `C:\Users\me`, DialectEscaped, `"C:\\Users\\me"`},
+		{"Escaped drops original quotes", `"C:\a b\c"`, DialectEscaped, `"C:\\a b\\c"`},
+		{"Original", `C:\Users\me`, DialectOriginal, `C:\Users\me`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.expected {
+				t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvert_UsesDefaultDialect(t *testing.T) {
+	pc := newTestConverter()
+	pc.SetDialect(DialectWSL)
+	if out := pc.Convert(`C:\a\b`); out != `/mnt/c/a/b` {
+		t.Fatalf("expected WSL path, got %q", out)
+	}
+}
+
+func TestParseDialect(t *testing.T) {
+	if d, err := ParseDialect(" WSL "); err != nil || d != DialectWSL {
+		t.Errorf("ParseDialect(\" WSL \") = %q, %v", d, err)
+	}
+	if _, err := ParseDialect("cygwin"); err == nil {
+		t.Error("expected error for unknown dialect")
+	}
+}
@@ -0,0 +1,233 @@
+package pathconv
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// QuotePolicy 转换结果的加引号策略
+type QuotePolicy string
+
+const (
+	QuoteNone   QuotePolicy = "none"   // 去掉原文本的引号，输出不加引号
+	QuoteKeep   QuotePolicy = "keep"   // 原文本有双引号时保留双引号，否则不加
+	QuoteAlways QuotePolicy = "always" // 总是按目标shell的规则加引号
+	QuoteAuto   QuotePolicy = "auto"   // 只在包含空格或特殊字符时按目标shell的规则加引号
+)
+
+// Shell 粘贴目标shell，决定引号与转义规则
+type Shell string
+
+const (
+	ShellPOSIX      Shell = "posix"      // bash、zsh等POSIX shell，使用单引号
+	ShellPowerShell Shell = "powershell" // PowerShell，使用单引号
+	ShellCmd        Shell = "cmd"        // cmd.exe，使用双引号
+	ShellFish       Shell = "fish"       // fish，使用单引号
+)
+
+// ParseQuotePolicy 解析加引号策略，不区分大小写，空字符串视为keep
+// 参数:
+//   - name: 策略名称
+//
+// 返回值:
+//   - QuotePolicy: 对应的策略
+//   - error: 名称无效时返回错误
+func ParseQuotePolicy(name string) (QuotePolicy, error) {
+	switch p := QuotePolicy(strings.ToLower(strings.TrimSpace(name))); p {
+	case "":
+		return QuoteKeep, nil
+	case QuoteNone, QuoteKeep, QuoteAlways, QuoteAuto:
+		return p, nil
+	}
+	return "", fmt.Errorf("未知的引号策略: %s", name)
+}
+
+// ParseShell 解析目标shell，不区分大小写，bash、sh、zsh视为posix，pwsh视为powershell
+// 参数:
+//   - name: shell名称
+//
+// 返回值:
+//   - Shell: 对应的shell
+//   - error: 名称无效时返回错误
+func ParseShell(name string) (Shell, error) {
+	switch s := strings.ToLower(strings.TrimSpace(name)); s {
+	case "", "posix", "bash", "sh", "zsh":
+		return ShellPOSIX, nil
+	case "powershell", "pwsh":
+		return ShellPowerShell, nil
+	case "cmd":
+		return ShellCmd, nil
+	case "fish":
+		return ShellFish, nil
+	}
+	return "", fmt.Errorf("未知的目标shell: %s", name)
+}
+
+// QuoteFor 按目标shell的规则为文本加引号
+// 参数:
+//   - s: 文本
+//   - sh: 目标shell
+//   - always: 为false时只在需要时加引号
+//
+// 返回值:
+//   - string: 粘贴到目标shell后作为单个参数、内容与s完全相同的文本
+func QuoteFor(s string, sh Shell, always bool) string {
+	if !always && !needsQuoting(s, sh) {
+		return s
+	}
+	switch sh {
+	case ShellPowerShell:
+		// 单引号内只有单引号需要转义（写两次）；PowerShell同样把弯引号视为单引号
+		var b strings.Builder
+		b.WriteByte('\'')
+		for _, r := range s {
+			if r == '\'' || r == '‘' || r == '’' || r == '‚' || r == '‛' {
+				b.WriteRune(r)
+			}
+			b.WriteRune(r)
+		}
+		b.WriteByte('\'')
+		return b.String()
+	case ShellCmd:
+		// 双引号内的 & | < > ^ 都不再有特殊含义；文件名中不会出现双引号，这里按惯例写两次
+		// 注意 %VAR% 在双引号内仍会被展开，cmd没有可靠的转义方式
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	case ShellFish:
+		// fish单引号内的反斜杠和单引号需要用反斜杠转义
+		r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+		return "'" + r.Replace(s) + "'"
+	default:
+		// POSIX单引号内没有任何转义，单引号需要先结束引号再转义：'\''
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+}
+
+// needsQuoting 报告文本在目标shell中是否需要加引号
+func needsQuoting(s string, sh Shell) bool {
+	if s == "" {
+		return true
+	}
+	for i, r := range s {
+		// POSIX与fish中只有开头的 ~ 会被展开，如 PROGRA~1 中的 ~ 保持字面含义
+		if r == '~' && i > 0 && (sh == ShellPOSIX || sh == ShellFish) {
+			continue
+		}
+		if !isShellSafe(r, sh) {
+			return true
+		}
+	}
+	return false
+}
+
+// isShellSafe 报告字符在目标shell中不加引号时是否保持字面含义
+func isShellSafe(r rune, sh Shell) bool {
+	if r < 0x80 && (isASCIILetter(byte(r)) || (r >= '0' && r <= '9')) {
+		return true
+	}
+	switch sh {
+	case ShellPowerShell:
+		return strings.ContainsRune(`_-./:\+=~`, r) || (r >= 0x80 && (unicode.IsLetter(r) || unicode.IsDigit(r)) && !isPowerShellQuote(r))
+	case ShellCmd:
+		return strings.ContainsRune(`_-./:\+~$'`+"`!#@[]{}", r) || (r >= 0x80 && unicode.IsPrint(r) && !unicode.IsSpace(r))
+	case ShellFish:
+		return strings.ContainsRune(`_-./:+=,@`, r)
+	default:
+		return strings.ContainsRune(`_-./:+=,@%`, r)
+	}
+}
+
+// isPowerShellQuote 报告字符是否被PowerShell视为引号
+func isPowerShellQuote(r rune) bool {
+	return strings.ContainsRune("‘’‚‛“”„", r)
+}
+
+// shellQuote 按策略为转换结果加引号
+// POSIX与fish中，开头的 ~ 和env_mode生成的 $VAR 引用留在引号外，以便shell展开
+// 参数:
+//   - s: 转换结果，不含原文本的引号
+//   - hadQuotes: 原文本两端是否有双引号
+//   - policy: 加引号策略
+//   - sh: 目标shell
+//   - keepVars: 是否把 $VAR、${VAR} 视为需要展开的变量引用
+func shellQuote(s string, hadQuotes bool, policy QuotePolicy, sh Shell, keepVars bool) string {
+	switch policy {
+	case QuoteNone:
+		return s
+	case QuoteAlways, QuoteAuto:
+		always := policy == QuoteAlways
+		if sh != ShellPOSIX && sh != ShellFish {
+			return QuoteFor(s, sh, always)
+		}
+		var b strings.Builder
+		for _, tok := range splitExpansions(s, keepVars) {
+			if tok.expand {
+				b.WriteString(tok.text)
+			} else {
+				b.WriteString(QuoteFor(tok.text, sh, always))
+			}
+		}
+		return b.String()
+	default:
+		if hadQuotes {
+			return `"` + s + `"`
+		}
+		return s
+	}
+}
+
+// shellToken 引号处理时的文本片段
+type shellToken struct {
+	text   string // 片段内容
+	expand bool   // 是否为需要shell展开、必须留在引号外的片段
+}
+
+// splitExpansions 拆分出开头的 ~ 以及（keepVars为true时）$VAR、${VAR} 引用
+func splitExpansions(s string, keepVars bool) []shellToken {
+	var tokens []shellToken
+	if s == "~" || strings.HasPrefix(s, "~/") {
+		tokens = append(tokens, shellToken{text: "~", expand: true})
+		s = s[1:]
+	}
+
+	start := 0
+	for i := 0; keepVars && i < len(s); {
+		n := varRefLen(s[i:])
+		if n == 0 {
+			i++
+			continue
+		}
+		if i > start {
+			tokens = append(tokens, shellToken{text: s[start:i]})
+		}
+		tokens = append(tokens, shellToken{text: s[i : i+n], expand: true})
+		i += n
+		start = i
+	}
+	if start < len(s) {
+		tokens = append(tokens, shellToken{text: s[start:]})
+	}
+	return tokens
+}
+
+// varRefLen 返回s开头的 $VAR 或 ${VAR} 引用的长度，不是变量引用时返回0
+func varRefLen(s string) int {
+	if len(s) < 2 || s[0] != '$' {
+		return 0
+	}
+	if s[1] == '{' {
+		end := strings.IndexByte(s, '}')
+		if end > 2 && isShellIdentifier(s[2:end]) {
+			return end + 1
+		}
+		return 0
+	}
+	n := 1
+	for n < len(s) && isIdentifierChar(s[n]) {
+		n++
+	}
+	if n == 1 || !isShellIdentifier(s[1:n]) {
+		return 0
+	}
+	return n
+}
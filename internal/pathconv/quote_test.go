@@ -0,0 +1,135 @@
+package pathconv
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// quoteCases 各shell的引号测试矩阵，want按 posix、powershell、cmd、fish 的顺序排列
+var quoteCases = []struct {
+	input string
+	want  [4]string
+}{
+	{`C:/tools/app`, [4]string{`C:/tools/app`, `C:/tools/app`, `C:/tools/app`, `C:/tools/app`}},
+	{`C:/Program Files/Foo`, [4]string{`'C:/Program Files/Foo'`, `'C:/Program Files/Foo'`, `"C:/Program Files/Foo"`, `'C:/Program Files/Foo'`}},
+	{`/mnt/c/$money`, [4]string{`'/mnt/c/$money'`, `'/mnt/c/$money'`, `/mnt/c/$money`, `'/mnt/c/$money'`}},
+	{"C:/a`b", [4]string{"'C:/a`b'", "'C:/a`b'", "C:/a`b", "'C:/a`b'"}},
+	{`C:/it's`, [4]string{`'C:/it'\''s'`, `'C:/it''s'`, `C:/it's`, `'C:/it\'s'`}},
+	{`C:/wow!`, [4]string{`'C:/wow!'`, `'C:/wow!'`, `C:/wow!`, `'C:/wow!'`}},
+	{`C:/a&b`, [4]string{`'C:/a&b'`, `'C:/a&b'`, `"C:/a&b"`, `'C:/a&b'`}},
+	{`C:\a b\`, [4]string{`'C:\a b\'`, `'C:\a b\'`, `"C:\a b\"`, `'C:\\a b\\'`}},
+	{`C:/PROGRA~1/x`, [4]string{`C:/PROGRA~1/x`, `C:/PROGRA~1/x`, `C:/PROGRA~1/x`, `C:/PROGRA~1/x`}},
+	{`C:/报告 2024`, [4]string{`'C:/报告 2024'`, `'C:/报告 2024'`, `"C:/报告 2024"`, `'C:/报告 2024'`}},
+	{`C:/it’s`, [4]string{`'C:/it’s'`, `'C:/it’’s'`, `C:/it’s`, `'C:/it’s'`}},
+}
+
+var quoteShells = [4]Shell{ShellPOSIX, ShellPowerShell, ShellCmd, ShellFish}
+
+func TestQuoteFor_Auto(t *testing.T) {
+	for _, tt := range quoteCases {
+		for i, sh := range quoteShells {
+			if got := QuoteFor(tt.input, sh, false); got != tt.want[i] {
+				t.Errorf("QuoteFor(%q, %s, auto) = %s, want %s", tt.input, sh, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestQuoteFor_Always(t *testing.T) {
+	want := [4]string{`'C:/x'`, `'C:/x'`, `"C:/x"`, `'C:/x'`}
+	for i, sh := range quoteShells {
+		if got := QuoteFor("C:/x", sh, true); got != want[i] {
+			t.Errorf("QuoteFor(C:/x, %s, always) = %s, want %s", sh, got, want[i])
+		}
+	}
+}
+
+// TestQuoteFor_POSIXShellDecodes 用真实的sh验证POSIX引号结果解析后与原文一致
+func TestQuoteFor_POSIXShellDecodes(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	for _, tt := range quoteCases {
+		quoted := QuoteFor(tt.input, ShellPOSIX, true)
+		out, err := exec.Command(sh, "-c", "printf '%s' "+quoted).Output()
+		if err != nil {
+			t.Fatalf("sh failed for %s: %v", quoted, err)
+		}
+		if string(out) != tt.input {
+			t.Errorf("sh decoded %s as %q, want %q", quoted, out, tt.input)
+		}
+	}
+}
+
+func TestConvertTo_QuotePolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  QuotePolicy
+		shell   Shell
+		dialect Dialect
+		input   string
+		want    string
+	}{
+		{"keep quoted", QuoteKeep, ShellPOSIX, DialectForward, `"C:\Program Files\Foo"`, `"C:/Program Files/Foo"`},
+		{"keep unquoted", QuoteKeep, ShellPOSIX, DialectForward, `C:\Program Files\Foo`, `C:/Program Files/Foo`},
+		{"none strips quotes", QuoteNone, ShellPOSIX, DialectForward, `"C:\Program Files\Foo"`, `C:/Program Files/Foo`},
+		{"auto posix", QuoteAuto, ShellPOSIX, DialectWSL, `"C:\Program Files\Foo"`, `'/mnt/c/Program Files/Foo'`},
+		{"auto not needed", QuoteAuto, ShellPOSIX, DialectForward, `"C:\tools\foo"`, `C:/tools/foo`},
+		{"always powershell", QuoteAlways, ShellPowerShell, DialectForward, `C:\tools`, `'C:/tools'`},
+		{"auto cmd", QuoteAuto, ShellCmd, DialectForward, `C:\a b`, `"C:/a b"`},
+		{"escaped dialect unaffected", QuoteAlways, ShellPOSIX, DialectEscaped, `C:\a`, `"C:\\a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := newTestConverter()
+			pc.SetQuoting(tt.policy, tt.shell)
+			if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+				t.Errorf("ConvertTo(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertTo_QuotingLeavesExpansionsOutside(t *testing.T) {
+	env := MapEnv(map[string]string{"USERPROFILE": `C:\Users\me`})
+	tests := []struct {
+		mode     EnvMode
+		contract ContractMode
+		shell    Shell
+		input    string
+		want     string
+	}{
+		{EnvPOSIX, ContractNone, ShellPOSIX, `%USERPROFILE%\My Docs`, `$USERPROFILE'/My Docs'`},
+		{EnvPOSIXBraced, ContractNone, ShellPOSIX, `%USERPROFILE%\x`, `${USERPROFILE}/x`},
+		{EnvKeep, ContractTilde, ShellPOSIX, `C:\Users\me\My Docs`, `~'/My Docs'`},
+		{EnvKeep, ContractTilde, ShellFish, `C:\Users\me\.ssh`, `~/.ssh`},
+		// 非posix环境变量模式下 $ 是字面字符
+		{EnvKeep, ContractNone, ShellPOSIX, `C:\$Recycle.Bin`, `'C:/$Recycle.Bin'`},
+	}
+	for _, tt := range tests {
+		pc := newTestConverter()
+		pc.SetEnvOptions(tt.mode, tt.contract, env)
+		pc.SetQuoting(QuoteAuto, tt.shell)
+		if got := pc.Convert(tt.input); got != tt.want {
+			t.Errorf("Convert(%q) with %s/%s = %s, want %s", tt.input, tt.mode, tt.shell, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuotePolicyAndShell(t *testing.T) {
+	if p, err := ParseQuotePolicy("AUTO"); err != nil || p != QuoteAuto {
+		t.Errorf("ParseQuotePolicy = %q, %v", p, err)
+	}
+	if _, err := ParseQuotePolicy("sometimes"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+	for in, want := range map[string]Shell{"bash": ShellPOSIX, "zsh": ShellPOSIX, "pwsh": ShellPowerShell, "cmd": ShellCmd, "fish": ShellFish} {
+		if got, err := ParseShell(in); err != nil || got != want {
+			t.Errorf("ParseShell(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseShell("tcsh"); err == nil {
+		t.Error("expected error for unknown shell")
+	}
+}
@@ -0,0 +1,79 @@
+package pathconv
+
+import (
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+var (
+	modMpr                 = windows.NewLazySystemDLL("mpr.dll")
+	procWNetGetConnectionW = modMpr.NewProc("WNetGetConnectionW")
+)
+
+// NewSystemResolver 创建查询当前用户映射的网络驱动器的Resolver
+// 参数:
+//   - timeout: 单次查询的超时时间，不大于0时不限制
+//   - ttl: 查询结果的缓存时间，不大于0时不缓存
+//   - c: 时间源
+//
+// 返回值:
+//   - *SnapshotResolver: Resolver实例
+func NewSystemResolver(timeout, ttl time.Duration, c clock.Clock) *SnapshotResolver {
+	return NewSnapshotResolver(remoteDrives, timeout, ttl, c)
+}
+
+// remoteDrives 查询所有网络驱动器映射的UNC根路径
+// 只对GetDriveType报告为网络驱动器的盘符调用WNetGetConnection
+func remoteDrives() map[byte]string {
+	drives := make(map[byte]string)
+	mask, err := windows.GetLogicalDrives()
+	if err != nil {
+		return drives
+	}
+	for i := 0; i < 26; i++ {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		drive := byte('A' + i)
+		root, err := windows.UTF16PtrFromString(string(drive) + `:\`)
+		if err != nil || windows.GetDriveType(root) != windows.DRIVE_REMOTE {
+			continue
+		}
+		if remote, ok := wnetConnection(drive); ok {
+			drives[drive] = remote
+		}
+	}
+	return drives
+}
+
+// wnetConnection 通过WNetGetConnection查询驱动器映射的UNC根路径
+func wnetConnection(drive byte) (string, bool) {
+	local, err := windows.UTF16PtrFromString(string(drive) + ":")
+	if err != nil {
+		return "", false
+	}
+
+	buf := make([]uint16, windows.MAX_PATH)
+	for {
+		size := uint32(len(buf))
+		ret, _, _ := procWNetGetConnectionW.Call(
+			uintptr(unsafe.Pointer(local)),
+			uintptr(unsafe.Pointer(&buf[0])),
+			uintptr(unsafe.Pointer(&size)),
+		)
+		switch windows.Errno(ret) {
+		case windows.ERROR_SUCCESS:
+			return windows.UTF16ToString(buf), true
+		case windows.ERROR_MORE_DATA:
+			// 缓冲区不足时size为所需的字符数
+			buf = make([]uint16, size)
+		default:
+			// ERROR_NOT_CONNECTED等：驱动器不是网络驱动器
+			return "", false
+		}
+	}
+}
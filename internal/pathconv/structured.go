@@ -0,0 +1,347 @@
+package pathconv
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Format 结构化文本的格式
+type Format string
+
+const (
+	FormatNone Format = ""     // 不是可识别的结构化文本
+	FormatJSON Format = "json" // JSON对象或数组
+	FormatYAML Format = "yaml" // 由 key: value 和 - item 组成的YAML片段
+	FormatINI  Format = "ini"  // INI文件或.env文件，由 key=value、[section] 和注释组成
+)
+
+// yamlKeyPattern YAML映射的一行：缩进、可选的序列标记、键、冒号，以及冒号后空白分隔的值
+var yamlKeyPattern = regexp.MustCompile(`^(\s*(?:-\s+)?(?:[^\s:#"'\[\]{},&*!|>%@` + "`" + `-][^:#]*?|"[^"]*"|'[^']*')\s*:)(?:(\s+)(.*))?$`)
+
+// yamlItemPattern YAML序列的一行：缩进、序列标记以及空白分隔的值
+var yamlItemPattern = regexp.MustCompile(`^(\s*-)(\s+)(.*)$`)
+
+// iniKeyPattern INI或.env的一行：可选的export、键、等号以及值
+var iniKeyPattern = regexp.MustCompile(`^(\s*(?:export\s+)?[A-Za-z_][\w.-]*(?: [\w.-]+)*\s*=[ \t]*)(.*)$`)
+
+// iniSectionPattern INI的节标题
+var iniSectionPattern = regexp.MustCompile(`^\s*\[[^\]]+\]\s*$`)
+
+// yamlIndicators 不能作为YAML普通标量开头的字符
+const yamlIndicators = "-?:,[]{}#&*!|>'\"%@`"
+
+// SetStructured 设置是否按结构转换JSON、YAML和INI片段
+// 参数:
+//   - enabled: 为true时只转换结构中像路径的字符串值，保持键、其他值和格式不变
+func (pc *PathConverter) SetStructured(enabled bool) {
+	pc.structured = enabled
+}
+
+// DetectFormat 识别文本的结构化格式
+// JSON必须是完整有效的对象或数组；YAML和INI的每个非空行都必须符合其语法，且至少有一个键
+// 参数:
+//   - text: 要识别的文本
+//
+// 返回值:
+//   - Format: 识别出的格式，无法识别时为FormatNone
+func DetectFormat(text string) Format {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return FormatNone
+	}
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		if json.Valid([]byte(trimmed)) {
+			return FormatJSON
+		}
+		// 以 [ 开头的也可能是INI的节标题
+		if trimmed[0] == '{' {
+			return FormatNone
+		}
+	}
+	if matchLines(text, iniLineKind) {
+		return FormatINI
+	}
+	if matchLines(text, yamlLineKind) {
+		return FormatYAML
+	}
+	return FormatNone
+}
+
+// 行的分类结果
+const (
+	lineInvalid = iota // 不符合语法
+	lineOther          // 空行、注释、节标题等
+	lineKey            // 键值行
+)
+
+// matchLines 报告文本的每一行是否都符合语法，且至少有一个键值行
+func matchLines(text string, kind func(line string) int) bool {
+	keys := 0
+	for _, line := range strings.Split(text, "\n") {
+		switch kind(strings.TrimSuffix(line, "\r")) {
+		case lineInvalid:
+			return false
+		case lineKey:
+			keys++
+		}
+	}
+	return keys > 0
+}
+
+// iniLineKind 对INI或.env的一行分类
+func iniLineKind(line string) int {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == "", trimmed[0] == ';', trimmed[0] == '#', iniSectionPattern.MatchString(line):
+		return lineOther
+	case iniKeyPattern.MatchString(line):
+		return lineKey
+	}
+	return lineInvalid
+}
+
+// yamlLineKind 对YAML的一行分类，块标量等多行结构不予支持
+func yamlLineKind(line string) int {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == "", trimmed[0] == '#', trimmed == "---", trimmed == "...":
+		return lineOther
+	case yamlKeyPattern.MatchString(line):
+		if m := yamlKeyPattern.FindStringSubmatch(line); strings.HasPrefix(m[3], "|") || strings.HasPrefix(m[3], ">") {
+			return lineInvalid
+		}
+		return lineKey
+	case yamlItemPattern.MatchString(line):
+		return lineOther
+	}
+	return lineInvalid
+}
+
+// structuredDialect 返回结构中的字符串值使用的输出格式
+// 字符串值由结构本身负责编码，自带引号的格式在结构中保持原值，string-forward 按 forward 处理
+func structuredDialect(d Dialect) Dialect {
+	switch {
+	case d == DialectStringForward:
+		return DialectForward
+	case d.quotesOwnOutput():
+		return DialectOriginal
+	}
+	return d
+}
+
+// convertStructured 按结构转换JSON、YAML或INI片段中的路径
+// 参数:
+//   - text: 要转换的文本
+//   - d: 输出格式
+//
+// 返回值:
+//   - string: 转换后的文本
+//   - bool: 文本是否为可识别的结构化文本，返回false时调用方应按普通文本转换
+func (pc *PathConverter) convertStructured(text string, d Dialect) (string, bool) {
+	if !pc.structured || !strings.Contains(text, `\`) {
+		return "", false
+	}
+	format := DetectFormat(text)
+	d = structuredDialect(d)
+	var out string
+	switch format {
+	case FormatJSON:
+		out = pc.convertJSON(text, d)
+	case FormatYAML:
+		out = convertLines(text, func(line string) string { return pc.convertYAMLLine(line, d) })
+	case FormatINI:
+		out = convertLines(text, func(line string) string { return pc.convertINILine(line, d) })
+	default:
+		return "", false
+	}
+	pc.logger.Debug("结构化转换(%s, %s)", format, d)
+	return out, true
+}
+
+// convertValue 转换结构中的一个字符串值，分号分隔的路径列表逐个转换
+// 参数:
+//   - value: 解码后的字符串值
+//   - d: 输出格式
+//   - policy: 加引号策略
+//
+// 返回值:
+//   - string: 转换后的值，不是路径时返回原值
+func (pc *PathConverter) convertValue(value string, d Dialect, policy QuotePolicy) string {
+	if d == DialectOriginal {
+		return value
+	}
+	if pc.splitLists && !strings.Contains(value, "\n") {
+		if converted, ok := pc.convertList(value, d); ok {
+			return converted
+		}
+	}
+	if !pc.shouldConvertItem(value) {
+		return value
+	}
+	return pc.convertText(value, d, policy)
+}
+
+// convertJSON 转换JSON中像路径的字符串值，键和其余文本保持不变
+// 调用方需确保text是有效的JSON
+func (pc *PathConverter) convertJSON(text string, d Dialect) string {
+	var out strings.Builder
+	last := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '"' {
+			continue
+		}
+		start, end := i, i+1
+		for text[end] != '"' {
+			if text[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		literal := text[start : end+1]
+		i = end
+
+		// 后面紧跟冒号的是键
+		if rest := strings.TrimLeft(text[end+1:], " \t\r\n"); strings.HasPrefix(rest, ":") {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal([]byte(literal), &value); err != nil {
+			continue
+		}
+		if converted := pc.convertValue(value, d, QuoteNone); converted != value {
+			out.WriteString(text[last:start])
+			out.WriteString(jsonLiteral(converted))
+			last = end + 1
+		}
+	}
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+// convertLines 逐行转换文本，保留原有的换行符
+func convertLines(text string, convert func(line string) string) string {
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		content := strings.TrimRight(line, "\r\n")
+		lines[i] = convert(content) + line[len(content):]
+	}
+	return strings.Join(lines, "")
+}
+
+// convertYAMLLine 转换YAML一行中的标量值
+// 支持普通标量、单引号和双引号标量，流式集合、锚点和标签保持原样
+func (pc *PathConverter) convertYAMLLine(line string, d Dialect) string {
+	var head, value string
+	if m := yamlKeyPattern.FindStringSubmatch(line); m != nil {
+		head, value = m[1]+m[2], m[3]
+	} else if m := yamlItemPattern.FindStringSubmatch(line); m != nil {
+		head, value = m[1]+m[2], m[3]
+	} else {
+		return line
+	}
+	if value == "" {
+		return line
+	}
+
+	switch value[0] {
+	case '"':
+		end := closingDoubleQuote(value)
+		var decoded string
+		// YAML的双引号转义兼容JSON，JSON无法解码的（如 \x41）保持原样
+		if end < 0 || json.Unmarshal([]byte(value[:end+1]), &decoded) != nil {
+			return line
+		}
+		if converted := pc.convertValue(decoded, d, QuoteNone); converted != decoded {
+			return head + jsonLiteral(converted) + value[end+1:]
+		}
+	case '\'':
+		end := closingSingleQuote(value)
+		if end < 0 {
+			return line
+		}
+		decoded := strings.ReplaceAll(value[1:end], "''", "'")
+		if converted := pc.convertValue(decoded, d, QuoteNone); converted != decoded {
+			return head + yamlSingleQuote(converted) + value[end+1:]
+		}
+	case '[', '{', '&', '*', '!', '|', '>':
+		return line
+	default:
+		scalar, comment := splitComment(value)
+		if converted := pc.convertValue(scalar, d, QuoteNone); converted != scalar {
+			if needsYAMLQuotes(converted) {
+				converted = yamlSingleQuote(converted)
+			}
+			return head + converted + comment
+		}
+	}
+	return line
+}
+
+// convertINILine 转换INI或.env一行中的值
+// 双引号值按原样保留引号，单引号值只转换引号内的部分；.env中空白后的 # 开始注释
+func (pc *PathConverter) convertINILine(line string, d Dialect) string {
+	m := iniKeyPattern.FindStringSubmatch(line)
+	if m == nil || m[2] == "" {
+		return line
+	}
+	head, value := m[1], m[2]
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		inner := value[1 : len(value)-1]
+		return head + "'" + pc.convertValue(inner, d, QuoteNone) + "'"
+	}
+	value, comment := splitComment(value)
+	return head + pc.convertValue(value, d, QuoteKeep) + comment
+}
+
+// splitComment 拆分值与其后由空白和 # 开始的注释，值的结尾空白归入注释
+func splitComment(value string) (string, string) {
+	end := len(value)
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			end = i
+			break
+		}
+	}
+	trimmed := strings.TrimRight(value[:end], " \t")
+	return trimmed, value[len(trimmed):]
+}
+
+// closingDoubleQuote 返回双引号字符串的结束引号位置，没有时返回-1
+func closingDoubleQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// closingSingleQuote 返回YAML单引号字符串的结束引号位置，其中两个连续的单引号表示一个单引号，没有时返回-1
+func closingSingleQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// yamlSingleQuote 将字符串写成YAML单引号标量
+func yamlSingleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// needsYAMLQuotes 报告字符串作为普通标量时是否会被解析为其他内容
+func needsYAMLQuotes(s string) bool {
+	return s == "" || strings.ContainsRune(yamlIndicators, rune(s[0])) ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":")
+}
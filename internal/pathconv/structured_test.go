@@ -0,0 +1,124 @@
+package pathconv
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Format
+	}{
+		{`{"root": "C:\\data\\x"}`, FormatJSON},
+		{`["C:\\a", 1, null]`, FormatJSON},
+		{`{"root": "C:\data"}`, FormatNone},
+		{"root: C:\\data\nitems:\n  - D:\\x\n", FormatYAML},
+		{"- name: build\n  dir: 'C:\\src'", FormatYAML},
+		{"[paths]\nroot = C:\\data\n; comment\n", FormatINI},
+		{"export GOPATH=C:\\go\nPATH=C:\\bin;C:\\tools", FormatINI},
+		{"[paths", FormatNone},
+		{`C:\Users\me`, FormatNone},
+		{"see C:\\a\nand C:\\b", FormatNone},
+		{"run: |\n  cd C:\\src", FormatNone},
+		{"", FormatNone},
+	}
+	for _, tt := range tests {
+		if got := DetectFormat(tt.input); got != tt.want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestConvertTo_JSON(t *testing.T) {
+	pc := newTestConverter()
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`{"root": "C:\\data\\x"}`, DialectForward, `{"root": "C:/data/x"}`},
+		{`{"root": "C:\\data\\x"}`, DialectWSL, `{"root": "/mnt/c/data/x"}`},
+		// 键、非路径的值和格式保持不变
+		{"{\n  \"C:\\\\key\": \"C:\\\\v\",\n  \"n\": 1,\n  \"s\": \"a\\\\b c\",\n  \"u\": \"\\u0041\"\n}", DialectForward,
+			"{\n  \"C:\\\\key\": \"C:/v\",\n  \"n\": 1,\n  \"s\": \"a\\\\b c\",\n  \"u\": \"\\u0041\"\n}"},
+		{`["C:\\a b\\c.txt", {"list": "C:\\a;D:\\b"}]`, DialectMSYS, `["/c/a b/c.txt", {"list": "/c/a:/d/b"}]`},
+		{`{"p": "C:\\Users\\me"}`, DialectStringForward, `{"p": "C:/Users/me"}`},
+		// 自带引号的格式在JSON中保持原值
+		{`{"p": "C:\\Users\\me"}`, DialectEscaped, `{"p": "C:\\Users\\me"}`},
+		{`{"p": "HKLM\\Software"}`, DialectForward, `{"p": "HKLM\\Software"}`},
+	}
+	for _, tt := range tests {
+		got := pc.ConvertTo(tt.input, tt.dialect)
+		if got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+		if !json.Valid([]byte(got)) {
+			t.Errorf("ConvertTo(%q, %s) produced invalid JSON %q", tt.input, tt.dialect, got)
+		}
+	}
+}
+
+func TestConvertTo_YAML(t *testing.T) {
+	pc := newTestConverter()
+	pc.SetQuoting(QuoteAuto, ShellPOSIX)
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{"root: C:\\data\\x\nname: app\n", DialectForward, "root: C:/data/x\nname: app\n"},
+		{"root: C:\\data\\x   # build output\r\n", DialectWSL, "root: /mnt/c/data/x   # build output\r\n"},
+		{"paths:\n  - C:\\a\n  - \"D:\\\\b c\"\n  - 'E:\\it''s'\n", DialectWSL,
+			"paths:\n  - /mnt/c/a\n  - \"/mnt/d/b c\"\n  - '/mnt/e/it''s'\n"},
+		{"- name: x\n  dir: C:\\My Files\\x\n", DialectMSYS, "- name: x\n  dir: /c/My Files/x\n"},
+		// 无法按JSON解码的双引号值和流式集合保持原样
+		{"a: \"C:\\x41\"\nb: [C:\\a]\n", DialectForward, "a: \"C:\\x41\"\nb: [C:\\a]\n"},
+		{"url: http://example.com\ndir: C:\\x", DialectForward, "url: http://example.com\ndir: C:/x"},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestConvertTo_INI(t *testing.T) {
+	pc := newTestConverter()
+	pc.SetQuoting(QuoteAlways, ShellPOSIX)
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{"[paths]\nroot = C:\\data\\x\n; C:\\comment\n", DialectForward, "[paths]\nroot = C:/data/x\n; C:\\comment\n"},
+		{"export GOPATH=C:\\go # workspace\nPATH=C:\\bin;C:\\tools\n", DialectWSL,
+			"export GOPATH=/mnt/c/go # workspace\nPATH=/mnt/c/bin:/mnt/c/tools\n"},
+		{"A=\"C:\\Program Files\\x\"\nB='C:\\y'\nC=plain\n", DialectForward, "A=\"C:/Program Files/x\"\nB='C:/y'\nC=plain\n"},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestShouldConvert_Structured(t *testing.T) {
+	pc := newTestConverter()
+	if !pc.ShouldConvert(`{"root": "C:\\data\\x"}`) {
+		t.Error("JSON with a path value should be convertible")
+	}
+	if pc.ShouldConvert(`{"C:\\key": 1}`) {
+		t.Error("JSON with paths only in keys should not be convertible")
+	}
+
+	pc.SetStructured(false)
+	if got := pc.ConvertTo("root: C:\\data", DialectWSL); got != "root: C:/data" {
+		t.Errorf("with structured conversion disabled the text is converted as a whole, got %q", got)
+	}
+	// 无法解析的JSON按普通文本转换
+	pc.SetStructured(true)
+	if got := pc.ConvertTo(`{"root": C:\data}`, DialectForward); got != `{"root": C:/data}` {
+		t.Errorf("invalid JSON should fall back to text mode, got %q", got)
+	}
+}
@@ -0,0 +1,133 @@
+package pathconv
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Target 粘贴目标程序的信息，通常取自前台窗口
+type Target struct {
+	Process string // 进程映像路径或文件名，如 C:\Windows\System32\wsl.exe
+	Title   string // 窗口标题
+}
+
+// TargetRule 一条目标程序到输出格式的匹配规则
+// Process 和 Title 为不区分大小写的通配符模式，* 匹配任意字符序列，? 匹配单个字符；
+// Process 只与映像的文件名比较，模式不含扩展名时 .exe 可以省略；为空的字段不参与匹配
+type TargetRule struct {
+	Process string  `json:"process"` // 进程文件名模式，如 WindowsTerminal.exe、Code 或 mintty*
+	Title   string  `json:"title"`   // 窗口标题模式，如 *@*:* 匹配bash提示符形式的标题
+	Dialect Dialect `json:"dialect"` // 匹配时使用的输出格式
+}
+
+// targetRule 编译后的匹配规则
+type targetRule struct {
+	process *regexp.Regexp // 为nil时不检查进程
+	title   *regexp.Regexp // 为nil时不检查标题
+	dialect Dialect
+}
+
+// TargetMatcher 按目标程序选择输出格式
+// 规则按配置顺序匹配，第一条进程和标题都匹配的规则生效，因此更具体的规则应写在前面
+type TargetMatcher struct {
+	rules []targetRule // 编译后的规则
+}
+
+// NewTargetMatcher 创建目标程序匹配器
+// 参数:
+//   - rules: 匹配规则，顺序决定优先级
+//
+// 返回值:
+//   - *TargetMatcher: 匹配器实例
+//   - error: 规则的进程和标题都为空，或输出格式无效时返回错误
+func NewTargetMatcher(rules []TargetRule) (*TargetMatcher, error) {
+	m := &TargetMatcher{}
+	for _, r := range rules {
+		if strings.TrimSpace(r.Process) == "" && strings.TrimSpace(r.Title) == "" {
+			return nil, fmt.Errorf("目标程序规则缺少进程或窗口标题: %q", r.Dialect)
+		}
+		d, err := ParseDialect(string(r.Dialect))
+		if err != nil {
+			return nil, err
+		}
+		compiled := targetRule{dialect: d}
+		if p := strings.TrimSpace(r.Process); p != "" {
+			compiled.process = compileWildcard(p)
+		}
+		if t := strings.TrimSpace(r.Title); t != "" {
+			compiled.title = compileWildcard(t)
+		}
+		m.rules = append(m.rules, compiled)
+	}
+	return m, nil
+}
+
+// compileWildcard 将通配符模式编译为不区分大小写的完整匹配正则表达式
+func compileWildcard(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// Match 查找目标程序对应的输出格式
+// 参数:
+//   - t: 目标程序信息
+//
+// 返回值:
+//   - Dialect: 第一条匹配规则的输出格式
+//   - bool: 是否有规则匹配，m为nil时总是false
+func (m *TargetMatcher) Match(t Target) (Dialect, bool) {
+	if m == nil {
+		return "", false
+	}
+	name := t.Process
+	if i := strings.LastIndexAny(name, `\/`); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, r := range m.rules {
+		if r.process != nil && !r.process.MatchString(name) && !r.process.MatchString(trimExe(name)) {
+			continue
+		}
+		if r.title != nil && !r.title.MatchString(t.Title) {
+			continue
+		}
+		return r.dialect, true
+	}
+	return "", false
+}
+
+// Dialects 返回规则中出现的输出格式，按首次出现的顺序排列，m为nil时返回nil
+func (m *TargetMatcher) Dialects() []Dialect {
+	if m == nil {
+		return nil
+	}
+	var out []Dialect
+	seen := make(map[Dialect]bool)
+	for _, r := range m.rules {
+		if !seen[r.dialect] {
+			seen[r.dialect] = true
+			out = append(out, r.dialect)
+		}
+	}
+	return out
+}
+
+// trimExe 去掉文件名的 .exe 扩展名，不区分大小写
+func trimExe(name string) string {
+	if len(name) > 4 && strings.EqualFold(name[len(name)-4:], ".exe") {
+		return name[:len(name)-4]
+	}
+	return name
+}
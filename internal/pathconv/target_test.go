@@ -0,0 +1,79 @@
+package pathconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTargetMatcher_Match(t *testing.T) {
+	m, err := NewTargetMatcher([]TargetRule{
+		{Process: "WindowsTerminal.exe", Title: "*Ubuntu*", Dialect: DialectWSL},
+		{Process: "WindowsTerminal", Title: "*@*:*", Dialect: DialectWSL},
+		{Process: "wsl.exe", Dialect: DialectWSL},
+		{Process: "mintty*", Dialect: DialectMSYS},
+		{Process: "Code", Dialect: DialectForward},
+		{Title: "*.py - Notepad++", Dialect: "Python"},
+	})
+	if err != nil {
+		t.Fatalf("NewTargetMatcher returned error: %v", err)
+	}
+
+	tests := []struct {
+		target Target
+		want   Dialect
+		ok     bool
+	}{
+		{Target{`C:\Program Files\WindowsApps\Microsoft.WindowsTerminal\WindowsTerminal.exe`, "Ubuntu-22.04"}, DialectWSL, true},
+		{Target{"windowsterminal.EXE", "me@host: ~/src"}, DialectWSL, true},
+		{Target{"WindowsTerminal.exe", "Windows PowerShell"}, "", false},
+		{Target{`C:\Windows\System32\wsl.exe`, ""}, DialectWSL, true},
+		{Target{`C:\Program Files\Git\usr\bin\mintty.exe`, "MINGW64:/c/src"}, DialectMSYS, true},
+		{Target{`C:\Users\me\AppData\Local\Programs\Microsoft VS Code\Code.exe`, "main.go - src"}, DialectForward, true},
+		// 不含扩展名的模式不匹配其他扩展名
+		{Target{"Code.com", ""}, "", false},
+		{Target{"notepad++.exe", "C:\\src\\app.py - Notepad++"}, DialectPython, true},
+		{Target{"", ""}, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := m.Match(tt.target)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Match(%+v) = (%q, %v), want (%q, %v)", tt.target, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got := m.Dialects(); !reflect.DeepEqual(got, []Dialect{DialectWSL, DialectMSYS, DialectForward, DialectPython}) {
+		t.Errorf("Dialects() = %v", got)
+	}
+}
+
+func TestTargetMatcher_Order(t *testing.T) {
+	m, err := NewTargetMatcher([]TargetRule{
+		{Process: "*", Title: "*[WSL]*", Dialect: DialectWSL},
+		{Process: "*", Dialect: DialectEscaped},
+	})
+	if err != nil {
+		t.Fatalf("NewTargetMatcher returned error: %v", err)
+	}
+	if d, _ := m.Match(Target{"Terminal.exe", "bash [WSL]"}); d != DialectWSL {
+		t.Errorf("the first matching rule should win, got %q", d)
+	}
+	if d, _ := m.Match(Target{"Terminal.exe", "cmd"}); d != DialectEscaped {
+		t.Errorf("later rules should apply when earlier ones do not match, got %q", d)
+	}
+
+	var nilMatcher *TargetMatcher
+	if _, ok := nilMatcher.Match(Target{"Code.exe", ""}); ok {
+		t.Error("a nil matcher should never match")
+	}
+}
+
+func TestNewTargetMatcher_Invalid(t *testing.T) {
+	for _, rules := range [][]TargetRule{
+		{{Dialect: DialectWSL}},
+		{{Process: "Code.exe", Dialect: "vim"}},
+	} {
+		if _, err := NewTargetMatcher(rules); err == nil {
+			t.Errorf("NewTargetMatcher(%+v) should fail", rules)
+		}
+	}
+}
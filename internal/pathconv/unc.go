@@ -0,0 +1,217 @@
+package pathconv
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+// UNCMode 映射网络驱动器与UNC路径之间的转换方向
+type UNCMode string
+
+const (
+	UNCModeNone    UNCMode = "none"     // 不转换
+	UNCModeToUNC   UNCMode = "to-unc"   // 将映射驱动器路径转换为UNC路径，如 Z:\builds → \\fileserver\builds
+	UNCModeToDrive UNCMode = "to-drive" // 将UNC路径转换为本机映射的驱动器路径
+)
+
+// ParseUNCMode 解析UNC转换方向，不区分大小写，空字符串视为none
+// 参数:
+//   - name: 转换方向名称
+//
+// 返回值:
+//   - UNCMode: 对应的转换方向
+//   - error: 名称无效时返回错误
+func ParseUNCMode(name string) (UNCMode, error) {
+	switch m := UNCMode(strings.ToLower(strings.TrimSpace(name))); m {
+	case "":
+		return UNCModeNone, nil
+	case UNCModeNone, UNCModeToUNC, UNCModeToDrive:
+		return m, nil
+	}
+	return "", fmt.Errorf("未知的UNC转换方式: %s", name)
+}
+
+// Resolver 查询驱动器映射的网络路径
+type Resolver interface {
+	// Lookup 返回驱动器映射的UNC根路径，如 'Z' 对应 \\fileserver\builds
+	// 参数drive为大写的驱动器字母；驱动器未映射时返回false
+	Lookup(drive byte) (string, bool)
+}
+
+// StaticResolver 基于固定映射表的Resolver，映射表通常来自配置文件
+type StaticResolver struct {
+	remotes map[byte]string // 大写驱动器字母到UNC根路径的映射
+}
+
+// NewStaticResolver 根据映射表创建Resolver
+// 参数:
+//   - table: 驱动器到UNC根路径的映射，键可以写作 "Z"、"Z:" 或 "z:"，值必须以 \\ 开头
+//
+// 返回值:
+//   - *StaticResolver: Resolver实例
+//   - error: 映射表中有无效的驱动器或路径时返回错误
+func NewStaticResolver(table map[string]string) (*StaticResolver, error) {
+	r := &StaticResolver{remotes: make(map[byte]string, len(table))}
+	for drive, remote := range table {
+		d := strings.TrimSuffix(strings.TrimSpace(drive), ":")
+		if len(d) != 1 || !isASCIILetter(d[0]) {
+			return nil, fmt.Errorf("无效的驱动器: %s", drive)
+		}
+		remote = strings.TrimRight(strings.ReplaceAll(remote, "/", `\`), `\`)
+		if !strings.HasPrefix(remote, `\\`) || len(remote) <= 2 {
+			return nil, fmt.Errorf("无效的UNC路径: %s", remote)
+		}
+		r.remotes[upper(d[0])] = remote
+	}
+	return r, nil
+}
+
+// Lookup 在映射表中查询驱动器
+func (r *StaticResolver) Lookup(drive byte) (string, bool) {
+	remote, ok := r.remotes[upper(drive)]
+	return remote, ok
+}
+
+// SnapshotResolver 基于驱动器映射快照的Resolver
+// 一次查询得到所有网络驱动器的映射，查询带超时并缓存一段时间（见timedLookup），
+// 转换UNC路径时逐个尝试驱动器不会重复访问系统，断开的网络驱动器也不会阻塞剪贴板处理
+type SnapshotResolver struct {
+	query  func() map[byte]string        // 查询所有网络驱动器的映射，键为大写驱动器字母
+	lookup *timedLookup[map[byte]string] // 带超时和缓存的查询
+}
+
+// NewSnapshotResolver 创建基于驱动器映射快照的Resolver
+// 参数:
+//   - query: 查询所有网络驱动器的映射，键为大写驱动器字母
+//   - timeout: 单次查询的超时时间，不大于0时不限制；超时时视为没有映射
+//   - ttl: 快照的缓存时间，不大于0时不缓存
+//   - c: 时间源
+//
+// 返回值:
+//   - *SnapshotResolver: Resolver实例
+func NewSnapshotResolver(query func() map[byte]string, timeout, ttl time.Duration, c clock.Clock) *SnapshotResolver {
+	return &SnapshotResolver{query: query, lookup: newTimedLookup[map[byte]string](timeout, ttl, c)}
+}
+
+// Lookup 在快照中查询驱动器
+func (r *SnapshotResolver) Lookup(drive byte) (string, bool) {
+	drives, _ := r.lookup.do("drives", r.query)
+	remote, ok := drives[upper(drive)]
+	return remote, ok
+}
+
+// chainResolver 依次查询多个Resolver
+type chainResolver []Resolver
+
+// ChainResolvers 组合多个Resolver，按顺序查询，返回第一个命中的结果
+// 常用于让配置文件中的映射覆盖系统查询结果
+// 参数:
+//   - resolvers: 按优先级排列的Resolver，nil会被忽略
+//
+// 返回值:
+//   - Resolver: 组合后的Resolver
+func ChainResolvers(resolvers ...Resolver) Resolver {
+	var chain chainResolver
+	for _, r := range resolvers {
+		if r != nil {
+			chain = append(chain, r)
+		}
+	}
+	return chain
+}
+
+// Lookup 按顺序查询
+func (c chainResolver) Lookup(drive byte) (string, bool) {
+	for _, r := range c {
+		if remote, ok := r.Lookup(drive); ok {
+			return remote, true
+		}
+	}
+	return "", false
+}
+
+// driveToUNC 将映射驱动器上的路径替换为UNC路径
+// 驱动器未映射或路径不以盘符开头时原样返回
+func driveToUNC(p string, r Resolver) string {
+	if len(p) < 2 || p[1] != ':' || !isASCIILetter(p[0]) {
+		return p
+	}
+	rest := p[2:]
+	if rest != "" && !isSeparator(rest[0]) {
+		// C:foo 是相对于驱动器当前目录的路径，无法确定对应的网络路径
+		return p
+	}
+	remote, ok := r.Lookup(upper(p[0]))
+	if !ok {
+		return p
+	}
+	return remote + rest
+}
+
+// uncToDrive 将UNC路径替换为映射驱动器上的路径
+// 多个驱动器都能匹配时选择映射路径最长的一个；前缀比较不区分大小写，且必须在路径分隔符处结束
+func uncToDrive(p string, r Resolver) string {
+	if !strings.HasPrefix(p, `\\`) && !strings.HasPrefix(p, "//") {
+		return p
+	}
+
+	bestDrive, bestLen := byte(0), 0
+	for d := byte('A'); d <= 'Z'; d++ {
+		remote, ok := r.Lookup(d)
+		if !ok || len(remote) <= bestLen || !hasPathPrefix(p, remote) {
+			continue
+		}
+		bestDrive, bestLen = d, len(remote)
+	}
+	if bestDrive == 0 {
+		return p
+	}
+
+	rest := p[bestLen:]
+	if rest == "" {
+		rest = `\`
+	}
+	return string(bestDrive) + ":" + rest
+}
+
+// hasPathPrefix 报告路径p是否以prefix开头，且前缀在路径分隔符或结尾处结束
+// 比较不区分大小写，正反斜杠视为相同
+func hasPathPrefix(p, prefix string) bool {
+	if len(p) < len(prefix) {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		a, b := p[i], prefix[i]
+		if isSeparator(a) && isSeparator(b) {
+			continue
+		}
+		if lower(a) != lower(b) {
+			return false
+		}
+	}
+	return len(p) == len(prefix) || isSeparator(p[len(prefix)])
+}
+
+// isSeparator 报告字节是否为路径分隔符
+func isSeparator(c byte) bool {
+	return c == '\\' || c == '/'
+}
+
+// upper 将ASCII小写字母转换为大写
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// lower 将ASCII大写字母转换为小写
+func lower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c - 'A' + 'a'
+	}
+	return c
+}
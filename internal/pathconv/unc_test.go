@@ -0,0 +1,165 @@
+package pathconv
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+// newUNCTestConverter 创建使用固定驱动器映射的转换器
+func newUNCTestConverter(t *testing.T, mode UNCMode) *PathConverter {
+	t.Helper()
+	r, err := NewStaticResolver(map[string]string{
+		"Z:": `\\fileserver\builds`,
+		"y":  `\\FileServer\builds\nightly\`,
+		"X:": `//nas/share`,
+	})
+	if err != nil {
+		t.Fatalf("NewStaticResolver failed: %v", err)
+	}
+	pc := newTestConverter()
+	pc.SetUNCResolution(mode, r)
+	return pc
+}
+
+func TestConvertTo_DriveToUNC(t *testing.T) {
+	pc := newUNCTestConverter(t, UNCModeToUNC)
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`Z:\123`, DialectForward, `//fileserver/builds/123`},
+		{`z:\123\log.txt`, DialectForward, `//fileserver/builds/123/log.txt`},
+		{`Z:\123`, DialectEscaped, `"\\\\fileserver\\builds\\123"`},
+		{`Z:`, DialectForward, `//fileserver/builds`},
+		{`"X:\a b"`, DialectForward, `"//nas/share/a b"`},
+		// 未映射的驱动器和驱动器相对路径保持原有行为
+		{`C:\Users`, DialectWSL, `/mnt/c/Users`},
+		{`Z:foo\bar`, DialectForward, `Z:foo/bar`},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestConvertTo_UNCToDrive(t *testing.T) {
+	pc := newUNCTestConverter(t, UNCModeToDrive)
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`\\fileserver\builds\123`, DialectForward, `Z:/123`},
+		// 大小写不敏感，且选择最长的映射
+		{`\\FILESERVER\Builds\nightly\42`, DialectForward, `Y:/42`},
+		{`\\fileserver\builds`, DialectWSL, `/mnt/z/`},
+		{`\\nas\share\a`, DialectMSYS, `/x/a`},
+		// 前缀必须在路径分隔符处结束
+		{`\\fileserver\builds2\x`, DialectForward, `//fileserver/builds2/x`},
+		{`\\other\share\x`, DialectForward, `//other/share/x`},
+		{`C:\Users`, DialectForward, `C:/Users`},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestConvertTo_UNCModeNone(t *testing.T) {
+	pc := newUNCTestConverter(t, UNCModeNone)
+	if got := pc.Convert(`Z:\123`); got != `Z:/123` {
+		t.Errorf("Convert = %q, want Z:/123", got)
+	}
+}
+
+func TestNewStaticResolver_Invalid(t *testing.T) {
+	tests := []map[string]string{
+		{"ZZ": `\\srv\share`},
+		{"1:": `\\srv\share`},
+		{"Z:": `C:\share`},
+		{"Z:": `\\`},
+	}
+	for _, table := range tests {
+		if _, err := NewStaticResolver(table); err == nil {
+			t.Errorf("NewStaticResolver(%v) expected error", table)
+		}
+	}
+}
+
+func TestChainResolvers(t *testing.T) {
+	first, _ := NewStaticResolver(map[string]string{"Z": `\\a\one`})
+	second, _ := NewStaticResolver(map[string]string{"Z": `\\b\two`, "Y": `\\b\three`})
+	r := ChainResolvers(first, nil, second)
+
+	if got, _ := r.Lookup('Z'); got != `\\a\one` {
+		t.Errorf("Lookup(Z) = %q, want the first resolver's mapping", got)
+	}
+	if got, _ := r.Lookup('y'); got != `\\b\three` {
+		t.Errorf("Lookup(y) = %q", got)
+	}
+	if _, ok := r.Lookup('Q'); ok {
+		t.Error("Lookup(Q) should miss")
+	}
+}
+
+func TestSnapshotResolver(t *testing.T) {
+	var queries atomic.Int32
+	clk := clock.NewFake(time.Unix(0, 0))
+	r := NewSnapshotResolver(func() map[byte]string {
+		queries.Add(1)
+		return map[byte]string{'Z': `\\fileserver\builds`}
+	}, time.Second, time.Minute, clk)
+
+	pc := newTestConverter()
+	pc.SetUNCResolution(UNCModeToDrive, r)
+	if got := pc.ConvertTo(`\\fileserver\builds\a`, DialectForward); got != "Z:/a" {
+		t.Errorf("ConvertTo = %q", got)
+	}
+	pc.ConvertTo(`\\fileserver\builds\b`, DialectForward)
+	if got := queries.Load(); got != 1 {
+		t.Errorf("the drive snapshot should be queried once and cached, got %d queries", got)
+	}
+	clk.Advance(time.Minute)
+	if _, ok := r.Lookup('z'); !ok || queries.Load() != 2 {
+		t.Errorf("an expired snapshot should be queried again, got %d queries", queries.Load())
+	}
+}
+
+func TestSnapshotResolver_Timeout(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	started, release := make(chan struct{}), make(chan struct{})
+	r := NewSnapshotResolver(func() map[byte]string {
+		close(started)
+		<-release
+		return map[byte]string{'Z': `\\fileserver\builds`}
+	}, 200*time.Millisecond, time.Minute, clk)
+
+	done := make(chan bool)
+	go func() {
+		_, ok := r.Lookup('Z')
+		done <- ok
+	}()
+	<-started
+	clk.Advance(200 * time.Millisecond)
+	if <-done {
+		t.Error("a query that times out should be treated as no mapping")
+	}
+	close(release)
+}
+
+func TestParseUNCMode(t *testing.T) {
+	for in, want := range map[string]UNCMode{"": UNCModeNone, "To-UNC": UNCModeToUNC, "to-drive": UNCModeToDrive} {
+		if got, err := ParseUNCMode(in); err != nil || got != want {
+			t.Errorf("ParseUNCMode(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseUNCMode("both"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
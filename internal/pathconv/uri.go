@@ -0,0 +1,124 @@
+package pathconv
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// uriPathSafe 路径中不需要百分号编码的字符之外的pchar字符（RFC 3986 第3.3节）
+// 字母、数字和 -._~ 总是保持原样，其余字符（包括空格、%、#、? 和非ASCII字符）都按UTF-8字节编码
+const uriPathSafe = "!$&'()*+,;=:@"
+
+// splitUNC 拆分UNC路径
+// 例如 \\server\share\dir 拆分为 "server" 和 "\share\dir"
+// 参数:
+//   - p: 路径
+//
+// 返回值:
+//   - string: 服务器名称
+//   - string: 服务器名称之后的部分，以分隔符开头或为空
+//   - bool: 路径是否为UNC路径，\\?\ 和 \\.\ 开头的命名空间路径不是UNC路径
+func splitUNC(p string) (string, string, bool) {
+	if len(p) < 3 || !isSeparator(p[0]) || !isSeparator(p[1]) || isSeparator(p[2]) || hasNamespacePrefix(p) {
+		return "", "", false
+	}
+	rest := p[2:]
+	end := strings.IndexAny(rest, `\/`)
+	if end < 0 {
+		return rest, "", true
+	}
+	return rest[:end], rest[end:], true
+}
+
+// toSMBURI 将UNC路径转换为smb:// URI，如 \\server\share\a b → smb://server/share/a%20b
+// 非UNC路径无法表示为smb URI，返回false
+func toSMBURI(p string) (string, bool) {
+	server, rest, ok := splitUNC(p)
+	if !ok {
+		return "", false
+	}
+	return "smb://" + escapeURIPath(server) + escapeURIPath(toSlash(rest)), true
+}
+
+// toFileURI 按RFC 8089将路径转换为file URI
+// UNC路径以服务器名称作为authority，如 file://server/share/dir；
+// 盘符路径使用空authority，如 file:///C:/dir
+// 其他路径（如相对路径）无法表示为file URI，返回false
+func toFileURI(p string) (string, bool) {
+	if server, rest, ok := splitUNC(p); ok {
+		return "file://" + escapeURIPath(server) + escapeURIPath(toSlash(rest)), true
+	}
+	if _, rest, ok := splitDrive(p); ok {
+		// 保留盘符原有的大小写
+		return "file:///" + p[:2] + escapeURIPath(toSlash(rest)), true
+	}
+	return "", false
+}
+
+// escapeURIPath 对URI路径进行百分号编码，保留 / 分隔符
+func escapeURIPath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if c == '/' || isASCIILetter(c) || (c >= '0' && c <= '9') ||
+			strings.IndexByte("-._~", c) >= 0 || strings.IndexByte(uriPathSafe, c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// ParseURI 将smb://或file:// URI解析为Windows路径，是smb和file-uri输出格式的逆操作
+// 支持的形式:
+//   - smb://server/share/dir → \\server\share\dir
+//   - file://server/share/dir → \\server\share\dir
+//   - file:///C:/dir、file://localhost/C:/dir → C:\dir
+//
+// 参数:
+//   - uri: URI文本
+//
+// 返回值:
+//   - string: Windows路径
+//   - error: URI格式无效或无法表示为Windows路径时返回错误
+func ParseURI(uri string) (string, error) {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return "", fmt.Errorf("无效的URI: %s", uri)
+	}
+	scheme = strings.ToLower(scheme)
+	if scheme != "smb" && scheme != "file" {
+		return "", fmt.Errorf("不支持的URI协议: %s", scheme)
+	}
+
+	host, path := rest, ""
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		host, path = rest[:i], rest[i:]
+	}
+	if strings.ContainsAny(path, "?#") {
+		return "", fmt.Errorf("URI中不能包含查询或片段: %s", uri)
+	}
+	host, err := url.PathUnescape(host)
+	if err != nil {
+		return "", fmt.Errorf("无效的URI: %v", err)
+	}
+	path, err = url.PathUnescape(path)
+	if err != nil {
+		return "", fmt.Errorf("无效的URI: %v", err)
+	}
+	winPath := strings.ReplaceAll(path, "/", `\`)
+
+	if scheme == "file" && (host == "" || strings.EqualFold(host, "localhost")) {
+		// 本机文件：路径形如 /C:/dir
+		if len(winPath) >= 3 && winPath[0] == '\\' && winPath[2] == ':' && isASCIILetter(winPath[1]) {
+			return winPath[1:], nil
+		}
+		return "", fmt.Errorf("无法将URI转换为Windows路径: %s", uri)
+	}
+	if host == "" {
+		return "", fmt.Errorf("URI缺少服务器名称: %s", uri)
+	}
+	return `\\` + host + winPath, nil
+}
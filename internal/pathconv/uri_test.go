@@ -0,0 +1,131 @@
+package pathconv
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestConvertTo_URIDialects(t *testing.T) {
+	pc := newTestConverter()
+	tests := []struct {
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{`\\server\share\dir\file.txt`, DialectSMB, `smb://server/share/dir/file.txt`},
+		{`\\server\share\My Docs\报告.docx`, DialectSMB, `smb://server/share/My%20Docs/%E6%8A%A5%E5%91%8A.docx`},
+		{`\\server\share\100%#1?.txt`, DialectSMB, `smb://server/share/100%25%231%3F.txt`},
+		{`\\server`, DialectSMB, `smb://server`},
+		// smb 只适用于UNC路径，盘符路径回退为正斜杠格式
+		{`C:\Users\me`, DialectSMB, `C:/Users/me`},
+		{`\\server\share\a b`, DialectFileURI, `file://server/share/a%20b`},
+		{`C:\Program Files\app`, DialectFileURI, `file:///C:/Program%20Files/app`},
+		{`d:\`, DialectFileURI, `file:///d:/`},
+		{`"C:\a b"`, DialectFileURI, `"file:///C:/a%20b"`},
+		{`rel\path`, DialectFileURI, `rel/path`},
+	}
+	for _, tt := range tests {
+		if got := pc.ConvertTo(tt.input, tt.dialect); got != tt.want {
+			t.Errorf("ConvertTo(%q, %s) = %q, want %q", tt.input, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{`smb://server/share/My%20Docs`, `\\server\share\My Docs`},
+		{`SMB://server/share`, `\\server\share`},
+		{`file://server/share/a%20b`, `\\server\share\a b`},
+		{`file:///C:/Program%20Files/app`, `C:\Program Files\app`},
+		{`file://localhost/C:/x`, `C:\x`},
+		{`file:///C:/%E6%8A%A5%E5%91%8A`, `C:\报告`},
+	}
+	for _, tt := range tests {
+		got, err := ParseURI(tt.uri)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseURI(%q) = %q, %v; want %q", tt.uri, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{`C:\x`, `http://host/x`, `smb:///share`, `file:///usr/bin`, `file:///C:/a%zz`, `smb://srv/a?b`} {
+		if got, err := ParseURI(bad); err == nil {
+			t.Errorf("ParseURI(%q) = %q, expected error", bad, got)
+		}
+	}
+}
+
+// windowsPath 随机生成的Windows绝对路径，用于往返属性测试
+type windowsPath string
+
+// pathRunes 生成路径时使用的字符，包含需要编码的空格、%、#、?、+ 以及非ASCII字符
+var pathRunes = []rune("abcXYZ019 -_.~!$&'()+,;=@%#?[]^{}`中文éü😀")
+
+// Generate 实现quick.Generator，生成盘符路径或UNC路径
+func (windowsPath) Generate(r *rand.Rand, size int) reflect.Value {
+	segment := func() string {
+		n := 1 + r.Intn(8)
+		var b strings.Builder
+		for i := 0; i < n; i++ {
+			b.WriteRune(pathRunes[r.Intn(len(pathRunes))])
+		}
+		return b.String()
+	}
+
+	var b strings.Builder
+	if r.Intn(2) == 0 {
+		// \\?\ 和 \\.\ 开头的是命名空间路径而不是UNC路径，服务器名称中不生成 ? 也不使用 .
+		server := strings.ReplaceAll(segment(), "?", "q")
+		if server == "." {
+			server = "dot"
+		}
+		b.WriteString(`\\` + server)
+	} else {
+		b.WriteByte(byte('A' + r.Intn(26)))
+		b.WriteByte(':')
+	}
+	for i := 0; i < r.Intn(size%6+1); i++ {
+		b.WriteString(`\` + segment())
+	}
+	if r.Intn(4) == 0 {
+		b.WriteString(`\`)
+	}
+	return reflect.ValueOf(windowsPath(b.String()))
+}
+
+func TestURIDialects_RoundTrip(t *testing.T) {
+	isURIChar := func(c rune) bool {
+		return c < 0x80 && c > ' ' && !strings.ContainsRune(`"<>\^{}|`+"`", c)
+	}
+
+	roundTrip := func(d Dialect) func(p windowsPath) bool {
+		return func(p windowsPath) bool {
+			in := string(p)
+			if _, _, unc := splitUNC(in); d == DialectSMB && !unc {
+				return true
+			}
+			uri := formatPath(in, d)
+			if strings.IndexFunc(uri, func(c rune) bool { return !isURIChar(c) }) >= 0 {
+				t.Logf("%s output %q contains characters that must be encoded", d, uri)
+				return false
+			}
+			back, err := ParseURI(uri)
+			if err != nil || back != in {
+				t.Logf("%q -> %q -> %q (%v)", in, uri, back, err)
+				return false
+			}
+			return true
+		}
+	}
+
+	for _, d := range []Dialect{DialectSMB, DialectFileURI} {
+		if err := quick.Check(roundTrip(d), &quick.Config{MaxCount: 2000}); err != nil {
+			t.Errorf("%s round trip: %v", d, err)
+		}
+	}
+}
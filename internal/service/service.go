@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultName 默认的Windows服务名称
+const DefaultName = "win-path-convert"
+
+// InstanceName 返回按用户服务模板在指定登录会话中创建的实例名称
+// 参数:
+//   - template: 模板服务名称
+//   - luid: 用户登录会话的LUID
+//
+// 返回值:
+//   - string: 实例名称，如 win-path-convert_4a1f2
+func InstanceName(template string, luid uint64) string {
+	return fmt.Sprintf("%s_%x", template, luid)
+}
+
+// IsInstanceName 报告服务名称是否为按用户服务模板创建的实例
+// SCM在用户登录时为模板创建名为 <模板名>_<登录会话LUID的十六进制> 的实例，如 win-path-convert_4a1f2
+// 参数:
+//   - template: 模板服务名称
+//   - name: 要检查的服务名称
+//
+// 返回值:
+//   - bool: 是否为该模板的实例
+func IsInstanceName(template, name string) bool {
+	suffix, ok := strings.CutPrefix(strings.ToLower(name), strings.ToLower(template)+"_")
+	if !ok || suffix == "" {
+		return false
+	}
+	for _, c := range suffix {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Control 服务控制管理器（SCM）发来的控制请求
+type Control int
+
+const (
+	ControlStop        Control = iota // 停止服务
+	ControlShutdown                   // 系统关机
+	ControlPause                      // 暂停服务
+	ControlContinue                   // 继续运行已暂停的服务
+	ControlInterrogate                // 查询当前状态
+)
+
+// Status 服务向SCM报告的状态
+type Status int
+
+const (
+	StatusRunning     Status = iota // 正在运行
+	StatusPaused                    // 已暂停
+	StatusStopPending               // 正在停止
+)
+
+// String 返回状态名称
+func (s Status) String() string {
+	switch s {
+	case StatusRunning:
+		return "running"
+	case StatusPaused:
+		return "paused"
+	case StatusStopPending:
+		return "stop-pending"
+	default:
+		return "unknown"
+	}
+}
+
+// Action 响应控制请求时需要对应用程序执行的操作
+type Action int
+
+const (
+	ActionNone   Action = iota // 无需操作
+	ActionStop                 // 停止应用程序
+	ActionPause                // 暂停自动转换
+	ActionResume               // 恢复自动转换
+)
+
+// Runner 以服务方式运行的应用程序
+type Runner interface {
+	// Run 运行应用程序，直到ctx被取消或应用程序自行退出
+	Run(ctx context.Context) error
+	// Pause 暂停自动转换
+	Pause()
+	// Resume 恢复自动转换
+	Resume()
+}
+
+// Target 控制请求作用的对象
+type Target interface {
+	Stop()
+	Pause()
+	Resume()
+}
+
+// Transition 根据当前状态和控制请求计算应执行的操作与新状态
+// 重复的请求（例如已暂停时再次暂停）不执行任何操作；正在停止时忽略除查询外的所有请求
+// 参数:
+//   - s: 当前状态
+//   - c: 控制请求
+//
+// 返回值:
+//   - Action: 需要执行的操作
+//   - Status: 处理请求后的状态
+func Transition(s Status, c Control) (Action, Status) {
+	if s == StatusStopPending {
+		return ActionNone, s
+	}
+
+	switch c {
+	case ControlStop, ControlShutdown:
+		return ActionStop, StatusStopPending
+	case ControlPause:
+		if s == StatusRunning {
+			return ActionPause, StatusPaused
+		}
+	case ControlContinue:
+		if s == StatusPaused {
+			return ActionResume, StatusRunning
+		}
+	}
+	return ActionNone, s
+}
+
+// Apply 处理控制请求：计算状态转换并对目标执行相应操作
+// 参数:
+//   - t: 控制请求作用的对象
+//   - s: 当前状态
+//   - c: 控制请求
+//
+// 返回值:
+//   - Status: 处理请求后的状态
+func Apply(t Target, s Status, c Control) Status {
+	action, next := Transition(s, c)
+	switch action {
+	case ActionStop:
+		t.Stop()
+	case ActionPause:
+		t.Pause()
+	case ActionResume:
+		t.Resume()
+	}
+	return next
+}
+
+// runnerTarget 将控制请求作用到Runner上，停止操作通过取消上下文实现
+type runnerTarget struct {
+	runner Runner
+	cancel context.CancelFunc
+}
+
+// Stop 取消Runner的上下文
+func (t runnerTarget) Stop() { t.cancel() }
+
+// Pause 暂停Runner
+func (t runnerTarget) Pause() { t.runner.Pause() }
+
+// Resume 恢复Runner
+func (t runnerTarget) Resume() { t.runner.Resume() }
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		status     Status
+		control    Control
+		wantAction Action
+		wantStatus Status
+	}{
+		{StatusRunning, ControlPause, ActionPause, StatusPaused},
+		{StatusRunning, ControlContinue, ActionNone, StatusRunning},
+		{StatusRunning, ControlStop, ActionStop, StatusStopPending},
+		{StatusRunning, ControlShutdown, ActionStop, StatusStopPending},
+		{StatusRunning, ControlInterrogate, ActionNone, StatusRunning},
+		{StatusPaused, ControlPause, ActionNone, StatusPaused},
+		{StatusPaused, ControlContinue, ActionResume, StatusRunning},
+		{StatusPaused, ControlStop, ActionStop, StatusStopPending},
+		{StatusStopPending, ControlContinue, ActionNone, StatusStopPending},
+		{StatusStopPending, ControlStop, ActionNone, StatusStopPending},
+	}
+	for _, tt := range tests {
+		action, status := Transition(tt.status, tt.control)
+		if action != tt.wantAction || status != tt.wantStatus {
+			t.Errorf("Transition(%v, %d) = (%d, %v), want (%d, %v)",
+				tt.status, tt.control, action, status, tt.wantAction, tt.wantStatus)
+		}
+	}
+}
+
+// recordingRunner 记录收到的操作
+type recordingRunner struct {
+	calls []string
+}
+
+func (r *recordingRunner) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (r *recordingRunner) Pause()  { r.calls = append(r.calls, "pause") }
+func (r *recordingRunner) Resume() { r.calls = append(r.calls, "resume") }
+
+func TestApply_DrivesRunner(t *testing.T) {
+	runner := &recordingRunner{}
+	ctx, cancel := context.WithCancel(context.Background())
+	target := runnerTarget{runner: runner, cancel: cancel}
+
+	status := StatusRunning
+	for _, c := range []Control{ControlPause, ControlPause, ControlContinue, ControlInterrogate, ControlStop, ControlContinue} {
+		status = Apply(target, status, c)
+	}
+
+	if want := []string{"pause", "resume"}; !reflect.DeepEqual(runner.calls, want) {
+		t.Errorf("runner calls = %v, want %v", runner.calls, want)
+	}
+	if status != StatusStopPending {
+		t.Errorf("final status = %v, want %v", status, StatusStopPending)
+	}
+	select {
+	case <-ctx.Done():
+	default:
+		t.Error("expected Stop to cancel the runner context")
+	}
+	if err := runner.Run(ctx); err != nil {
+		t.Errorf("Run returned %v after stop", err)
+	}
+}
+
+func TestIsInstanceName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"win-path-convert_4a1f2", true},
+		{"Win-Path-Convert_4A1F2", true},
+		{"win-path-convert", false},
+		{"win-path-convert_", false},
+		{"win-path-convert_old", false},
+		{"win-path-convert-2_4a1f2", false},
+	}
+	for _, tt := range tests {
+		if got := IsInstanceName(DefaultName, tt.name); got != tt.want {
+			t.Errorf("IsInstanceName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInstanceName(t *testing.T) {
+	name := InstanceName(DefaultName, 0x4a1f2)
+	if name != "win-path-convert_4a1f2" {
+		t.Errorf("InstanceName = %q", name)
+	}
+	if !IsInstanceName(DefaultName, name) {
+		t.Errorf("IsInstanceName(%q) = false", name)
+	}
+}
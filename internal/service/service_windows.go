@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// serviceUserOwnProcess 按用户服务模板的类型（SERVICE_USER_OWN_PROCESS），x/sys未定义
+// SCM在每个用户登录时以该用户的身份、在其会话中启动一个实例，因此可以访问剪贴板、热键和托盘
+const serviceUserOwnProcess = 0x50
+
+// accepted 服务接受的控制请求
+const accepted = svc.AcceptStop | svc.AcceptShutdown | svc.AcceptPauseAndContinue
+
+// stopWaitHint 停止服务时告知SCM的预计等待时间
+const stopWaitHint = 5 * time.Second
+
+// handler 实现svc.Handler，将SCM的控制请求转发给Runner
+type handler struct {
+	runner Runner
+}
+
+// Execute 服务主函数，由SCM在服务启动时调用
+func (h *handler) Execute(args []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- h.runner.Run(ctx) }()
+
+	target := runnerTarget{runner: h.runner, cancel: cancel}
+	status := StatusRunning
+	changes <- toSvcStatus(status)
+
+	for {
+		select {
+		case err := <-done:
+			// 应用程序退出（收到停止请求或通过quit命令自行退出）
+			if err != nil {
+				return true, 1
+			}
+			return false, 0
+		case req := <-requests:
+			control, ok := fromSvcCmd(req.Cmd)
+			if !ok {
+				continue
+			}
+			if control == ControlInterrogate {
+				changes <- req.CurrentStatus
+				continue
+			}
+			status = Apply(target, status, control)
+			changes <- toSvcStatus(status)
+		}
+	}
+}
+
+// fromSvcCmd 将svc控制命令转换为Control
+func fromSvcCmd(cmd svc.Cmd) (Control, bool) {
+	switch cmd {
+	case svc.Stop:
+		return ControlStop, true
+	case svc.Shutdown:
+		return ControlShutdown, true
+	case svc.Pause:
+		return ControlPause, true
+	case svc.Continue:
+		return ControlContinue, true
+	case svc.Interrogate:
+		return ControlInterrogate, true
+	default:
+		return 0, false
+	}
+}
+
+// toSvcStatus 将Status转换为报告给SCM的状态
+func toSvcStatus(s Status) svc.Status {
+	switch s {
+	case StatusPaused:
+		return svc.Status{State: svc.Paused, Accepts: accepted}
+	case StatusStopPending:
+		return svc.Status{State: svc.StopPending, WaitHint: uint32(stopWaitHint / time.Millisecond)}
+	default:
+		return svc.Status{State: svc.Running, Accepts: accepted}
+	}
+}
+
+// IsService 报告当前进程是否由SCM以服务方式启动
+func IsService() bool {
+	ok, err := svc.IsWindowsService()
+	return err == nil && ok
+}
+
+// Run 以服务方式运行，直到服务停止
+// 参数:
+//   - name: 服务名称
+//   - r: 应用程序
+//
+// 返回值:
+//   - error: 无法连接SCM时返回错误
+func Run(name string, r Runner) error {
+	if err := svc.Run(name, &handler{runner: r}); err != nil {
+		return fmt.Errorf("服务运行失败: %v", err)
+	}
+	return nil
+}
+
+// Install 注册按用户服务模板
+// 模板本身不会运行，用户下次登录时SCM为其创建并启动实例
+// 参数:
+//   - name: 服务名称
+//   - displayName: 服务显示名称
+//   - exe: 程序路径
+//   - args: SCM启动程序时传入的参数
+//
+// 返回值:
+//   - error: 服务已存在或没有管理员权限时返回错误
+func Install(name, displayName, exe string, args ...string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("无法连接服务控制管理器（需要管理员权限）: %v", err)
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(name); err == nil {
+		s.Close()
+		return fmt.Errorf("服务 %s 已存在", name)
+	}
+
+	s, err := m.CreateService(name, exe, mgr.Config{
+		ServiceType: serviceUserOwnProcess,
+		DisplayName: displayName,
+		Description: "自动将剪贴板中的Windows路径转换为其他格式",
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		return fmt.Errorf("无法创建服务: %v", err)
+	}
+	s.Close()
+	return nil
+}
+
+// Remove 删除服务模板及已创建的实例
+// 参数:
+//   - name: 服务名称
+//
+// 返回值:
+//   - error: 服务不存在或没有管理员权限时返回错误
+func Remove(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("无法连接服务控制管理器（需要管理员权限）: %v", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("无法打开服务 %s: %v", name, err)
+	}
+	defer s.Close()
+	if err := s.Delete(); err != nil {
+		return fmt.Errorf("无法删除服务: %v", err)
+	}
+
+	// 实例随模板删除后不再启动；正在运行的实例在停止后由SCM移除
+	instances, _ := listInstances(m, name)
+	for _, inst := range instances {
+		if is, err := m.OpenService(inst); err == nil {
+			is.Control(svc.Stop)
+			is.Delete()
+			is.Close()
+		}
+	}
+	return nil
+}
+
+// Start 启动当前用户的服务实例
+// 参数:
+//   - name: 服务名称
+//
+// 返回值:
+//   - error: 找不到实例或启动失败时返回错误
+func Start(name string) error {
+	return withUserInstance(name, func(s *mgr.Service) error {
+		if err := s.Start(); err != nil {
+			return fmt.Errorf("无法启动服务: %v", err)
+		}
+		return nil
+	})
+}
+
+// Stop 停止当前用户的服务实例
+// 参数:
+//   - name: 服务名称
+//
+// 返回值:
+//   - error: 找不到实例或停止失败时返回错误
+func Stop(name string) error {
+	return withUserInstance(name, func(s *mgr.Service) error {
+		if _, err := s.Control(svc.Stop); err != nil {
+			return fmt.Errorf("无法停止服务: %v", err)
+		}
+		return nil
+	})
+}
+
+// withUserInstance 打开属于当前用户的服务实例并执行操作
+// 运行中的实例按进程所属的用户判断，已停止的实例按名称中的登录会话LUID判断；
+// 同一用户有多个实例时优先选择在当前会话中运行的实例，没有属于当前用户的实例时返回错误
+func withUserInstance(name string, f func(s *mgr.Service) error) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("无法连接服务控制管理器（需要管理员权限）: %v", err)
+	}
+	defer m.Disconnect()
+
+	instances, err := listInstances(m, name)
+	if err != nil {
+		return err
+	}
+	user, err := currentCaller(name)
+	if err != nil {
+		return err
+	}
+
+	var owned []*mgr.Service
+	defer func() {
+		for _, s := range owned {
+			s.Close()
+		}
+	}()
+	for _, inst := range instances {
+		s, err := m.OpenService(inst)
+		if err != nil {
+			continue
+		}
+		pid := processID(s)
+		if (pid != 0 && user.ownsProcess(pid)) || (pid == 0 && user.ownsName(inst)) {
+			owned = append(owned, s)
+			if pid != 0 && processSession(pid) == user.session {
+				return f(s)
+			}
+			continue
+		}
+		s.Close()
+	}
+
+	switch len(owned) {
+	case 0:
+		return fmt.Errorf("找不到属于当前用户的服务 %s 实例，注册服务后需要注销并重新登录", name)
+	case 1:
+		return f(owned[0])
+	}
+	return fmt.Errorf("当前用户有多个服务 %s 实例，请在服务管理器中操作", name)
+}
+
+// caller 执行服务命令的用户
+type caller struct {
+	sid       *windows.SID // 用户SID
+	session   uint32       // 当前会话ID
+	instances []string     // 该用户登录会话对应的实例名称，管理员的提升令牌与筛选令牌属于不同的登录会话
+}
+
+// tokenStatistics 对应TOKEN_STATISTICS结构，x/sys未定义
+type tokenStatistics struct {
+	TokenID            windows.LUID
+	AuthenticationID   windows.LUID // 登录会话的LUID，按用户服务实例以其作为名称后缀
+	ExpirationTime     int64
+	TokenType          uint32
+	ImpersonationLevel uint32
+	DynamicCharged     uint32
+	DynamicAvailable   uint32
+	GroupCount         uint32
+	PrivilegeCount     uint32
+	ModifiedID         windows.LUID
+}
+
+// currentCaller 查询当前用户的SID、会话和登录会话对应的实例名称
+func currentCaller(template string) (*caller, error) {
+	token := windows.GetCurrentProcessToken()
+	u, err := token.GetTokenUser()
+	if err != nil {
+		return nil, fmt.Errorf("无法获取当前用户: %v", err)
+	}
+	c := &caller{sid: u.User.Sid}
+	if err := windows.ProcessIdToSessionId(windows.GetCurrentProcessId(), &c.session); err != nil {
+		return nil, fmt.Errorf("无法获取当前会话: %v", err)
+	}
+	if luid, ok := logonSession(token); ok {
+		c.instances = append(c.instances, InstanceName(template, luid))
+	}
+	if linked, err := token.GetLinkedToken(); err == nil {
+		if luid, ok := logonSession(linked); ok {
+			c.instances = append(c.instances, InstanceName(template, luid))
+		}
+		linked.Close()
+	}
+	return c, nil
+}
+
+// logonSession 返回令牌所属登录会话的LUID
+func logonSession(token windows.Token) (uint64, bool) {
+	var stats tokenStatistics
+	var n uint32
+	if err := windows.GetTokenInformation(token, windows.TokenStatistics, (*byte)(unsafe.Pointer(&stats)), uint32(unsafe.Sizeof(stats)), &n); err != nil {
+		return 0, false
+	}
+	return uint64(uint32(stats.AuthenticationID.HighPart))<<32 | uint64(stats.AuthenticationID.LowPart), true
+}
+
+// ownsName 报告实例名称是否对应该用户的登录会话
+func (c *caller) ownsName(instance string) bool {
+	for _, name := range c.instances {
+		if strings.EqualFold(name, instance) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsProcess 报告进程是否以该用户的身份运行
+func (c *caller) ownsProcess(pid uint32) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(h)
+	var token windows.Token
+	if err := windows.OpenProcessToken(h, windows.TOKEN_QUERY, &token); err != nil {
+		return false
+	}
+	defer token.Close()
+	u, err := token.GetTokenUser()
+	return err == nil && u.User.Sid.Equals(c.sid)
+}
+
+// processID 返回服务实例的进程ID，未运行时为0
+func processID(s *mgr.Service) uint32 {
+	status, err := s.Query()
+	if err != nil {
+		return 0
+	}
+	return status.ProcessId
+}
+
+// processSession 返回进程所在的会话ID，查询失败时返回一个不存在的会话
+func processSession(pid uint32) uint32 {
+	var session uint32
+	if windows.ProcessIdToSessionId(pid, &session) != nil {
+		return ^uint32(0)
+	}
+	return session
+}
+
+// listInstances 列出按用户服务模板创建的实例
+func listInstances(m *mgr.Mgr, name string) ([]string, error) {
+	names, err := m.ListServices()
+	if err != nil {
+		return nil, fmt.Errorf("无法列出服务: %v", err)
+	}
+	var instances []string
+	for _, n := range names {
+		if IsInstanceName(name, n) {
+			instances = append(instances, n)
+		}
+	}
+	return instances, nil
+}
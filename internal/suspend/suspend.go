@@ -0,0 +1,270 @@
+package suspend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+// Mode 自动转换的运行状态
+type Mode int
+
+const (
+	Active        Mode = iota // 自动转换正常工作
+	Paused                    // 手动暂停，直到显式恢复
+	SnoozedUntil              // 暂停到指定时间后自动恢复
+	SnoozedCopies             // 跳过接下来的若干次复制后自动恢复
+)
+
+// String 返回状态名称
+func (m Mode) String() string {
+	switch m {
+	case Active:
+		return "active"
+	case Paused:
+		return "paused"
+	case SnoozedUntil:
+		return "snoozed-until"
+	case SnoozedCopies:
+		return "snoozed-copies"
+	default:
+		return "unknown"
+	}
+}
+
+// State 某一时刻的暂停状态快照
+type State struct {
+	Mode            Mode      // 当前状态
+	Until           time.Time // SnoozedUntil状态下的自动恢复时间
+	RemainingCopies int       // SnoozedCopies状态下剩余需要跳过的复制次数
+}
+
+// Suspended 报告该状态下是否跳过自动转换
+func (s State) Suspended() bool {
+	return s.Mode != Active
+}
+
+// String 返回面向用户的状态描述
+func (s State) String() string {
+	switch s.Mode {
+	case Paused:
+		return "已暂停"
+	case SnoozedUntil:
+		return fmt.Sprintf("已暂停至 %s", s.Until.Format("15:04:05"))
+	case SnoozedCopies:
+		return fmt.Sprintf("已暂停，剩余 %d 次复制", s.RemainingCopies)
+	default:
+		return "运行中"
+	}
+}
+
+// Controller 管理自动转换的暂停、恢复与定时暂停
+// 所有方法都可以在多个协程中并发调用
+type Controller struct {
+	mu       sync.Mutex
+	clock    clock.Clock // 时间源，测试时可替换
+	state    State       // 当前状态
+	timer    clock.Timer // SnoozedUntil状态下的自动恢复定时器
+	onChange func(State) // 状态自动变化（定时或次数到期）时的回调
+}
+
+// NewController 创建暂停控制器，初始为Active状态
+// 参数:
+//   - c: 时间源
+//
+// 返回值:
+//   - *Controller: 控制器实例
+func NewController(c clock.Clock) *Controller {
+	return &Controller{clock: c}
+}
+
+// SetOnChange 设置自动恢复时的回调
+// 回调只在定时到期或跳过次数用完导致的自动恢复时调用，手动操作不会触发
+// 参数:
+//   - f: 回调函数，参数为变化后的状态
+func (c *Controller) SetOnChange(f func(State)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onChange = f
+}
+
+// Pause 暂停自动转换，直到调用Resume
+func (c *Controller) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(State{Mode: Paused})
+}
+
+// Resume 立即恢复自动转换，同时取消所有定时暂停
+func (c *Controller) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(State{Mode: Active})
+}
+
+// SnoozeFor 暂停自动转换指定时长，到期后自动恢复
+// 参数:
+//   - d: 暂停时长，必须大于0
+//
+// 返回值:
+//   - error: 时长无效时返回错误
+func (c *Controller) SnoozeFor(d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("暂停时长必须大于0")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	until := c.clock.Now().Add(d)
+	c.setLocked(State{Mode: SnoozedUntil, Until: until})
+	c.timer = c.clock.AfterFunc(d, func() { c.expire(until) })
+	return nil
+}
+
+// SnoozeCopies 跳过接下来的n次复制，之后自动恢复
+// 参数:
+//   - n: 跳过的复制次数，必须大于0
+//
+// 返回值:
+//   - error: 次数无效时返回错误
+func (c *Controller) SnoozeCopies(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("跳过次数必须大于0")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(State{Mode: SnoozedCopies, RemainingCopies: n})
+	return nil
+}
+
+// Allow 在每次剪贴板内容变化时调用，判断是否执行自动转换
+// SnoozedCopies状态下每次调用消耗一次跳过次数
+// 返回值:
+//   - bool: 允许转换时返回true
+func (c *Controller) Allow() bool {
+	c.mu.Lock()
+	if c.refreshLocked() {
+		// 定时暂停刚好到期，恢复后允许本次转换
+		c.notifyAndUnlock()
+		return true
+	}
+
+	switch c.state.Mode {
+	case Active:
+		c.mu.Unlock()
+		return true
+	case SnoozedCopies:
+		c.state.RemainingCopies--
+		if c.state.RemainingCopies > 0 {
+			c.mu.Unlock()
+			return false
+		}
+		// 跳过次数用完，自动恢复；本次复制仍然跳过
+		c.setLocked(State{Mode: Active})
+		c.notifyAndUnlock()
+		return false
+	default:
+		c.mu.Unlock()
+		return false
+	}
+}
+
+// State 返回当前状态快照
+func (c *Controller) State() State {
+	c.mu.Lock()
+	s := c.state
+	if c.refreshLocked() {
+		s = c.state
+		c.notifyAndUnlock()
+		return s
+	}
+	c.mu.Unlock()
+	return s
+}
+
+// expire 定时器到期时恢复自动转换
+// until用于识别定时器是否仍对应当前的定时暂停，避免旧定时器误恢复新的暂停状态
+func (c *Controller) expire(until time.Time) {
+	c.mu.Lock()
+	if c.state.Mode != SnoozedUntil || !c.state.Until.Equal(until) {
+		c.mu.Unlock()
+		return
+	}
+	c.setLocked(State{Mode: Active})
+	c.notifyAndUnlock()
+}
+
+// refreshLocked 检查定时暂停是否已过期，过期时恢复并返回true，调用方需持有锁
+// 定时器回调可能因调度延迟晚于查询执行，这里按时间直接判断以保证状态准确
+func (c *Controller) refreshLocked() bool {
+	if c.state.Mode == SnoozedUntil && !c.clock.Now().Before(c.state.Until) {
+		c.setLocked(State{Mode: Active})
+		return true
+	}
+	return false
+}
+
+// setLocked 切换状态并停止旧的定时器，调用方需持有锁
+func (c *Controller) setLocked(s State) {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.state = s
+}
+
+// notifyAndUnlock 释放锁后调用状态变化回调，调用方需持有锁
+func (c *Controller) notifyAndUnlock() {
+	f, s := c.onChange, c.state
+	c.mu.Unlock()
+	if f != nil {
+		f(s)
+	}
+}
+
+// ParseSnoozeArgs 解析定时暂停命令的参数
+// 支持以下形式:
+//   - "15": 暂停15分钟
+//   - "1h30m": 暂停指定时长（time.ParseDuration格式）
+//   - "3 copies": 跳过接下来的3次复制
+//
+// 参数:
+//   - args: 命令参数
+//
+// 返回值:
+//   - time.Duration: 暂停时长，按次数暂停时为0
+//   - int: 跳过的复制次数，按时长暂停时为0
+//   - error: 参数无效时返回错误
+func ParseSnoozeArgs(args []string) (time.Duration, int, error) {
+	switch len(args) {
+	case 1:
+		if minutes, err := strconv.Atoi(args[0]); err == nil {
+			if minutes <= 0 {
+				return 0, 0, fmt.Errorf("暂停分钟数必须大于0")
+			}
+			return time.Duration(minutes) * time.Minute, 0, nil
+		}
+		d, err := time.ParseDuration(args[0])
+		if err != nil || d <= 0 {
+			return 0, 0, fmt.Errorf("无效的暂停时长: %s", args[0])
+		}
+		return d, 0, nil
+	case 2:
+		unit := strings.ToLower(args[1])
+		if unit != "copies" && unit != "copy" {
+			return 0, 0, fmt.Errorf("无效的暂停单位: %s", args[1])
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return 0, 0, fmt.Errorf("无效的跳过次数: %s", args[0])
+		}
+		return 0, n, nil
+	default:
+		return 0, 0, fmt.Errorf("用法: snooze <分钟数|时长> 或 snooze <次数> copies")
+	}
+}
@@ -0,0 +1,145 @@
+package suspend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lyj404/win-path-convert/internal/clock"
+)
+
+func newTestController() (*Controller, *clock.Fake, *[]State) {
+	c := clock.NewFake(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	ctl := NewController(c)
+	var changes []State
+	ctl.SetOnChange(func(s State) { changes = append(changes, s) })
+	return ctl, c, &changes
+}
+
+func TestController_PauseResume(t *testing.T) {
+	ctl, _, changes := newTestController()
+
+	if !ctl.Allow() {
+		t.Fatal("expected new controller to allow conversion")
+	}
+	ctl.Pause()
+	if ctl.Allow() || !ctl.State().Suspended() {
+		t.Fatal("expected paused controller to block conversion")
+	}
+	ctl.Resume()
+	if !ctl.Allow() {
+		t.Fatal("expected resumed controller to allow conversion")
+	}
+	if len(*changes) != 0 {
+		t.Errorf("manual changes should not trigger callback, got %v", *changes)
+	}
+}
+
+func TestController_SnoozeForAutoResumes(t *testing.T) {
+	ctl, c, changes := newTestController()
+
+	if err := ctl.SnoozeFor(10 * time.Minute); err != nil {
+		t.Fatalf("SnoozeFor failed: %v", err)
+	}
+	state := ctl.State()
+	if state.Mode != SnoozedUntil || !state.Until.Equal(c.Now().Add(10*time.Minute)) {
+		t.Fatalf("unexpected state after snooze: %+v", state)
+	}
+
+	c.Advance(9 * time.Minute)
+	if ctl.Allow() {
+		t.Fatal("expected conversion to stay blocked before snooze expires")
+	}
+
+	c.Advance(time.Minute)
+	if ctl.State().Mode != Active {
+		t.Fatalf("expected controller to resume, got %v", ctl.State().Mode)
+	}
+	if len(*changes) != 1 || (*changes)[0].Mode != Active {
+		t.Errorf("expected one auto-resume callback, got %v", *changes)
+	}
+}
+
+func TestController_StaleTimerDoesNotResumeNewSnooze(t *testing.T) {
+	ctl, c, _ := newTestController()
+
+	ctl.SnoozeFor(time.Minute)
+	ctl.Pause()
+	c.Advance(time.Hour)
+	if ctl.State().Mode != Paused {
+		t.Fatalf("expected manual pause to survive old snooze timer, got %v", ctl.State().Mode)
+	}
+
+	ctl.SnoozeFor(time.Minute)
+	ctl.SnoozeFor(time.Hour)
+	c.Advance(2 * time.Minute)
+	if ctl.State().Mode != SnoozedUntil {
+		t.Fatalf("expected longer snooze to replace shorter one, got %v", ctl.State().Mode)
+	}
+}
+
+func TestController_SnoozeCopies(t *testing.T) {
+	ctl, _, changes := newTestController()
+
+	if err := ctl.SnoozeCopies(2); err != nil {
+		t.Fatalf("SnoozeCopies failed: %v", err)
+	}
+	if ctl.Allow() {
+		t.Fatal("first copy should be skipped")
+	}
+	if got := ctl.State().RemainingCopies; got != 1 {
+		t.Fatalf("expected 1 remaining copy, got %d", got)
+	}
+	if ctl.Allow() {
+		t.Fatal("second copy should be skipped")
+	}
+	if !ctl.Allow() {
+		t.Fatal("third copy should be converted")
+	}
+	if len(*changes) != 1 {
+		t.Errorf("expected one auto-resume callback, got %v", *changes)
+	}
+}
+
+func TestController_RejectsInvalidSnooze(t *testing.T) {
+	ctl, _, _ := newTestController()
+	if err := ctl.SnoozeFor(0); err == nil {
+		t.Error("expected error for zero duration")
+	}
+	if err := ctl.SnoozeCopies(-1); err == nil {
+		t.Error("expected error for negative copies")
+	}
+	if ctl.State().Mode != Active {
+		t.Error("invalid snooze should not change state")
+	}
+}
+
+func TestParseSnoozeArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		duration time.Duration
+		copies   int
+		wantErr  bool
+	}{
+		{"Minutes", []string{"15"}, 15 * time.Minute, 0, false},
+		{"Duration", []string{"1h30m"}, 90 * time.Minute, 0, false},
+		{"Copies", []string{"3", "copies"}, 0, 3, false},
+		{"Single copy", []string{"1", "copy"}, 0, 1, false},
+		{"Zero minutes", []string{"0"}, 0, 0, true},
+		{"Bad duration", []string{"soon"}, 0, 0, true},
+		{"Bad unit", []string{"3", "times"}, 0, 0, true},
+		{"No args", nil, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, n, err := ParseSnoozeArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSnoozeArgs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if d != tt.duration || n != tt.copies {
+				t.Errorf("ParseSnoozeArgs(%v) = %v, %d, want %v, %d", tt.args, d, n, tt.duration, tt.copies)
+			}
+		})
+	}
+}
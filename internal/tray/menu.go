@@ -0,0 +1,178 @@
+package tray
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxRecent 托盘菜单中显示的最近转换记录条数
+const MaxRecent = 10
+
+// maxLabelRunes 菜单项中单段路径的最大显示字符数
+const maxLabelRunes = 40
+
+// 固定菜单项的命令ID，动态菜单项从各自的基数开始编号
+const (
+	idToggle     uint32 = 1   // 暂停/恢复自动转换
+	idOpenConfig uint32 = 2   // 打开配置文件
+	idOpenLog    uint32 = 3   // 打开日志文件
+	idQuit       uint32 = 4   // 退出程序
+	idDialect    uint32 = 100 // 输出格式菜单项的起始ID
+	idRecent     uint32 = 200 // 最近转换菜单项的起始ID
+)
+
+// ActionKind 菜单项对应的动作类型
+type ActionKind int
+
+const (
+	ActionToggle     ActionKind = iota + 1 // 暂停或恢复自动转换
+	ActionSetDialect                       // 切换默认输出格式
+	ActionRecopy                           // 将历史转换结果重新复制到剪贴板
+	ActionOpenConfig                       // 打开配置文件
+	ActionOpenLog                          // 打开日志文件
+	ActionQuit                             // 退出程序
+)
+
+// Action 用户选择菜单项后需要执行的动作
+type Action struct {
+	Kind    ActionKind // 动作类型
+	Dialect string     // ActionSetDialect的目标格式
+	Text    string     // ActionRecopy需要复制的内容
+}
+
+// Item 菜单项
+// 分隔线只设置Separator；带Children的菜单项渲染为子菜单，其ID无意义
+type Item struct {
+	ID        uint32 // 命令ID，用户选择后通过Menu.Lookup查找对应动作
+	Label     string // 显示文本
+	Checked   bool   // 是否显示勾选标记
+	Disabled  bool   // 是否禁用（灰色不可点击）
+	Separator bool   // 是否为分隔线
+	Children  []Item // 子菜单项
+}
+
+// Recent 菜单中显示的一条转换记录
+type Recent struct {
+	Original  string // 原始内容
+	Converted string // 转换后的内容
+}
+
+// State 构建菜单所需的应用状态
+type State struct {
+	Status       string   // 状态描述，如"运行中"
+	Paused       bool     // 自动转换是否暂停（包括定时暂停）
+	Dialects     []string // 可选的输出格式
+	Dialect      string   // 当前默认输出格式
+	Recent       []Recent // 最近的转换记录，最新的在前
+	LogAvailable bool     // 是否配置了日志文件
+}
+
+// Menu 与平台无关的托盘菜单模型
+// Win32渲染层按Items创建弹出菜单，并用Lookup把用户选择的命令ID映射回动作
+type Menu struct {
+	Items   []Item
+	actions map[uint32]Action
+}
+
+// Build 根据应用状态构建托盘菜单
+// 参数:
+//   - s: 当前应用状态
+//
+// 返回值:
+//   - *Menu: 菜单模型
+func Build(s State) *Menu {
+	m := &Menu{actions: make(map[uint32]Action)}
+
+	toggleLabel := "暂停自动转换"
+	if s.Paused {
+		toggleLabel = "恢复自动转换"
+	}
+
+	m.Items = append(m.Items,
+		Item{Label: "状态: " + s.Status, Disabled: true},
+		Item{Separator: true},
+		m.command(idToggle, toggleLabel, Action{Kind: ActionToggle}),
+		Item{Label: "输出格式", Children: m.dialectItems(s)},
+		Item{Label: "最近转换", Children: m.recentItems(s.Recent)},
+		Item{Separator: true},
+		m.command(idOpenConfig, "打开配置文件", Action{Kind: ActionOpenConfig}),
+	)
+
+	openLog := m.command(idOpenLog, "打开日志文件", Action{Kind: ActionOpenLog})
+	openLog.Disabled = !s.LogAvailable
+	m.Items = append(m.Items,
+		openLog,
+		Item{Separator: true},
+		m.command(idQuit, "退出", Action{Kind: ActionQuit}),
+	)
+	return m
+}
+
+// Lookup 查找命令ID对应的动作
+// 参数:
+//   - id: 用户选择的命令ID
+//
+// 返回值:
+//   - Action: 对应的动作
+//   - bool: ID未知时返回false
+func (m *Menu) Lookup(id uint32) (Action, bool) {
+	a, ok := m.actions[id]
+	return a, ok
+}
+
+// Tooltip 返回托盘图标的提示文本
+// 参数:
+//   - s: 当前应用状态
+//
+// 返回值:
+//   - string: 提示文本
+func Tooltip(s State) string {
+	return fmt.Sprintf("Windows路径转换 - %s (%s)", s.Status, s.Dialect)
+}
+
+// command 创建命令菜单项并登记对应动作
+func (m *Menu) command(id uint32, label string, a Action) Item {
+	m.actions[id] = a
+	return Item{ID: id, Label: label}
+}
+
+// dialectItems 构建输出格式子菜单，当前格式带勾选标记
+func (m *Menu) dialectItems(s State) []Item {
+	items := make([]Item, 0, len(s.Dialects))
+	for i, d := range s.Dialects {
+		item := m.command(idDialect+uint32(i), d, Action{Kind: ActionSetDialect, Dialect: d})
+		item.Checked = d == s.Dialect
+		items = append(items, item)
+	}
+	return items
+}
+
+// recentItems 构建最近转换子菜单，没有记录时显示一个禁用的占位项
+func (m *Menu) recentItems(recent []Recent) []Item {
+	if len(recent) == 0 {
+		return []Item{{Label: "（无）", Disabled: true}}
+	}
+	if len(recent) > MaxRecent {
+		recent = recent[:MaxRecent]
+	}
+
+	items := make([]Item, 0, len(recent))
+	for i, r := range recent {
+		label := shorten(r.Original) + " → " + shorten(r.Converted)
+		items = append(items, m.command(idRecent+uint32(i), label, Action{Kind: ActionRecopy, Text: r.Converted}))
+	}
+	return items
+}
+
+// shorten 将过长或多行的文本压缩为适合菜单显示的单行文本
+// 按字符而不是字节截断，避免截断中文等多字节字符
+func shorten(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= maxLabelRunes {
+		return text
+	}
+	runes := []rune(text)
+	half := maxLabelRunes/2 - 1
+	return string(runes[:half]) + "…" + string(runes[len(runes)-half:])
+}
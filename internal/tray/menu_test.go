@@ -0,0 +1,142 @@
+package tray
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func testState() State {
+	return State{
+		Status:   "运行中",
+		Dialects: []string{"forward", "wsl", "original"},
+		Dialect:  "wsl",
+		Recent: []Recent{
+			{Original: `C:\b`, Converted: "C:/b"},
+			{Original: `C:\a`, Converted: "C:/a"},
+		},
+	}
+}
+
+// findItem 按显示文本递归查找菜单项
+func findItem(items []Item, label string) (Item, bool) {
+	for _, it := range items {
+		if it.Label == label {
+			return it, true
+		}
+		if found, ok := findItem(it.Children, label); ok {
+			return found, true
+		}
+	}
+	return Item{}, false
+}
+
+func TestBuild_Structure(t *testing.T) {
+	m := Build(testState())
+
+	if m.Items[0].Label != "状态: 运行中" || !m.Items[0].Disabled {
+		t.Errorf("expected disabled status header, got %+v", m.Items[0])
+	}
+	toggle, ok := findItem(m.Items, "暂停自动转换")
+	if !ok {
+		t.Fatal("expected pause item while running")
+	}
+	if a, ok := m.Lookup(toggle.ID); !ok || a.Kind != ActionToggle {
+		t.Errorf("toggle item maps to %+v, %v", a, ok)
+	}
+
+	quit, ok := findItem(m.Items, "退出")
+	if !ok {
+		t.Fatal("expected quit item")
+	}
+	if a, _ := m.Lookup(quit.ID); a.Kind != ActionQuit {
+		t.Errorf("quit item maps to %+v", a)
+	}
+
+	openLog, _ := findItem(m.Items, "打开日志文件")
+	if !openLog.Disabled {
+		t.Error("expected open log item to be disabled without a log file")
+	}
+}
+
+func TestBuild_PausedShowsResume(t *testing.T) {
+	s := testState()
+	s.Paused = true
+	s.LogAvailable = true
+	m := Build(s)
+
+	if _, ok := findItem(m.Items, "恢复自动转换"); !ok {
+		t.Error("expected resume item while paused")
+	}
+	if openLog, _ := findItem(m.Items, "打开日志文件"); openLog.Disabled {
+		t.Error("expected open log item to be enabled")
+	}
+}
+
+func TestBuild_DialectSubmenu(t *testing.T) {
+	m := Build(testState())
+	menu, ok := findItem(m.Items, "输出格式")
+	if !ok || len(menu.Children) != 3 {
+		t.Fatalf("expected dialect submenu with 3 items, got %+v", menu)
+	}
+
+	for _, item := range menu.Children {
+		if item.Checked != (item.Label == "wsl") {
+			t.Errorf("dialect %s checked = %v", item.Label, item.Checked)
+		}
+		a, ok := m.Lookup(item.ID)
+		if !ok || a.Kind != ActionSetDialect || a.Dialect != item.Label {
+			t.Errorf("dialect item %s maps to %+v, %v", item.Label, a, ok)
+		}
+	}
+}
+
+func TestBuild_RecentSubmenu(t *testing.T) {
+	s := testState()
+	for i := 0; i < 20; i++ {
+		s.Recent = append(s.Recent, Recent{Original: `C:\x`, Converted: "C:/x"})
+	}
+	m := Build(s)
+
+	menu, _ := findItem(m.Items, "最近转换")
+	if len(menu.Children) != MaxRecent {
+		t.Fatalf("expected %d recent items, got %d", MaxRecent, len(menu.Children))
+	}
+	first := menu.Children[0]
+	if first.Label != `C:\b → C:/b` {
+		t.Errorf("unexpected recent label %q", first.Label)
+	}
+	if a, ok := m.Lookup(first.ID); !ok || a.Kind != ActionRecopy || a.Text != "C:/b" {
+		t.Errorf("recent item maps to %+v, %v", a, ok)
+	}
+}
+
+func TestBuild_EmptyRecentPlaceholder(t *testing.T) {
+	s := testState()
+	s.Recent = nil
+	menu, _ := findItem(Build(s).Items, "最近转换")
+	if len(menu.Children) != 1 || !menu.Children[0].Disabled {
+		t.Errorf("expected a single disabled placeholder, got %+v", menu.Children)
+	}
+}
+
+func TestLookup_UnknownID(t *testing.T) {
+	if _, ok := Build(testState()).Lookup(9999); ok {
+		t.Error("expected unknown ID lookup to fail")
+	}
+}
+
+func TestShorten(t *testing.T) {
+	if got := shorten("a\nb\tc"); got != "a b c" {
+		t.Errorf("expected whitespace to collapse, got %q", got)
+	}
+
+	long := `C:\` + strings.Repeat("目录\\", 30) + "file.txt"
+	got := shorten(long)
+	if utf8.RuneCountInString(got) > maxLabelRunes || !utf8.ValidString(got) {
+		t.Errorf("shortened label too long or invalid: %q", got)
+	}
+	if !strings.HasPrefix(got, `C:\`) || !strings.HasSuffix(got, "file.txt") {
+		t.Errorf("expected head and tail to be kept, got %q", got)
+	}
+}
@@ -10,6 +10,7 @@ import (
 var (
 	User32   = windows.NewLazySystemDLL("user32.dll")   // 用户界面API，包括窗口、消息、剪贴板等
 	Kernel32 = windows.NewLazySystemDLL("kernel32.dll") // 核心系统API，包括内存管理、进程线程等
+	Shell32  = windows.NewLazySystemDLL("shell32.dll")  // 外壳API，包括通知区域图标等
 )
 
 // 剪贴板相关的Windows API函数
@@ -17,11 +18,12 @@ var (
 
 var (
 	// 剪贴板访问函数
-	ProcOpenClipboard    = User32.NewProc("OpenClipboard")    // 打开剪贴板，以阻止其他应用程序修改剪贴板内容
-	ProcCloseClipboard   = User32.NewProc("CloseClipboard")   // 关闭剪贴板，释放剪贴板访问权限
-	ProcGetClipboardData = User32.NewProc("GetClipboardData") // 获取剪贴板数据句柄
-	ProcEmptyClipboard   = User32.NewProc("EmptyClipboard")   // 清空剪贴板内容
-	ProcSetClipboardData = User32.NewProc("SetClipboardData") // 设置剪贴板数据，将数据句柄传递给剪贴板
+	ProcOpenClipboard     = User32.NewProc("OpenClipboard")     // 打开剪贴板，以阻止其他应用程序修改剪贴板内容
+	ProcCloseClipboard    = User32.NewProc("CloseClipboard")    // 关闭剪贴板，释放剪贴板访问权限
+	ProcGetClipboardData  = User32.NewProc("GetClipboardData")  // 获取剪贴板数据句柄
+	ProcEmptyClipboard    = User32.NewProc("EmptyClipboard")    // 清空剪贴板内容
+	ProcSetClipboardData  = User32.NewProc("SetClipboardData")  // 设置剪贴板数据，将数据句柄传递给剪贴板
+	ProcGetClipboardOwner = User32.NewProc("GetClipboardOwner") // 获取剪贴板所有者窗口句柄
 
 	// 内存操作函数
 	ProcGlobalAlloc   = Kernel32.NewProc("GlobalAlloc")   // 从堆中分配内存，返回可移动的内存块句柄
@@ -57,10 +59,51 @@ var (
 
 	// 线程消息处理
 	ProcPostThreadMessage = User32.NewProc("PostThreadMessageW") // 向指定线程的消息队列发送消息
+	ProcPostMessageW      = User32.NewProc("PostMessageW")       // 向指定窗口的消息队列投递消息
 
 	// 系统模块与线程管理
 	ProcGetModuleHandleW   = Kernel32.NewProc("GetModuleHandleW")   // 获取模块句柄
 	ProcGetCurrentThreadId = Kernel32.NewProc("GetCurrentThreadId") // 获取当前线程ID
+	ProcSetLastError       = Kernel32.NewProc("SetLastError")       // 设置当前线程的错误码，用于区分返回值0是否表示失败
+
+	// 控制台
+	ProcFreeConsole = Kernel32.NewProc("FreeConsole") // 使进程脱离其控制台，没有其他进程使用时控制台窗口关闭
+)
+
+// 全局热键与键盘输入相关的Windows API函数
+// 这些函数用于注册系统级热键以及模拟键盘输入（如自动粘贴）
+
+var (
+	ProcRegisterHotKey   = User32.NewProc("RegisterHotKey")   // 注册全局热键
+	ProcUnregisterHotKey = User32.NewProc("UnregisterHotKey") // 注销全局热键
+	ProcSendInput        = User32.NewProc("SendInput")        // 合成键盘鼠标输入事件
+)
+
+// 通知区域图标与弹出菜单相关的Windows API函数
+// 这些函数用于显示托盘图标并在用户点击时弹出操作菜单
+
+var (
+	ProcShellNotifyIconW       = Shell32.NewProc("Shell_NotifyIconW")     // 添加、修改或删除通知区域图标
+	ProcLoadIconW              = User32.NewProc("LoadIconW")              // 加载图标资源
+	ProcCreatePopupMenu        = User32.NewProc("CreatePopupMenu")        // 创建弹出菜单
+	ProcAppendMenuW            = User32.NewProc("AppendMenuW")            // 向菜单追加菜单项
+	ProcTrackPopupMenu         = User32.NewProc("TrackPopupMenu")         // 显示弹出菜单并等待用户选择
+	ProcDestroyMenu            = User32.NewProc("DestroyMenu")            // 销毁菜单及其子菜单
+	ProcGetCursorPos           = User32.NewProc("GetCursorPos")           // 获取鼠标光标的屏幕坐标
+	ProcSetForegroundWindow    = User32.NewProc("SetForegroundWindow")    // 将窗口设为前台窗口
+	ProcRegisterWindowMessageW = User32.NewProc("RegisterWindowMessageW") // 注册系统范围内唯一的消息
+)
+
+// 前台窗口与进程信息相关的Windows API函数
+// 这些函数用于在延迟渲染时识别请求剪贴板数据的目标程序
+
+var (
+	ProcGetForegroundWindow        = User32.NewProc("GetForegroundWindow")          // 获取前台窗口句柄
+	ProcGetWindowThreadProcessId   = User32.NewProc("GetWindowThreadProcessId")     // 获取创建窗口的线程和进程ID
+	ProcInternalGetWindowText      = User32.NewProc("InternalGetWindowText")        // 获取窗口标题，不向目标窗口发送WM_GETTEXT
+	ProcOpenProcess                = Kernel32.NewProc("OpenProcess")                // 打开进程对象
+	ProcQueryFullProcessImageNameW = Kernel32.NewProc("QueryFullProcessImageNameW") // 获取进程映像的完整路径
+	ProcCloseHandle                = Kernel32.NewProc("CloseHandle")                // 关闭内核对象句柄
 )
 
 // Windows系统常量定义
@@ -74,7 +117,51 @@ const (
 	GMEMMoveable = 0x0002 // 可移动内存标志，表示内存块可以在内存中移动
 
 	// Windows消息常量
-	WMClipboardUpdate = 0x031D // 剪贴板内容更新消息，当剪贴板内容变化时发送
-	WMDestroy         = 0x0002 // 窗口销毁消息，当窗口即将被销毁时发送
-	WMQuit            = 0x0012 // 退出消息，用于请求消息循环终止
+	WMClipboardUpdate  = 0x031D // 剪贴板内容更新消息，当剪贴板内容变化时发送
+	WMDestroy          = 0x0002 // 窗口销毁消息，当窗口即将被销毁时发送
+	WMQuit             = 0x0012 // 退出消息，用于请求消息循环终止
+	WMHotkey           = 0x0312 // 热键消息，当注册的全局热键被按下时发送
+	WMRenderFormat     = 0x0305 // 延迟渲染时，其他程序请求某种剪贴板格式的数据
+	WMRenderAllFormats = 0x0306 // 剪贴板所有者窗口即将销毁，需要渲染所有延迟的格式
+	WMDestroyClipboard = 0x0307 // 剪贴板被清空，本窗口不再是所有者
+	WMApp              = 0x8000 // 应用程序自定义消息的起始值
+
+	// 通知区域图标常量
+	NIMAdd      = 0x00000000 // 添加图标
+	NIMModify   = 0x00000001 // 修改图标
+	NIMDelete   = 0x00000002 // 删除图标
+	NIFMessage  = 0x00000001 // uCallbackMessage字段有效
+	NIFIcon     = 0x00000002 // hIcon字段有效
+	NIFTip      = 0x00000004 // szTip字段有效
+	NIFInfo     = 0x00000010 // szInfo等气泡通知字段有效
+	NIIFInfo    = 0x00000001 // 气泡通知使用信息图标
+	NIIFNoSound = 0x00000010 // 气泡通知不播放提示音
+	IDIApp      = 32512      // 系统默认应用程序图标
+	WMLButtonUp = 0x0202     // 鼠标左键释放消息
+	WMRButtonUp = 0x0205     // 鼠标右键释放消息
+	WMNull      = 0x0000     // 空消息
+
+	// 菜单常量
+	MFString     = 0x00000000 // 文本菜单项
+	MFGrayed     = 0x00000001 // 禁用菜单项
+	MFChecked    = 0x00000008 // 勾选菜单项
+	MFPopup      = 0x00000010 // 子菜单
+	MFSeparator  = 0x00000800 // 分隔线
+	TPMRightBtn  = 0x0002     // 允许用鼠标右键选择菜单项
+	TPMNoNotify  = 0x0080     // 不发送WM_COMMAND通知
+	TPMReturnCmd = 0x0100     // 返回用户选择的菜单项ID
+
+	// 进程访问权限常量
+	ProcessQueryLimitedInformation = 0x1000 // 查询进程映像名称等有限信息所需的权限
+
+	// 键盘输入常量
+	InputKeyboard  = 1      // INPUT结构体类型：键盘输入
+	KeyEventFKeyUp = 0x0002 // 按键释放标志
+
+	// 虚拟键码常量
+	VKShift   = 0x10 // Shift键
+	VKControl = 0x11 // Ctrl键
+	VKMenu    = 0x12 // Alt键
+	VKLWin    = 0x5B // 左Windows徽标键
+	VKV       = 0x56 // V键
 )